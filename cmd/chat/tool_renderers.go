@@ -38,7 +38,7 @@ func (m *ToolWeatherModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 func (m *ToolWeatherModel) View() string {
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
@@ -121,7 +121,7 @@ func (m *ToolWebSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 func (m *ToolWebSearchModel) View() string {
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
@@ -250,7 +250,7 @@ func (m *CheckboxModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 func (m *CheckboxModel) View() string {
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
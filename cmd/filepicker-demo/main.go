@@ -342,8 +342,9 @@ func (m model) setupScenario(scenarioID string) model {
 }
 
 func (m model) runFilePicker(options []filepicker.Option) model {
-	// Create the filepicker
-	picker := filepicker.New(options...)
+	// Create the filepicker, showing changes made by other programs while it runs
+	picker := filepicker.New(append(options, filepicker.WithWatch(true))...)
+	defer func() { _ = picker.Close() }()
 
 	// Run it in its own program
 	p := tea.NewProgram(picker, tea.WithAltScreen())
@@ -20,7 +20,11 @@ import (
 
 type GenericEvaluator struct {
 	symbols []string
-	help    map[string]string
+	kinds   map[string]autocomplete.SuggestionKind
+	details map[string]string
+	// snippets are call templates inserted for functions
+	snippets map[string]string
+	help     map[string]string
 }
 
 func newGenericEvaluator() *GenericEvaluator {
@@ -34,6 +38,26 @@ func newGenericEvaluator() *GenericEvaluator {
 			"contains",
 			"concat",
 		},
+		kinds: map[string]autocomplete.SuggestionKind{
+			"console":  autocomplete.KindModule,
+			"const":    autocomplete.KindKeyword,
+			"context":  autocomplete.KindVariable,
+			"continue": autocomplete.KindKeyword,
+			"count":    autocomplete.KindVariable,
+			"contains": autocomplete.KindFunction,
+			"concat":   autocomplete.KindFunction,
+		},
+		details: map[string]string{
+			"console":  "object",
+			"context":  "symbol",
+			"count":    "number",
+			"contains": "(value, query) -> bool",
+			"concat":   "(a, b) -> string",
+		},
+		snippets: map[string]string{
+			"contains": "contains(${1:value}, ${2:query})$0",
+			"concat":   "concat(${1:a}, ${2:b})$0",
+		},
 		help: map[string]string{
 			"console":  "console: object (logging namespace)",
 			"const":    "const: keyword (immutable binding)",
@@ -91,11 +115,19 @@ func (e *GenericEvaluator) CompleteInput(_ context.Context, req repl.CompletionR
 			continue
 		}
 
-		suggestions = append(suggestions, autocomplete.Suggestion{
-			Id:          symbol,
-			Value:       symbol,
-			DisplayText: symbol,
-		})
+		suggestion := autocomplete.Suggestion{
+			Id:            symbol,
+			Value:         symbol,
+			DisplayText:   symbol,
+			Kind:          e.kinds[symbol],
+			Detail:        e.details[symbol],
+			Documentation: e.help[symbol],
+		}
+		if snippet, ok := e.snippets[symbol]; ok {
+			suggestion.Value = snippet
+			suggestion.Snippet = true
+		}
+		suggestions = append(suggestions, suggestion)
 	}
 
 	return repl.CompletionResult{
@@ -261,6 +293,7 @@ func main() {
 	config.Autocomplete.TriggerKeys = []string{"tab"}
 	config.Autocomplete.AcceptKeys = []string{"enter", "tab"}
 	config.Autocomplete.FocusToggleKey = "ctrl+t"
+	config.Autocomplete.ClientFilter = true
 	config.HelpBar.Enabled = true
 	config.HelpDrawer.Enabled = true
 
@@ -11,6 +11,7 @@ import (
 	"github.com/go-go-golems/bobatea/pkg/eventbus"
 	"github.com/go-go-golems/bobatea/pkg/logutil"
 	"github.com/go-go-golems/bobatea/pkg/repl"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
 	"github.com/rs/zerolog"
 )
@@ -68,8 +69,6 @@ func (e *ThemeDemo) GetFileExtension() string {
 	return ".theme"
 }
 
-// Custom theme data removed in timeline-centric example to focus on hotkeys and streaming output.
-
 // Theme switcher application
 type ThemeSwitcherApp struct {
 	repl         *repl.Model
@@ -77,7 +76,7 @@ type ThemeSwitcherApp struct {
 	currentTheme string
 }
 
-// NewThemeSwitcherApp wires a pre-built REPL model and evaluator and adds F1-F9 hotkeys.
+// NewThemeSwitcherApp wires a pre-built REPL model and evaluator and adds F1-F6 hotkeys.
 func NewThemeSwitcherApp(model *repl.Model, evaluator *ThemeDemo) *ThemeSwitcherApp {
 	return &ThemeSwitcherApp{
 		repl:         model,
@@ -92,46 +91,28 @@ func (app *ThemeSwitcherApp) Init() tea.Cmd {
 	return app.repl.Init()
 }
 
+// themeKeys maps F-keys to the built-in themes; F1 detects one from the terminal.
+var themeKeys = map[string]string{
+	"f1": theme.Auto,
+	"f2": theme.Dark,
+	"f3": theme.Light,
+	"f4": theme.HighContrast,
+	"f5": theme.Solarized,
+	"f6": theme.SolarizedLight,
+}
+
 func (app *ThemeSwitcherApp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "f1":
-			app.currentTheme = "default"
-			app.evaluator.currentTheme = "default"
-			return app, nil
-		case "f2":
-			app.currentTheme = "dark"
-			app.evaluator.currentTheme = "dark"
-			return app, nil
-		case "f3":
-			app.currentTheme = "light"
-			app.evaluator.currentTheme = "light"
-			return app, nil
-		case "f4":
-			app.currentTheme = "cyberpunk"
-			app.evaluator.currentTheme = "cyberpunk"
-			return app, nil
-		case "f5":
-			app.currentTheme = "ocean"
-			app.evaluator.currentTheme = "ocean"
-			return app, nil
-		case "f6":
-			app.currentTheme = "forest"
-			app.evaluator.currentTheme = "forest"
-			return app, nil
-		case "f7":
-			app.currentTheme = "sunset"
-			app.evaluator.currentTheme = "sunset"
-			return app, nil
-		case "f8":
-			app.currentTheme = "monochrome"
-			app.evaluator.currentTheme = "monochrome"
-			return app, nil
-		case "f9":
-			app.currentTheme = "rainbow"
-			app.evaluator.currentTheme = "rainbow"
-			return app, nil
+		if name, ok := themeKeys[msg.String()]; ok {
+			t, err := theme.Resolve(name)
+			if err != nil {
+				return app, nil
+			}
+			app.currentTheme = t.Name
+			app.evaluator.currentTheme = t.Name
+			// the REPL restyles itself and the timeline when the ChangedMsg comes back
+			return app, theme.Use(t)
 		}
 	}
 
@@ -149,7 +130,7 @@ func (app *ThemeSwitcherApp) View() string {
 	// Add theme information at the bottom
 	themeInfo := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("243")).
-		Render(fmt.Sprintf("Current theme: %s | F1-F9: Quick theme switch | Commands: demo, colors, rainbow", app.currentTheme))
+		Render(fmt.Sprintf("Current theme: %s | F1-F6: Quick theme switch | Commands: demo, colors, rainbow", app.currentTheme))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -167,10 +148,11 @@ func main() {
 	evaluator := NewThemeDemo()
 	config := repl.Config{
 		Title:          "Theme Demo",
-		Placeholder:    "Try: demo, colors, rainbow. Use F1-F9 to change theme label.",
+		Placeholder:    "Try: demo, colors, rainbow. Use F1-F6 to switch themes.",
 		Width:          100,
 		EnableHistory:  true,
 		MaxHistorySize: 200,
+		Theme:          repl.ThemeConfig{Name: theme.Dark},
 	}
 
 	bus, err := eventbus.NewInMemoryBus()
@@ -97,6 +97,7 @@ func main() {
 	config := repl.DefaultConfig()
 	config.Title = "Math REPL"
 	config.Placeholder = "Enter math expression (e.g., 5 + 3)"
+	config.PersistHistory = true
 
 	// Wire bus and forwarders
 	bus, err := eventbus.NewInMemoryBus()
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/eventbus"
+	"github.com/go-go-golems/bobatea/pkg/logutil"
+	"github.com/go-go-golems/bobatea/pkg/repl"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/rs/zerolog"
+)
+
+// Runs the REPL against any program speaking the remote evaluator protocol, e.g.
+//
+//	go run ./examples/repl/remote-evaluator -- python3 examples/repl/remote-evaluator/python_server.py
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("usage: remote-evaluator -- <command> [args...]")
+	}
+
+	// Silence logs for TUI
+	logutil.InitTUILoggingToDiscard(zerolog.ErrorLevel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evaluator, err := repl.NewRemoteEvaluator(ctx, repl.RemoteEvaluatorConfig{
+		Command: flag.Arg(0),
+		Args:    flag.Args()[1:],
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { _ = evaluator.Close() }()
+
+	config := repl.DefaultConfig()
+	config.Title = evaluator.GetName() + " (remote)"
+
+	bus, err := eventbus.NewInMemoryBus()
+	if err != nil {
+		log.Fatal(err)
+	}
+	repl.RegisterReplToTimelineTransformer(bus)
+
+	model := repl.NewModel(evaluator, config, bus.Publisher)
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	timeline.RegisterUIForwarder(bus, p)
+
+	errs := make(chan error, 2)
+	go func() { errs <- bus.Run(ctx) }()
+	go func() { _, e := p.Run(); cancel(); errs <- e }()
+	if e := <-errs; e != nil {
+		log.Fatal(e)
+	}
+}
@@ -0,0 +1,139 @@
+#!/usr/bin/env python3
+"""Minimal remote evaluator for the bobatea REPL: evaluates Python expressions.
+
+Speaks newline-delimited JSON-RPC 2.0 on stdin/stdout (see docs/repl.md, "Remote evaluators").
+"""
+import builtins
+import codeop
+import contextlib
+import inspect
+import io
+import json
+import keyword
+import sys
+import traceback
+
+env = {}
+
+
+def send(msg):
+    msg["jsonrpc"] = "2.0"
+    sys.stdout.write(json.dumps(msg) + "\n")
+    sys.stdout.flush()
+
+
+def event(request_id, kind, props):
+    send({"method": "repl/event", "params": {"requestId": request_id, "kind": kind, "props": props}})
+
+
+def evaluate(request_id, code):
+    out = io.StringIO()
+    try:
+        with contextlib.redirect_stdout(out):
+            try:
+                result = eval(code, env)
+            except SyntaxError:
+                exec(code, env)
+                result = None
+    except Exception:
+        event(request_id, "repl_stderr", {"text": traceback.format_exc(), "is_error": True})
+        return
+    if out.getvalue():
+        event(request_id, "repl_stdout", {"text": out.getvalue()})
+    if result is not None:
+        event(request_id, "repl_result_markdown", {"markdown": "```\n" + repr(result) + "\n```"})
+
+
+def describe(name):
+    if keyword.iskeyword(name):
+        return {"value": name, "kind": "keyword"}
+    obj = env.get(name, getattr(builtins, name, None))
+    item = {"value": name, "kind": "function" if callable(obj) else "variable", "detail": type(obj).__name__}
+    doc = getattr(obj, "__doc__", None) if callable(obj) else None
+    if doc:
+        item["documentation"] = doc.strip()
+    if callable(obj):
+        call = call_snippet(name, obj)
+        if call:
+            item.update({"value": call, "displayText": name, "snippet": True})
+    return item
+
+
+def call_snippet(name, obj):
+    """Returns a call template with a placeholder per required positional argument."""
+    try:
+        params = inspect.signature(obj).parameters.values()
+    except (TypeError, ValueError):
+        return None
+    required = [
+        p.name
+        for p in params
+        if p.default is p.empty and p.kind in (p.POSITIONAL_ONLY, p.POSITIONAL_OR_KEYWORD)
+    ]
+    if not required:
+        return name + "($0)"
+    args = ", ".join("${%d:%s}" % (i + 1, arg) for i, arg in enumerate(required))
+    return name + "(" + args + ")$0"
+
+
+def complete(params):
+    text = params["input"][: params["cursorByte"]]
+    start = len(text)
+    while start > 0 and (text[start - 1].isalnum() or text[start - 1] == "_"):
+        start -= 1
+    word = text[start:]
+    names = sorted(set(list(env) + dir(__builtins__) + keyword.kwlist))
+    matches = [n for n in names if word and n.startswith(word)]
+    return {
+        "suggestions": [describe(n) for n in matches[:50]],
+        "replaceFrom": start,
+        "replaceTo": params["cursorByte"],
+        "show": bool(matches),
+    }
+
+
+def is_complete(params):
+    source = params["input"]
+    try:
+        code = codeop.compile_command(source, "<input>", "single")
+    except (SyntaxError, ValueError, OverflowError):
+        return {"status": "invalid"}
+    if code is not None:
+        return {"status": "complete"}
+    last = source.rstrip("\n").split("\n")[-1]
+    indent = last[: len(last) - len(last.lstrip())]
+    if last.rstrip().endswith(":"):
+        indent += "    "
+    return {"status": "incomplete", "indent": indent}
+
+
+def main():
+    for line in sys.stdin:
+        if not line.strip():
+            continue
+        msg = json.loads(line)
+        method, msg_id, params = msg.get("method"), msg.get("id"), msg.get("params") or {}
+        if method == "initialize":
+            send({"id": msg_id, "result": {
+                "name": "python",
+                "prompt": ">>> ",
+                "multiline": True,
+                "fileExtension": ".py",
+                "capabilities": {"completion": True, "isComplete": True},
+            }})
+        elif method == "repl/evaluate":
+            evaluate(msg_id, params["code"])
+            send({"id": msg_id, "result": {}})
+        elif method == "repl/complete":
+            send({"id": msg_id, "result": complete(params)})
+        elif method == "repl/isComplete":
+            send({"id": msg_id, "result": is_complete(params)})
+        elif method == "shutdown":
+            send({"id": msg_id, "result": {}})
+            return
+        elif msg_id is not None:
+            send({"id": msg_id, "error": {"code": -32601, "message": "method not found: " + method}})
+
+
+if __name__ == "__main__":
+    main()
@@ -14,12 +14,34 @@ type Span struct {
 	Start, End int // rune offsets, half-open [Start,End)
 }
 
+// SuggestionKind classifies what a suggestion refers to, e.g. to pick its icon.
+type SuggestionKind string
+
+const (
+	KindFunction  SuggestionKind = "function"
+	KindMethod    SuggestionKind = "method"
+	KindVariable  SuggestionKind = "variable"
+	KindConstant  SuggestionKind = "constant"
+	KindProperty  SuggestionKind = "property"
+	KindType      SuggestionKind = "type"
+	KindKeyword   SuggestionKind = "keyword"
+	KindModule    SuggestionKind = "module"
+	KindFile      SuggestionKind = "file"
+	KindDirectory SuggestionKind = "directory"
+	KindSnippet   SuggestionKind = "snippet"
+)
+
 // Suggestion is a completion result with associated metadata and highlight regions.
 type Suggestion struct {
-	Id          string // Unique identifier
-	Value       string // The actual value (for programmatic use)
-	DisplayText string // The displayed value (for viewing)
-	Submatches  []Span // Regions to highlight
+	Id            string         // Unique identifier
+	Value         string         // The actual value (for programmatic use)
+	DisplayText   string         // The displayed value (for viewing)
+	Submatches    []Span         // Regions to highlight
+	Kind          SuggestionKind // What the suggestion refers to (optional)
+	Icon          string         // Overrides the icon derived from Kind (optional)
+	Detail        string         // Short type or signature, shown next to the item (optional)
+	Documentation string         // Longer description, shown for the selected item (optional)
+	Snippet       bool           // Value uses snippet syntax (${1:placeholder}, $0) with tab stops
 }
 
 // ID returns the unique identifier of the suggestion (implements listbox.Item)
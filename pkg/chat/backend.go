@@ -44,3 +44,13 @@ type Backend interface {
 
 // BackendFinishedMsg is a message sent when the backend process has finished its operation.
 type BackendFinishedMsg struct{}
+
+// BranchingBackend is an optional Backend extension used for editing, regenerating and switching
+// conversation threads. When implemented, the chat model calls StartFromHistory instead of Start
+// for every run, passing the user/assistant messages of the active branch; the last entry is the
+// user message to answer. Backends that only implement Backend still work, but their own
+// conversation memory is not rewound when the UI forks a branch.
+type BranchingBackend interface {
+	Backend
+	StartFromHistory(ctx context.Context, history []ConversationMessage) (tea.Cmd, error)
+}
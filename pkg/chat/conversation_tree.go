@@ -0,0 +1,231 @@
+package chat
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+)
+
+// ConversationMessage is a user or assistant message on the active conversation branch.
+type ConversationMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// conversationNode mirrors one timeline entity. Entities produced in sequence form a chain;
+// editing or regenerating forks a new child next to the existing ones.
+type conversationNode struct {
+	id        timeline.EntityID
+	renderer  timeline.RendererDescriptor
+	props     map[string]any
+	startedAt time.Time
+	completed bool
+
+	parent   *conversationNode
+	children []*conversationNode
+	// activeChild is the child followed when descending into this node's branch
+	activeChild int
+}
+
+func (n *conversationNode) role() string {
+	if n.renderer.Kind != "llm_text" && n.id.Kind != "llm_text" {
+		return ""
+	}
+	r, _ := n.props["role"].(string)
+	return r
+}
+
+func (n *conversationNode) text() string {
+	t, _ := n.props["text"].(string)
+	return t
+}
+
+// conversationTree keeps every entity the chat has seen, including those on inactive
+// branches, so the timeline can be rebuilt when switching threads.
+type conversationTree struct {
+	root  *conversationNode
+	byKey map[string]*conversationNode
+	// leaf is the node new entities attach to; the active branch is root..leaf
+	leaf *conversationNode
+}
+
+func newConversationTree() *conversationTree {
+	root := &conversationNode{}
+	return &conversationTree{root: root, byKey: map[string]*conversationNode{}, leaf: root}
+}
+
+func entityKey(id timeline.EntityID) string {
+	b, _ := json.Marshal(id)
+	return string(b)
+}
+
+func (t *conversationTree) get(id timeline.EntityID) (*conversationNode, bool) {
+	n, ok := t.byKey[entityKey(id)]
+	return n, ok
+}
+
+func (t *conversationTree) onCreated(e timeline.UIEntityCreated) {
+	k := entityKey(e.ID)
+	if _, exists := t.byKey[k]; exists {
+		return
+	}
+	n := &conversationNode{
+		id:        e.ID,
+		renderer:  e.Renderer,
+		props:     copyProps(e.Props),
+		startedAt: e.StartedAt,
+		parent:    t.leaf,
+	}
+	t.leaf.children = append(t.leaf.children, n)
+	t.leaf.activeChild = len(t.leaf.children) - 1
+	t.leaf = n
+	t.byKey[k] = n
+}
+
+func (t *conversationTree) onUpdated(e timeline.UIEntityUpdated) {
+	if n, ok := t.get(e.ID); ok {
+		for k, v := range e.Patch {
+			n.props[k] = v
+		}
+	}
+}
+
+func (t *conversationTree) onCompleted(e timeline.UIEntityCompleted) {
+	if n, ok := t.get(e.ID); ok {
+		for k, v := range e.Result {
+			n.props[k] = v
+		}
+		n.completed = true
+	}
+}
+
+// onDeleted removes the node and splices its children into its parent.
+func (t *conversationTree) onDeleted(e timeline.UIEntityDeleted) {
+	n, ok := t.get(e.ID)
+	if !ok {
+		return
+	}
+	delete(t.byKey, entityKey(e.ID))
+	p := n.parent
+	idx := indexOf(p.children, n)
+	spliced := make([]*conversationNode, 0, len(p.children)-1+len(n.children))
+	spliced = append(spliced, p.children[:idx]...)
+	spliced = append(spliced, n.children...)
+	spliced = append(spliced, p.children[idx+1:]...)
+	for _, c := range n.children {
+		c.parent = p
+	}
+	p.children = spliced
+	if len(n.children) > 0 {
+		p.activeChild = idx + n.activeChild
+	} else {
+		p.activeChild = min(p.activeChild, max(len(p.children)-1, 0))
+	}
+	if t.leaf == n {
+		t.leaf = p
+		if len(n.children) > 0 {
+			t.leaf = t.descend(n.children[n.activeChild])
+		}
+	}
+}
+
+// path returns the active branch from the first entity to the leaf.
+func (t *conversationTree) path() []*conversationNode {
+	var out []*conversationNode
+	for n := t.leaf; n != nil && n != t.root; n = n.parent {
+		out = append(out, n)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// descend follows active children down to the most recent leaf of n's branch.
+func (t *conversationTree) descend(n *conversationNode) *conversationNode {
+	for len(n.children) > 0 {
+		n = n.children[min(n.activeChild, len(n.children)-1)]
+	}
+	return n
+}
+
+// forkAt makes n the attachment point, so the next entity starts a new branch under it.
+func (t *conversationTree) forkAt(n *conversationNode) { t.leaf = n }
+
+// switchThread moves to the previous (delta<0) or next (delta>0) sibling branch at the nearest
+// fork at or above n. It returns the node that became visible at the fork, or nil if there is
+// no sibling in that direction.
+func (t *conversationTree) switchThread(n *conversationNode, delta int) *conversationNode {
+	for cur := n; cur != nil && cur != t.root; cur = cur.parent {
+		p := cur.parent
+		if len(p.children) < 2 {
+			continue
+		}
+		idx := indexOf(p.children, cur) + delta
+		if idx < 0 || idx >= len(p.children) {
+			return nil
+		}
+		p.activeChild = idx
+		t.leaf = t.descend(p.children[idx])
+		return p.children[idx]
+	}
+	return nil
+}
+
+// threadPosition returns the 1-based index of n's branch among its siblings at the nearest fork.
+func (t *conversationTree) threadPosition(n *conversationNode) (int, int) {
+	for cur := n; cur != nil && cur != t.root; cur = cur.parent {
+		if len(cur.parent.children) > 1 {
+			return indexOf(cur.parent.children, cur) + 1, len(cur.parent.children)
+		}
+	}
+	return 1, 1
+}
+
+// nearestUser returns n or its closest ancestor that is a user message.
+func (t *conversationTree) nearestUser(n *conversationNode) *conversationNode {
+	for cur := n; cur != nil && cur != t.root; cur = cur.parent {
+		if cur.role() == "user" {
+			return cur
+		}
+	}
+	return nil
+}
+
+// lastUser returns the most recent user message on the active branch.
+func (t *conversationTree) lastUser() *conversationNode { return t.nearestUser(t.leaf) }
+
+// history returns the user/assistant messages on the active branch.
+func (t *conversationTree) history() []ConversationMessage {
+	var out []ConversationMessage
+	for _, n := range t.path() {
+		if r := n.role(); r == "user" || r == "assistant" {
+			out = append(out, ConversationMessage{Role: r, Text: n.text()})
+		}
+	}
+	return out
+}
+
+func (t *conversationTree) reset() {
+	t.root = &conversationNode{}
+	t.byKey = map[string]*conversationNode{}
+	t.leaf = t.root
+}
+
+func indexOf(nodes []*conversationNode, n *conversationNode) int {
+	for i, c := range nodes {
+		if c == n {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyProps(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/stretchr/testify/require"
+)
+
+func addMessage(tr *conversationTree, id, role, text string) *conversationNode {
+	eid := timeline.EntityID{LocalID: id, Kind: "llm_text"}
+	tr.onCreated(timeline.UIEntityCreated{
+		ID:       eid,
+		Renderer: timeline.RendererDescriptor{Kind: "llm_text"},
+		Props:    map[string]any{"role": role, "text": text},
+	})
+	tr.onCompleted(timeline.UIEntityCompleted{ID: eid})
+	n, _ := tr.get(eid)
+	return n
+}
+
+func pathIDs(tr *conversationTree) []string {
+	var out []string
+	for _, n := range tr.path() {
+		out = append(out, n.id.LocalID)
+	}
+	return out
+}
+
+func TestConversationTreeRegenerateAndSwitchThreads(t *testing.T) {
+	tr := newConversationTree()
+	u1 := addMessage(tr, "u1", "user", "hi")
+	addMessage(tr, "a1", "assistant", "hello")
+	require.Equal(t, []string{"u1", "a1"}, pathIDs(tr))
+
+	// regenerate: a second answer forks under the same user message
+	tr.forkAt(tr.lastUser())
+	a2 := addMessage(tr, "a2", "assistant", "hey")
+	require.Equal(t, []string{"u1", "a2"}, pathIDs(tr))
+	idx, count := tr.threadPosition(a2)
+	require.Equal(t, 2, idx)
+	require.Equal(t, 2, count)
+
+	shown := tr.switchThread(a2, -1)
+	require.NotNil(t, shown)
+	require.Equal(t, "a1", shown.id.LocalID)
+	require.Equal(t, []string{"u1", "a1"}, pathIDs(tr))
+	require.Nil(t, tr.switchThread(shown, -1))
+
+	require.Equal(t, u1, tr.nearestUser(shown))
+	require.Equal(t, []ConversationMessage{{Role: "user", Text: "hi"}, {Role: "assistant", Text: "hello"}}, tr.history())
+}
+
+func TestConversationTreeEditForksSiblingUserMessage(t *testing.T) {
+	tr := newConversationTree()
+	u1 := addMessage(tr, "u1", "user", "first")
+	addMessage(tr, "a1", "assistant", "one")
+	addMessage(tr, "u2", "user", "second")
+
+	tr.forkAt(u1.parent)
+	addMessage(tr, "u1b", "user", "first, edited")
+	require.Equal(t, []string{"u1b"}, pathIDs(tr))
+
+	shown := tr.switchThread(tr.leaf, -1)
+	require.Equal(t, u1, shown)
+	// switching back restores the deepest node of the old branch
+	require.Equal(t, []string{"u1", "a1", "u2"}, pathIDs(tr))
+}
+
+func TestConversationTreeDeleteSplicesChildren(t *testing.T) {
+	tr := newConversationTree()
+	addMessage(tr, "u1", "user", "q")
+	a1 := addMessage(tr, "a1", "assistant", "a")
+	addMessage(tr, "u2", "user", "q2")
+
+	tr.onDeleted(timeline.UIEntityDeleted{ID: a1.id})
+	require.Equal(t, []string{"u1", "u2"}, pathIDs(tr))
+}
@@ -15,7 +15,7 @@ type KeyMap struct {
 	CancelCompletion key.Binding `keymap-mode:"stream-completion"`
 	DismissError     key.Binding `keymap-mode:"error"`
 
-	LoadFromFile key.Binding
+	LoadFromFile key.Binding `keymap-mode:"user-input,moving-around"`
 
 	Regenerate         key.Binding `keymap-mode:"user-input"`
 	RegenerateFromHere key.Binding `keymap-mode:"moving-around"`
@@ -24,6 +24,10 @@ type KeyMap struct {
 	PreviousConversationThread key.Binding `keymap-mode:"moving-around"`
 	NextConversationThread     key.Binding `keymap-mode:"moving-around"`
 
+	SearchTimeline key.Binding `keymap-mode:"moving-around"`
+	SearchNext     key.Binding `keymap-mode:"moving-around"`
+	SearchPrev     key.Binding `keymap-mode:"moving-around"`
+
 	SaveToFile             key.Binding `keymap-mode:"*"`
 	SaveSourceBlocksToFile key.Binding `keymap-mode:"*"`
 
@@ -132,6 +136,35 @@ var DefaultKeyMap = KeyMap{
 		key.WithHelp("right", "next conversation thread"),
 	),
 
+	Regenerate: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "regenerate last response"),
+	),
+	RegenerateFromHere: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "regenerate from here"),
+	),
+	EditMessage: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit message"),
+	),
+	SearchTimeline: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	SearchNext: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	SearchPrev: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous match"),
+	),
+	LoadFromFile: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("ctrl+o", "load from file"),
+	),
+
 	TriggerWeatherTool: key.NewBinding(
 		key.WithKeys("alt+w"),
 		key.WithHelp("alt+w", "demo weather tool"),
@@ -167,5 +200,9 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.CopyLastResponseToClipboard, k.CopyToClipboard},
 		{k.Profile},
 		{k.CopySourceBlocksToClipboard},
+		{k.Regenerate, k.RegenerateFromHere, k.EditMessage},
+		{k.PreviousConversationThread, k.NextConversationThread},
+		{k.SearchTimeline, k.SearchNext, k.SearchPrev},
+		{k.SaveToFile, k.LoadFromFile},
 	}
 }
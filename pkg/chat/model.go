@@ -18,6 +18,7 @@ import (
 	"github.com/go-go-golems/bobatea/pkg/filepicker"
 	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
 	"github.com/go-go-golems/bobatea/pkg/textarea"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
 	renderers "github.com/go-go-golems/bobatea/pkg/timeline/renderers"
 	"github.com/pkg/errors"
@@ -42,6 +43,7 @@ const (
 	StateMovingAround     State = "moving-around"
 	StateStreamCompletion State = "stream-completion"
 	StateSavingToFile     State = "saving-to-file"
+	StateLoadingFromFile  State = "loading-from-file"
 
 	StateError State = "error"
 )
@@ -51,7 +53,10 @@ type Status struct {
 	InputText    string `json:"inputText"`
 	SelectedIdx  int    `json:"selectedIdx"`
 	MessageCount int    `json:"messageCount"`
-	Error        error  `json:"error,omitempty"`
+	// ThreadIndex/ThreadCount locate the selected message's branch among its siblings (1-based)
+	ThreadIndex int   `json:"threadIndex"`
+	ThreadCount int   `json:"threadCount"`
+	Error       error `json:"error,omitempty"`
 }
 
 type model struct {
@@ -74,15 +79,25 @@ type model struct {
 	// entityStart     map[string]time.Time
 	timelineRegHook func(*timeline.Registry)
 
+	// tree records every entity, including inactive branches, for edit/regenerate/thread switching
+	tree *conversationTree
+	// editTarget is the user message being edited; the next submit forks a sibling of it
+	editTarget *conversationNode
+
 	help help.Model
 
 	err    error
 	keyMap KeyMap
+	// chords recognizes multi-key bindings in keyMap and renders the which-key popup
+	chords mode_keymap.Chords
 
 	style  *Style
 	width  int
 	height int
 
+	// theme is set by WithTheme and applied once the model is built
+	theme *theme.Theme
+
 	backend Backend
 
 	state        State
@@ -176,6 +191,34 @@ func WithStatusBarView(fn func() string) ModelOption {
 	}
 }
 
+// WithKeyMap replaces DefaultKeyMap, e.g. with a copy that had a keymap file applied:
+//
+//	km := chat.DefaultKeyMap
+//	overrides, err := mode_keymap.LoadOverrides(path)
+//	...
+//	conflicts, err := overrides.Apply(&km)
+func WithKeyMap(keyMap KeyMap) ModelOption {
+	return func(m *model) {
+		m.keyMap = keyMap
+	}
+}
+
+// WithChords configures how multi-key bindings such as "ctrl+x ctrl+s" or "leader s" are
+// recognized, e.g. mode_keymap.WithLeader("ctrl+a").
+func WithChords(options ...mode_keymap.ChordOption) ModelOption {
+	return func(m *model) {
+		m.chords = mode_keymap.NewChords(options...)
+	}
+}
+
+// WithTheme makes t the active theme and styles the chat, its timeline and the file picker
+// with it. Switch themes at runtime with theme.Use.
+func WithTheme(t theme.Theme) ModelOption {
+	return func(m *model) {
+		m.theme = &t
+	}
+}
+
 // TODO(manuel, 2024-04-07) Add options to configure filepicker
 
 func InitialModel(backend Backend, options ...ModelOption) model {
@@ -191,6 +234,7 @@ func InitialModel(backend Backend, options ...ModelOption) model {
 		filepicker:     fp,
 		style:          DefaultStyles(),
 		keyMap:         DefaultKeyMap,
+		chords:         mode_keymap.NewChords(),
 		backend:        backend,
 		help:           help.New(),
 		scrollToBottom: true,
@@ -218,7 +262,12 @@ func InitialModel(backend Backend, options ...ModelOption) model {
 		ret.timelineRegHook(ret.timelineReg)
 	}
 	ret.timelineSh = timeline.NewShell(ret.timelineReg)
+	ret.tree = newConversationTree()
 	// ret.entityStart = map[string]time.Time{}
+	if ret.theme != nil {
+		theme.Set(*ret.theme)
+		ret.applyTheme(*ret.theme)
+	}
 
 	return ret
 }
@@ -234,7 +283,8 @@ func (m model) Init() tea.Cmd {
 	//	})
 	//}
 
-	cmds = append(cmds, m.filepicker.Init(), m.timelineSh.Init())
+	// The file picker is initialized when saving or loading
+	cmds = append(cmds, m.timelineSh.Init())
 
 	// Seed existing chat messages as timeline entities
 	// Seeding from conversation is disabled; timeline should be sourced from entity events
@@ -265,79 +315,110 @@ func (m *model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if cmd, ok := m.bindingCmd(msg); ok {
+		return m, cmd
+	}
+
+	switch m.state {
+	case StateUserInput:
+		if !m.externalInput {
+			m.textArea, cmd = m.textArea.Update(msg)
+		}
+	case StateSavingToFile, StateLoadingFromFile:
+		var updatedModel tea.Model
+		updatedModel, cmd = m.filepicker.Update(msg)
+		m.filepicker = updatedModel.(filepicker.Model)
+	case StateMovingAround, StateStreamCompletion, StateError:
+		prevAtBottom := m.timelineSh.AtBottom()
+		cmd = m.timelineSh.UpdateViewport(msg)
+		if m.timelineSh.AtBottom() && !prevAtBottom {
+			m.scrollToBottom = false
+		}
+	}
+
+	return m, cmd
+}
+
+// bindingCmd returns the command of the keymap binding k triggers. k is a key press or a
+// completed chord (mode_keymap.ChordMsg).
+func (m *model) bindingCmd(k fmt.Stringer) (tea.Cmd, bool) {
 	// When streaming, forbid entering/focusing input and submitting
 	if m.state == StateStreamCompletion {
-		if key.Matches(msg, m.keyMap.SubmitMessage) || key.Matches(msg, m.keyMap.FocusMessage) {
-			return m, nil
+		if key.Matches(k, m.keyMap.SubmitMessage) || key.Matches(k, m.keyMap.FocusMessage) {
+			return nil, true
 		}
 	}
 
 	switch {
-	case key.Matches(msg, m.keyMap.Help):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("Help pressed")
-		cmd = func() tea.Msg { return ToggleHelpMsg{} }
-	case key.Matches(msg, m.keyMap.Profile):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("Profile pressed")
-		cmd = func() tea.Msg { return OpenProfilePickerMsg{} }
-	case key.Matches(msg, m.keyMap.UnfocusMessage):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("Unfocus (ESC) pressed")
-		cmd = func() tea.Msg { return UnfocusMessageMsg{} }
-	case key.Matches(msg, m.keyMap.Quit):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("Quit pressed")
-		cmd = func() tea.Msg { return QuitMsg{} }
-	case key.Matches(msg, m.keyMap.FocusMessage):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("Focus pressed")
-		cmd = func() tea.Msg { return FocusMessageMsg{} }
-	case key.Matches(msg, m.keyMap.SelectNextMessage):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("SelectNext pressed")
-		cmd = func() tea.Msg { return SelectNextMessageMsg{} }
-	case key.Matches(msg, m.keyMap.SelectPrevMessage):
-		log.Debug().Str("component", "chat").Str("key", msg.String()).Msg("SelectPrev pressed")
-		cmd = func() tea.Msg { return SelectPrevMessageMsg{} }
-	case key.Matches(msg, m.keyMap.SubmitMessage):
-		cmd = func() tea.Msg { return SubmitMessageMsg{} }
-	case key.Matches(msg, m.keyMap.CopyToClipboard):
-		cmd = func() tea.Msg { return CopyToClipboardMsg{} }
-	case key.Matches(msg, m.keyMap.CopyLastResponseToClipboard):
-		cmd = func() tea.Msg { return CopyLastResponseToClipboardMsg{} }
-	case key.Matches(msg, m.keyMap.CopyLastSourceBlocksToClipboard):
-		cmd = func() tea.Msg { return CopyLastSourceBlocksToClipboardMsg{} }
-	case key.Matches(msg, m.keyMap.CopySourceBlocksToClipboard):
-		cmd = func() tea.Msg { return CopySourceBlocksToClipboardMsg{} }
-	case key.Matches(msg, m.keyMap.SaveToFile):
-		cmd = func() tea.Msg { return SaveToFileMsg{} }
-	case key.Matches(msg, m.keyMap.CancelCompletion):
-		cmd = func() tea.Msg { return CancelCompletionMsg{} }
-	case key.Matches(msg, m.keyMap.DismissError):
-		cmd = func() tea.Msg { return DismissErrorMsg{} }
-	default:
-		switch m.state {
-		case StateUserInput:
-			if !m.externalInput {
-				m.textArea, cmd = m.textArea.Update(msg)
-			}
-		case StateSavingToFile:
-			var updatedModel tea.Model
-			updatedModel, cmd = m.filepicker.Update(msg)
-			m.filepicker = updatedModel.(filepicker.Model)
-		case StateMovingAround, StateStreamCompletion, StateError:
-			prevAtBottom := m.timelineSh.AtBottom()
-			cmd = m.timelineSh.UpdateViewport(msg)
-			if m.timelineSh.AtBottom() && !prevAtBottom {
-				m.scrollToBottom = false
-			}
-		}
+	case key.Matches(k, m.keyMap.Help):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("Help pressed")
+		return func() tea.Msg { return ToggleHelpMsg{} }, true
+	case key.Matches(k, m.keyMap.Profile):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("Profile pressed")
+		return func() tea.Msg { return OpenProfilePickerMsg{} }, true
+	case key.Matches(k, m.keyMap.UnfocusMessage):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("Unfocus (ESC) pressed")
+		return func() tea.Msg { return UnfocusMessageMsg{} }, true
+	case key.Matches(k, m.keyMap.Quit):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("Quit pressed")
+		return func() tea.Msg { return QuitMsg{} }, true
+	case key.Matches(k, m.keyMap.FocusMessage):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("Focus pressed")
+		return func() tea.Msg { return FocusMessageMsg{} }, true
+	case key.Matches(k, m.keyMap.SelectNextMessage):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("SelectNext pressed")
+		return func() tea.Msg { return SelectNextMessageMsg{} }, true
+	case key.Matches(k, m.keyMap.SelectPrevMessage):
+		log.Debug().Str("component", "chat").Str("key", k.String()).Msg("SelectPrev pressed")
+		return func() tea.Msg { return SelectPrevMessageMsg{} }, true
+	case key.Matches(k, m.keyMap.SubmitMessage):
+		return func() tea.Msg { return SubmitMessageMsg{} }, true
+	case key.Matches(k, m.keyMap.CopyToClipboard):
+		return func() tea.Msg { return CopyToClipboardMsg{} }, true
+	case key.Matches(k, m.keyMap.CopyLastResponseToClipboard):
+		return func() tea.Msg { return CopyLastResponseToClipboardMsg{} }, true
+	case key.Matches(k, m.keyMap.CopyLastSourceBlocksToClipboard):
+		return func() tea.Msg { return CopyLastSourceBlocksToClipboardMsg{} }, true
+	case key.Matches(k, m.keyMap.CopySourceBlocksToClipboard):
+		return func() tea.Msg { return CopySourceBlocksToClipboardMsg{} }, true
+	case key.Matches(k, m.keyMap.SaveToFile):
+		return func() tea.Msg { return SaveToFileMsg{} }, true
+	case key.Matches(k, m.keyMap.CancelCompletion):
+		return func() tea.Msg { return CancelCompletionMsg{} }, true
+	case key.Matches(k, m.keyMap.DismissError):
+		return func() tea.Msg { return DismissErrorMsg{} }, true
+	case key.Matches(k, m.keyMap.Regenerate):
+		return func() tea.Msg { return RegenerateMsg{} }, true
+	case key.Matches(k, m.keyMap.RegenerateFromHere):
+		return func() tea.Msg { return RegenerateFromHereMsg{} }, true
+	case key.Matches(k, m.keyMap.EditMessage):
+		return func() tea.Msg { return EditMessageMsg{} }, true
+	case key.Matches(k, m.keyMap.PreviousConversationThread):
+		return func() tea.Msg { return PreviousConversationThreadMsg{} }, true
+	case key.Matches(k, m.keyMap.NextConversationThread):
+		return func() tea.Msg { return NextConversationThreadMsg{} }, true
+	case key.Matches(k, m.keyMap.LoadFromFile):
+		return func() tea.Msg { return LoadFromFileMsg{} }, true
 	}
-
-	return m, cmd
+	return nil, false
 }
 
 func (m model) saveToFile(path string) (tea.Model, tea.Cmd) {
-	// No conversation manager; writing viewport content as a simple fallback
-	// In a real backend, this should request an export from the backend.
-	content := m.timelineSh.View()
-	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
-		return m, func() tea.Msg { return ErrorMsg(err) }
+	// .jsonl saves a timeline transcript that LoadFromFile can restore; anything else
+	// writes the rendered viewport content.
+	if strings.HasSuffix(path, ".jsonl") {
+		var buf strings.Builder
+		if err := m.timelineSh.Snapshot(&buf); err != nil {
+			return m, func() tea.Msg { return ErrorMsg(err) }
+		}
+		if err := os.WriteFile(path, []byte(buf.String()), 0o644); err != nil {
+			return m, func() tea.Msg { return ErrorMsg(err) }
+		}
+	} else {
+		content := m.timelineSh.View()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return m, func() tea.Msg { return ErrorMsg(err) }
+		}
 	}
 
 	m.state = StateUserInput
@@ -367,13 +448,45 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg_ := msg.(type) {
+	case theme.ChangedMsg:
+		m.applyTheme(msg_.Theme)
+		m.recomputeSize()
+		return m, nil
+
 	case tea.KeyMsg:
 		// When input is blurred, ignore key events on the input field
 		if m.inputBlurred {
 			return m, nil
 		}
+		// The search input takes every key, including enter and esc, until it is closed
+		if m.state == StateMovingAround && m.timelineSh.SearchInputActive() {
+			return m, m.timelineSh.UpdateSearch(msg_)
+		}
+		wasPending := m.chords.Pending()
+		var handled bool
+		if m.chords, cmd, handled = m.chords.Update(&m.keyMap, msg_); handled {
+			if m.chords.Pending() != wasPending {
+				m.recomputeSize()
+			}
+			return m, cmd
+		}
 		// Entering mode and selection routing
 		if m.state == StateMovingAround {
+			if !m.timelineSh.IsEntering() {
+				switch {
+				case key.Matches(msg_, m.keyMap.SearchTimeline):
+					return m, m.timelineSh.StartSearch()
+				case m.timelineSh.SearchActive() && key.Matches(msg_, m.keyMap.SearchNext):
+					m.timelineSh.SearchNext()
+					return m, nil
+				case m.timelineSh.SearchActive() && key.Matches(msg_, m.keyMap.SearchPrev):
+					m.timelineSh.SearchPrev()
+					return m, nil
+				case m.timelineSh.SearchActive() && msg_.String() == "esc":
+					m.timelineSh.ClearSearch()
+					return m, nil
+				}
+			}
 			switch msg_.String() {
 			case "enter":
 				m.timelineSh.EnterSelection()
@@ -449,6 +562,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m.handleKeyPress(msg_)
 
+	case mode_keymap.ChordTimeoutMsg:
+		wasPending := m.chords.Pending()
+		m.chords, cmd, _ = m.chords.Update(&m.keyMap, msg_)
+		if m.chords.Pending() != wasPending {
+			m.recomputeSize()
+		}
+		return m, cmd
+
+	case mode_keymap.ChordMsg:
+		cmd, _ = m.bindingCmd(msg_)
+		return m, cmd
+
 	case tea.WindowSizeMsg:
 		logger.Debug().Int("width", msg_.Width).Int("height", msg_.Height).Msg("Window size changed")
 		m.width = msg_.Width
@@ -472,6 +597,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Accept external timeline lifecycle messages (e.g., from backend simulating agent tool calls)
 	case timeline.UIEntityCreated:
 		logger.Debug().Str("lifecycle", "created").Str("kind", msg_.ID.Kind).Str("local_id", msg_.ID.LocalID).Msg("Applying external entity event")
+		m.tree.onCreated(msg_)
 		m.timelineSh.OnCreated(msg_)
 		if m.scrollToBottom {
 			m.timelineSh.GotoBottom()
@@ -479,6 +605,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case timeline.UIEntityUpdated:
 		logger.Debug().Str("lifecycle", "updated").Str("kind", msg_.ID.Kind).Str("local_id", msg_.ID.LocalID).Int64("version", msg_.Version).Msg("Applying external entity event")
+		m.tree.onUpdated(msg_)
 		m.timelineSh.OnUpdated(msg_)
 		if m.scrollToBottom {
 			m.timelineSh.GotoBottom()
@@ -486,6 +613,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case timeline.UIEntityCompleted:
 		logger.Debug().Str("lifecycle", "completed").Str("kind", msg_.ID.Kind).Str("local_id", msg_.ID.LocalID).Msg("Applying external entity event")
+		m.tree.onCompleted(msg_)
 		m.timelineSh.OnCompleted(msg_)
 		if m.scrollToBottom {
 			m.timelineSh.GotoBottom()
@@ -493,6 +621,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case timeline.UIEntityDeleted:
 		logger.Debug().Str("lifecycle", "deleted").Str("kind", msg_.ID.Kind).Str("local_id", msg_.ID.LocalID).Msg("Applying external entity event")
+		m.tree.onDeleted(msg_)
 		m.timelineSh.OnDeleted(msg_)
 		if m.scrollToBottom {
 			m.timelineSh.GotoBottom()
@@ -514,11 +643,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case filepicker.SelectFileMsg:
+		_ = m.filepicker.Close()
+		if m.state == StateLoadingFromFile {
+			logger.Trace().Str("path", msg_.Path).Msg("File selected for loading")
+			return m.loadFromFile(msg_.Path)
+		}
 		logger.Trace().Str("path", msg_.Path).Msg("File selected for saving")
 		return m.saveToFile(msg_.Path)
 
 	case filepicker.CancelFilePickerMsg:
 		logger.Trace().Msg("File picker cancelled")
+		_ = m.filepicker.Close()
 		m.state = StateUserInput
 		m.updateKeyBindings()
 
@@ -558,7 +693,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			// Allow non-key messages (e.g., mouse wheel) to reach the shell viewport
 			m.timelineSh.UpdateViewport(msg_)
-		case StateSavingToFile:
+		case StateSavingToFile, StateLoadingFromFile:
 			log.Trace().
 				Int64("update_call_id", updateCallID).
 				Msg("Updating filepicker")
@@ -577,6 +712,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.status.SelectedIdx = m.timelineSh.SelectedIndex()
 		// Fallback approximation using rendered height if entity count is not available
 		m.status.MessageCount = lipgloss.Height(m.timelineSh.View())
+		m.status.ThreadIndex, m.status.ThreadCount = 1, 1
+		if n := m.selectedNode(); n != nil {
+			m.status.ThreadIndex, m.status.ThreadCount = m.tree.threadPosition(n)
+		}
 		m.status.Error = m.err
 
 		if oldMessageCount != m.status.MessageCount {
@@ -612,10 +751,10 @@ func (m *model) recomputeSize() {
 	if headerView == "" {
 		headerHeight = 0
 	}
-	helpView := m.help.View(m.keyMap)
+	helpView := m.helpView()
 	helpViewHeight := lipgloss.Height(helpView)
 
-	if m.state == StateSavingToFile {
+	if m.state == StateSavingToFile || m.state == StateLoadingFromFile {
 		m.filepicker.Filepicker.Height = m.height - headerHeight - helpViewHeight
 		return
 	}
@@ -649,6 +788,25 @@ func (m *model) recomputeSize() {
 	m.timelineSh.GotoBottom()
 }
 
+// applyTheme restyles the chat and its children; the timeline renderers read the active theme.
+func (m *model) applyTheme(t theme.Theme) {
+	m.style = StylesFromTheme(t)
+	m.help.Styles = theme.HelpStyles(t)
+	m.chords = m.chords.WithStyles(mode_keymap.WhichKeyStylesFromTheme(t))
+	filepicker.ApplyTheme(t)
+	m.timelineSh.Controller().SetTheme(t.Name)
+	m.timelineSh.RefreshView(false)
+}
+
+// helpView renders the key help, preceded by the which-key popup while a chord is pending.
+func (m model) helpView() string {
+	helpView := m.help.View(m.keyMap)
+	if whichKey := m.chords.View(&m.keyMap); whichKey != "" {
+		return whichKey + "\n" + helpView
+	}
+	return helpView
+}
+
 func (m model) headerView() string {
 	if m.headerViewFunc == nil {
 		return ""
@@ -680,13 +838,16 @@ func (m model) textAreaView() string {
 	case StateUserInput:
 		v = m.style.FocusedMessage.Render(v)
 	case StateMovingAround:
+		if m.timelineSh.SearchActive() {
+			return m.timelineSh.SearchView()
+		}
 		// Grey out input when in selection mode
 		v = m.style.UnselectedMessage.Foreground(lipgloss.Color("240")).Render(v)
 	case StateStreamCompletion:
 		// Grey out and ensure blurred while streaming
 		m.textArea.Blur()
 		v = m.style.UnselectedMessage.Render(v)
-	case StateError, StateSavingToFile:
+	case StateError, StateSavingToFile, StateLoadingFromFile:
 	}
 
 	return v
@@ -700,7 +861,7 @@ func (m model) View() string {
 	textAreaView := m.textAreaView()
 
 	statusBarView := m.statusBarView()
-	helpView := m.help.View(m.keyMap)
+	helpView := m.helpView()
 
 	ret := ""
 	if headerView != "" {
@@ -728,7 +889,7 @@ func (m model) View() string {
 			ret += viewportView + statusBarSuffix + "\n" + textAreaView + "\n" + helpView
 		}
 
-	case StateSavingToFile:
+	case StateSavingToFile, StateLoadingFromFile:
 		ret += m.filepicker.View()
 	}
 
@@ -819,15 +980,27 @@ func (m *model) submit() tea.Cmd {
 	}
 	m.updateKeyBindings()
 
+	// Editing a past user message forks a new branch next to it
+	if m.editTarget != nil {
+		log.Debug().Str("component", "chat").Str("when", "submit").Str("edited_id", m.editTarget.id.LocalID).Msg("Forking branch for edited message")
+		m.tree.forkAt(m.editTarget.parent)
+		m.editTarget = nil
+		m.rebuildTimeline(nil)
+	}
+
 	// Add entity to timeline
 	id := uuid.New().String()
 	log.Debug().Str("component", "chat").Str("when", "submit").Str("id", id).Msg("Adding user message to timeline")
-	m.timelineSh.OnCreated(timeline.UIEntityCreated{
+	created := timeline.UIEntityCreated{
 		ID:       timeline.EntityID{LocalID: id, Kind: "llm_text"},
 		Renderer: timeline.RendererDescriptor{Kind: "llm_text"},
 		Props:    map[string]any{"role": "user", "text": userMessage},
-	})
-	m.timelineSh.OnCompleted(timeline.UIEntityCompleted{ID: timeline.EntityID{LocalID: id, Kind: "llm_text"}})
+	}
+	completed := timeline.UIEntityCompleted{ID: created.ID}
+	m.tree.onCreated(created)
+	m.tree.onCompleted(completed)
+	m.timelineSh.OnCreated(created)
+	m.timelineSh.OnCompleted(completed)
 	log.Debug().Str("component", "chat").Str("when", "submit").Str("id", id).Msg("User message added to timeline")
 
 	if !m.externalInput {
@@ -841,15 +1014,147 @@ func (m *model) submit() tea.Cmd {
 		return refreshMessageMsg{GoToBottom: true}
 	}
 
-	backendCmd := func() tea.Msg {
+	return tea.Batch(refreshCmd, m.runBackend(userMessage))
+}
+
+// runBackend starts the backend for the active branch. BranchingBackends receive the branch
+// history; plain backends receive the prompt only.
+func (m *model) runBackend(prompt string) tea.Cmd {
+	history := m.tree.history()
+	backend := m.backend
+	return func() tea.Msg {
 		ctx := context2.Background()
-		cmd, err := m.backend.Start(ctx, userMessage)
+		var (
+			cmd tea.Cmd
+			err error
+		)
+		if bb, ok := backend.(BranchingBackend); ok {
+			cmd, err = bb.StartFromHistory(ctx, history)
+		} else {
+			cmd, err = backend.Start(ctx, prompt)
+		}
 		if err != nil {
 			return ErrorMsg(err)
 		}
+		if cmd == nil {
+			return nil
+		}
 		return cmd()
 	}
-	return tea.Batch(refreshCmd, backendCmd)
+}
+
+// regenerate forks a new branch after the given user message and re-runs the backend from there.
+// The previous responses stay reachable as a sibling thread.
+func (m *model) regenerate(from *conversationNode) tea.Cmd {
+	if from == nil {
+		return func() tea.Msg { return ErrorMsg(errors.New("no user message to regenerate from")) }
+	}
+	if !m.backend.IsFinished() {
+		return func() tea.Msg { return ErrorMsg(errors.New("already streaming")) }
+	}
+	log.Debug().Str("component", "chat").Str("when", "regenerate").Str("from_id", from.id.LocalID).Msg("Forking branch for regeneration")
+	m.tree.forkAt(from)
+	m.editTarget = nil
+	m.rebuildTimeline(nil)
+
+	m.state = StateStreamCompletion
+	m.timelineSh.SetSelectionVisible(false)
+	if m.externalInput {
+		m.inputBlurred = true
+	} else {
+		m.textArea.Blur()
+	}
+	m.scrollToBottom = true
+	m.updateKeyBindings()
+
+	refreshCmd := func() tea.Msg { return refreshMessageMsg{GoToBottom: true} }
+	return tea.Batch(refreshCmd, m.runBackend(from.text()))
+}
+
+// rebuildTimeline replaces the timeline content with the active branch of the conversation tree
+// and selects sel when it is on that branch.
+func (m *model) rebuildTimeline(sel *conversationNode) {
+	ctrl := m.timelineSh.Controller()
+	ctrl.Clear()
+	selIdx := -1
+	for i, n := range m.tree.path() {
+		ctrl.OnCreated(timeline.UIEntityCreated{ID: n.id, Renderer: n.renderer, Props: copyProps(n.props), StartedAt: n.startedAt})
+		if n.completed {
+			ctrl.OnCompleted(timeline.UIEntityCompleted{ID: n.id})
+		}
+		if n == sel {
+			selIdx = i
+		}
+	}
+	if selIdx >= 0 {
+		ctrl.Select(selIdx)
+		m.timelineSh.ScrollToSelected()
+		return
+	}
+	ctrl.SelectLast()
+	m.timelineSh.RefreshView(true)
+}
+
+// selectedNode returns the conversation node behind the selected timeline entity.
+func (m *model) selectedNode() *conversationNode {
+	id, _, _, ok := m.timelineSh.Controller().GetSelectedMeta()
+	if !ok {
+		return nil
+	}
+	n, _ := m.tree.get(id)
+	return n
+}
+
+// switchThread shows the previous/next sibling branch of the selected message.
+func (m *model) switchThread(delta int) {
+	n := m.selectedNode()
+	if n == nil {
+		return
+	}
+	if shown := m.tree.switchThread(n, delta); shown != nil {
+		m.rebuildTimeline(shown)
+		m.scrollToBottom = false
+	}
+}
+
+// loadFromFile replaces the conversation with a JSONL timeline transcript.
+func (m model) loadFromFile(path string) (tea.Model, tea.Cmd) {
+	f, err := os.Open(path)
+	if err != nil {
+		return m, func() tea.Msg { return ErrorMsg(err) }
+	}
+	defer func() { _ = f.Close() }()
+
+	m.tree.reset()
+	m.editTarget = nil
+	m.timelineSh.Clear()
+	ctrl := m.timelineSh.Controller()
+	err = timeline.ReadTranscript(f, func(ev any) error {
+		switch e := ev.(type) {
+		case timeline.UIEntityCreated:
+			m.tree.onCreated(e)
+		case timeline.UIEntityUpdated:
+			m.tree.onUpdated(e)
+		case timeline.UIEntityCompleted:
+			m.tree.onCompleted(e)
+		case timeline.UIEntityDeleted:
+			m.tree.onDeleted(e)
+		}
+		ctrl.Apply(ev)
+		return nil
+	})
+	m.timelineSh.RefreshView(true)
+
+	m.state = StateUserInput
+	if !m.externalInput {
+		m.textArea.Focus()
+	}
+	m.updateKeyBindings()
+	m.recomputeSize()
+	if err != nil {
+		return m, func() tea.Msg { return ErrorMsg(errors.Wrapf(err, "load %s", path)) }
+	}
+	return m, nil
 }
 
 type refreshMessageMsg struct {
@@ -913,6 +1218,7 @@ func (m model) handleUserAction(msg UserActionMsg) (tea.Model, tea.Cmd) {
 
 	case UnfocusMessageMsg:
 		if m.state == StateUserInput {
+			m.editTarget = nil
 			m.textArea.Blur()
 			m.state = StateMovingAround
 			// Enter moving around; select last entity and show selection highlight
@@ -949,6 +1255,7 @@ func (m model) handleUserAction(msg UserActionMsg) (tea.Model, tea.Cmd) {
 		// But if we kill we might get another completion response and then we would have two messages.
 		// Maybe we should just do the right thing and implementing a Quitting state...
 		m.finishCompletion()
+		_ = m.filepicker.Close()
 
 		cmd = tea.Quit
 
@@ -1018,6 +1325,46 @@ func (m model) handleUserAction(msg UserActionMsg) (tea.Model, tea.Cmd) {
 		m.recomputeSize()
 		m.updateKeyBindings()
 
+	case RegenerateMsg:
+		if m.state == StateStreamCompletion {
+			break
+		}
+		cmd = m.regenerate(m.tree.lastUser())
+
+	case RegenerateFromHereMsg:
+		if m.state == StateStreamCompletion {
+			break
+		}
+		cmd = m.regenerate(m.tree.nearestUser(m.selectedNode()))
+
+	case EditMessageMsg:
+		n := m.selectedNode()
+		if n == nil || n.role() != "user" {
+			return m, func() tea.Msg { return ErrorMsg(errors.New("only user messages can be edited")) }
+		}
+		m.editTarget = n
+		m.textArea.SetValue(n.text())
+		cmd = m.textArea.Focus()
+		m.state = StateUserInput
+		m.timelineSh.SetSelectionVisible(false)
+		m.updateKeyBindings()
+		m.recomputeSize()
+
+	case PreviousConversationThreadMsg:
+		m.switchThread(-1)
+
+	case NextConversationThreadMsg:
+		m.switchThread(1)
+
+	case LoadFromFileMsg:
+		if !m.backend.IsFinished() {
+			return m, func() tea.Msg { return ErrorMsg(errors.New("cannot load while streaming")) }
+		}
+		m.state = StateLoadingFromFile
+		cmd = m.filepicker.Init()
+		m.recomputeSize()
+		m.updateKeyBindings()
+
 	case CancelCompletionMsg:
 		if m.state == StateStreamCompletion {
 			m.backend.Interrupt()
@@ -1,6 +1,9 @@
 package chat
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+)
 
 type Style struct {
 	UnselectedMessage lipgloss.Style
@@ -68,3 +71,21 @@ func DefaultStyles() *Style {
 			Foreground(lipgloss.Color(errorColors.Selected)),
 	}
 }
+
+// StylesFromTheme returns the message styles for a theme.
+func StylesFromTheme(t theme.Theme) *Style {
+	p := t.Palette
+	frame := func(border lipgloss.Border, color lipgloss.Color) lipgloss.Style {
+		return lipgloss.NewStyle().Border(border).Padding(0, 1).BorderForeground(color)
+	}
+	return &Style{
+		UnselectedMessage: frame(lipgloss.NormalBorder(), p.Border),
+		SelectedMessage:   frame(lipgloss.ThickBorder(), p.Secondary),
+		FocusedMessage:    frame(lipgloss.NormalBorder(), p.Highlight),
+		MetadataStyle: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Align(lipgloss.Right),
+		ErrorMessage:  frame(lipgloss.NormalBorder(), p.Error).Foreground(p.Error),
+		ErrorSelected: frame(lipgloss.ThickBorder(), p.Error).Foreground(p.Error).Bold(true),
+	}
+}
@@ -87,3 +87,18 @@ type UnblurInputMsg struct{}
 
 func (BlurInputMsg) isUserAction()   {}
 func (UnblurInputMsg) isUserAction() {}
+
+// Conversation tree actions
+type RegenerateMsg struct{}
+type RegenerateFromHereMsg struct{}
+type EditMessageMsg struct{}
+type PreviousConversationThreadMsg struct{}
+type NextConversationThreadMsg struct{}
+type LoadFromFileMsg struct{}
+
+func (RegenerateMsg) isUserAction()                 {}
+func (RegenerateFromHereMsg) isUserAction()         {}
+func (EditMessageMsg) isUserAction()                {}
+func (PreviousConversationThreadMsg) isUserAction() {}
+func (NextConversationThreadMsg) isUserAction()     {}
+func (LoadFromFileMsg) isUserAction()               {}
@@ -0,0 +1,168 @@
+package commandpalette
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// ParamKind is the type of a command parameter.
+type ParamKind string
+
+const (
+	// ParamString accepts any text; Param.Complete may suggest values.
+	ParamString ParamKind = "string"
+	// ParamChoice accepts one of Param.Choices.
+	ParamChoice ParamKind = "choice"
+	// ParamPath accepts a file path, completed from the file system or picked with the file picker.
+	ParamPath ParamKind = "path"
+	// ParamBool accepts yes or no.
+	ParamBool ParamKind = "bool"
+)
+
+// Param declares an argument a command asks for before it runs.
+type Param struct {
+	// Name keys the value in Args.
+	Name string
+	// Prompt labels the argument step; Name is used when empty.
+	Prompt string
+	Kind   ParamKind
+	// Choices lists the accepted values of a ParamChoice.
+	Choices []string
+	// Default is used when the input is left empty.
+	Default string
+	// Optional lets the argument be left empty.
+	Optional bool
+	// Complete suggests values for the current input. It replaces the built-in completions
+	// (file system entries for ParamPath, matching choices for ParamChoice).
+	Complete func(input string) []string
+}
+
+func (p Param) label() string {
+	if p.Prompt != "" {
+		return p.Prompt
+	}
+	return p.Name
+}
+
+// Args holds the collected arguments of a command, keyed by Param.Name. ParamBool values are
+// bools, all others strings.
+type Args map[string]any
+
+// String returns the argument name as a string.
+func (a Args) String(name string) string {
+	switch v := a[name].(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// Bool returns the argument name as a bool.
+func (a Args) Bool(name string) bool {
+	v, _ := a[name].(bool)
+	return v
+}
+
+var boolChoices = []string{"yes", "no"}
+
+// completions returns the suggestions listed under the argument input.
+func (p Param) completions(input string) []string {
+	if p.Complete != nil {
+		return p.Complete(input)
+	}
+	switch p.Kind {
+	case ParamChoice:
+		return filterChoices(p.Choices, input)
+	case ParamBool:
+		return filterChoices(boolChoices, input)
+	case ParamPath:
+		return pathCompletions(input)
+	case ParamString:
+	}
+	return nil
+}
+
+// parse validates input and converts it to the argument value.
+func (p Param) parse(input string) (any, bool) {
+	if input == "" {
+		input = p.Default
+	}
+	if input == "" {
+		return "", p.Optional
+	}
+	switch p.Kind {
+	case ParamChoice:
+		for _, c := range p.Choices {
+			if strings.EqualFold(c, input) {
+				return c, true
+			}
+		}
+		return nil, false
+	case ParamBool:
+		switch strings.ToLower(input) {
+		case "y", "yes", "true", "1", "on":
+			return true, true
+		case "n", "no", "false", "0", "off":
+			return false, true
+		}
+		return nil, false
+	case ParamPath:
+		return expandHome(input), true
+	case ParamString:
+	}
+	return input, true
+}
+
+func filterChoices(choices []string, input string) []string {
+	if input == "" {
+		return choices
+	}
+	ret := []string{}
+	for _, match := range fuzzy.Find(input, choices) {
+		ret = append(ret, choices[match.Index])
+	}
+	return ret
+}
+
+// pathCompletions lists the entries of input's directory whose name starts with its last
+// element. Directories end with a separator so completing one descends into it.
+func pathCompletions(input string) []string {
+	dir, prefix := filepath.Split(input)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(expandHome(readDir))
+	if err != nil {
+		return nil
+	}
+	ret := []string{}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		if e.IsDir() {
+			name += string(filepath.Separator)
+		}
+		ret = append(ret, dir+name)
+	}
+	return ret
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,160 @@
+package commandpalette
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func key(t tea.KeyType) tea.KeyMsg { return tea.KeyMsg{Type: t} }
+
+func TestArgumentStepCollectsTypedParams(t *testing.T) {
+	var got Args
+	m := New()
+	m.SetSize(80, 40)
+	m.SetCommands([]Command{{
+		Name: "Export",
+		Params: []Param{
+			{Name: "format", Kind: ParamChoice, Choices: []string{"json", "yaml"}},
+			{Name: "name", Kind: ParamString, Default: "out"},
+			{Name: "force", Kind: ParamBool},
+		},
+		Run: func(args Args) tea.Cmd { got = args; return nil },
+	}})
+	m.Show()
+
+	m, _ = m.Update(key(tea.KeyEnter))
+	require.True(t, m.CollectingArguments())
+	require.Contains(t, m.View(), "Export › format (1/3)")
+
+	// choices are filtered by the input and enter takes the highlighted one
+	m = typeQuery(m, "ya")
+	m, _ = m.Update(key(tea.KeyEnter))
+	require.Contains(t, m.View(), "default: out")
+
+	// esc steps back to the previous parameter with its value restored
+	m, _ = m.Update(key(tea.KeyEsc))
+	require.Contains(t, m.View(), "> yaml")
+	m, _ = m.Update(key(tea.KeyEnter))
+
+	m, _ = m.Update(key(tea.KeyEnter))
+	m = typeQuery(m, "maybe")
+	m, _ = m.Update(key(tea.KeyEnter))
+	require.Nil(t, got)
+	require.Contains(t, m.View(), `invalid force: "maybe"`)
+
+	for range "maybe" {
+		m, _ = m.Update(key(tea.KeyBackspace))
+	}
+	m = typeQuery(m, "y")
+	m, _ = m.Update(key(tea.KeyEnter))
+	require.False(t, m.IsVisible())
+	require.Equal(t, Args{"format": "yaml", "name": "out", "force": true}, got)
+}
+
+func TestArgumentStepRequiresValuesAndStepsBackToCommands(t *testing.T) {
+	ran := false
+	m := New()
+	m.SetCommands([]Command{{
+		Name:   "Rename",
+		Params: []Param{{Name: "to", Kind: ParamString}},
+		Run:    func(Args) tea.Cmd { ran = true; return nil },
+	}})
+	m.Show()
+	m, _ = m.Update(key(tea.KeyEnter))
+	m, _ = m.Update(key(tea.KeyEnter))
+	require.True(t, m.CollectingArguments())
+	require.Contains(t, m.View(), "to is required")
+
+	m, _ = m.Update(key(tea.KeyEsc))
+	require.True(t, m.IsVisible())
+	require.False(t, m.CollectingArguments())
+	require.False(t, ran)
+}
+
+func TestPathCompletionsListDirectoryEntries(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "setup.js"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), nil, 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "scripts"), 0o755))
+
+	p := Param{Name: "path", Kind: ParamPath}
+	sep := string(filepath.Separator)
+	require.Equal(t, []string{
+		filepath.Join(dir, "scripts") + sep,
+		filepath.Join(dir, "setup.js"),
+	}, p.completions(dir+sep+"s"))
+	require.Equal(t, []string{filepath.Join(dir, ".hidden")}, p.completions(dir+sep+"."))
+}
+
+func TestPathPickerRunsItsCommandsThroughPickerMsg(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "scripts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "scripts", "run.js"), nil, 0o644))
+
+	var got Args
+	m := New()
+	m.SetSize(80, 40)
+	m.SetCommands([]Command{{
+		Name:   "Load",
+		Params: []Param{{Name: "path", Kind: ParamPath}},
+		Run:    func(args Args) tea.Cmd { got = args; return nil },
+	}})
+	m.Show()
+	m, _ = m.Update(key(tea.KeyEnter))
+	m = typeQuery(m, dir)
+	m, _ = m.Update(key(tea.KeyCtrlO))
+	require.NotNil(t, m.step.picker)
+
+	// entering a directory loads it in a command, whose result comes back as PickerMsg
+	m, _ = m.Update(key(tea.KeyDown))
+	m, cmd := m.Update(key(tea.KeyEnter))
+	for cmd != nil {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			require.Len(t, batch, 1)
+			msg = batch[0]()
+		}
+		if msg == nil {
+			break
+		}
+		require.IsType(t, PickerMsg{}, msg)
+		m, cmd = m.Update(msg)
+	}
+	require.Contains(t, m.View(), "run.js")
+
+	m, _ = m.Update(key(tea.KeyDown))
+	_, _ = m.Update(key(tea.KeyEnter))
+	require.Equal(t, Args{"path": filepath.Join(dir, "scripts", "run.js")}, got)
+}
+
+func TestHidingThePaletteClosesThePathPicker(t *testing.T) {
+	m := New()
+	m.SetSize(80, 40)
+	m.SetCommands([]Command{{
+		Name:   "Load",
+		Params: []Param{{Name: "path", Kind: ParamPath}},
+		Run:    func(Args) tea.Cmd { return nil },
+	}})
+	m.Show()
+	m, _ = m.Update(key(tea.KeyEnter))
+	m = typeQuery(m, t.TempDir())
+	m, watch := m.Update(key(tea.KeyCtrlO))
+	require.NotNil(t, watch, "expected the picker to start watching its directory")
+
+	// closing the picker ends its watch, which then delivers nothing
+	m.Hide()
+	require.Nil(t, m.step.picker)
+	done := make(chan tea.Msg, 1)
+	go func() { done <- watch() }()
+	select {
+	case msg := <-done:
+		require.Nil(t, msg)
+	case <-time.After(time.Second):
+		t.Fatal("expected hiding the palette to close the picker")
+	}
+}
@@ -0,0 +1,271 @@
+package commandpalette
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/filepicker"
+)
+
+// argumentStep is the state of the palette while it asks for the selected command's Params.
+type argumentStep struct {
+	cmd   *Command
+	index int
+	args  Args
+	input string
+	// items are the completions of input, selected is the highlighted one
+	items    []string
+	selected int
+	offset   int
+	err      string
+	// picker browses the file system for a ParamPath argument
+	picker *filepicker.AdvancedModel
+}
+
+func (s argumentStep) active() bool { return s.cmd != nil }
+
+func (s argumentStep) param() Param { return s.cmd.Params[s.index] }
+
+func (m *Model) beginArguments(cmd Command) {
+	m.resetArguments()
+	m.step = argumentStep{cmd: &cmd, args: Args{}}
+	m.refreshArgumentItems()
+}
+
+// resetArguments leaves the argument step, closing its file picker.
+func (m *Model) resetArguments() {
+	m.closePicker()
+	m.step = argumentStep{}
+}
+
+func (m *Model) refreshArgumentItems() {
+	m.step.items = m.step.param().completions(m.step.input)
+	m.step.selected = 0
+	m.step.offset = 0
+}
+
+// updateArguments handles keys while arguments are collected. Enter confirms the input (or the
+// highlighted item for choice and bool parameters), tab completes the highlighted item, esc goes
+// back one parameter and ctrl+o opens the file picker for path parameters.
+func (m Model) updateArguments(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.step.picker != nil {
+		return m.updatePicker(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+p":
+		m.Hide()
+		return m, nil
+
+	case "esc", "escape":
+		if m.step.index == 0 {
+			m.resetArguments()
+			return m, nil
+		}
+		m.step.index--
+		m.step.input = m.step.args.String(m.step.param().Name)
+		m.step.err = ""
+		m.refreshArgumentItems()
+		return m, nil
+
+	case "enter":
+		value := m.step.input
+		if k := m.step.param().Kind; (k == ParamChoice || k == ParamBool) && len(m.step.items) > 0 {
+			value = m.step.items[m.step.selected]
+		}
+		return m.acceptArgument(value)
+
+	case "tab":
+		if len(m.step.items) > 0 {
+			m.step.input = m.step.items[m.step.selected]
+			m.step.err = ""
+			m.refreshArgumentItems()
+		}
+		return m, nil
+
+	case "up", "ctrl+k":
+		if m.step.selected > 0 {
+			m.step.selected--
+		}
+		m.step.offset = min(m.step.offset, m.step.selected)
+		return m, nil
+
+	case "down", "ctrl+j":
+		if m.step.selected < len(m.step.items)-1 {
+			m.step.selected++
+		}
+		m.step.offset = max(m.step.offset, m.step.selected-m.visibleLimit()+1)
+		return m, nil
+
+	case "ctrl+o":
+		if m.step.param().Kind == ParamPath {
+			return m, m.openPicker()
+		}
+		return m, nil
+
+	case "backspace":
+		if m.step.input != "" {
+			_, size := utf8.DecodeLastRuneInString(m.step.input)
+			m.step.input = m.step.input[:len(m.step.input)-size]
+			m.step.err = ""
+			m.refreshArgumentItems()
+		}
+		return m, nil
+
+	default:
+		if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt {
+			m.step.input += string(msg.Runes)
+			m.step.err = ""
+			m.refreshArgumentItems()
+		}
+		return m, nil
+	}
+}
+
+// acceptArgument stores value for the current parameter and moves on, running the command once
+// the last one is filled in.
+func (m Model) acceptArgument(value string) (Model, tea.Cmd) {
+	p := m.step.param()
+	v, ok := p.parse(value)
+	if !ok {
+		if value == "" && p.Default == "" {
+			m.step.err = p.label() + " is required"
+		} else {
+			m.step.err = fmt.Sprintf("invalid %s: %q", p.label(), value)
+		}
+		return m, nil
+	}
+	m.step.args[p.Name] = v
+	m.step.index++
+	if m.step.index < len(m.step.cmd.Params) {
+		m.step.input = ""
+		m.step.err = ""
+		m.refreshArgumentItems()
+		return m, nil
+	}
+	cmd, args := *m.step.cmd, m.step.args
+	m.Hide()
+	return m, cmd.execute(args)
+}
+
+// openPicker shows the file picker, starting in the directory of the current input, and
+// returns its commands, which watch the directory for changes.
+func (m *Model) openPicker() tea.Cmd {
+	start := "."
+	if m.step.input != "" {
+		dir := expandHome(m.step.input)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		start = dir
+	}
+	// the palette closes the picker with the argument step, so it can watch the disk
+	picker := filepicker.New(filepicker.WithStartPath(start), filepicker.WithShowPreview(false), filepicker.WithWatch(true))
+	_, cmd := picker.Update(tea.WindowSizeMsg{Width: max(20, m.width-12), Height: max(8, m.height/2)})
+	m.step.picker = picker
+	return pickerCmd(tea.Batch(picker.Init(), cmd))
+}
+
+// closePicker stops the file picker, if one is open.
+func (m *Model) closePicker() {
+	if m.step.picker != nil {
+		_ = m.step.picker.Close()
+		m.step.picker = nil
+	}
+}
+
+// updatePicker forwards keys to the file picker. The picker loads directories and runs file
+// operations in commands, whose messages come back wrapped in PickerMsg; tea.Quit is dropped
+// since the palette only needs the chosen path.
+func (m Model) updatePicker(msg tea.Msg) (Model, tea.Cmd) {
+	_, cmd := m.step.picker.Update(msg)
+	if paths, ok := m.step.picker.GetSelected(); ok {
+		m.closePicker()
+		return m.acceptArgument(paths[0])
+	}
+	if m.step.picker.Cancelled() {
+		m.closePicker()
+		return m, nil
+	}
+	return m, pickerCmd(cmd)
+}
+
+// pickerCmd wraps the messages of a file picker command in PickerMsg.
+func pickerCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil, tea.QuitMsg:
+			return nil
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				cmds[i] = pickerCmd(c)
+			}
+			return cmds
+		default:
+			return PickerMsg{Msg: msg}
+		}
+	}
+}
+
+func (m Model) viewArguments() string {
+	p := m.step.param()
+	title := m.step.cmd.Name + " › " + p.label()
+	if n := len(m.step.cmd.Params); n > 1 {
+		title += fmt.Sprintf(" (%d/%d)", m.step.index+1, n)
+	}
+	header := m.styles.Header.Render(title)
+
+	queryPrompt := "> " + m.step.input
+	if m.step.input == "" {
+		queryPrompt = "> " + p.label()
+		if p.Default != "" {
+			queryPrompt += " (default: " + p.Default + ")"
+		}
+	}
+	query := m.styles.Query.Width(m.width - 12).Render(queryPrompt)
+
+	var body []string
+	if m.step.picker != nil {
+		body = append(body, m.step.picker.View())
+	} else {
+		rowWidth := m.width - 12
+		end := min(len(m.step.items), m.step.offset+m.visibleLimit())
+		for i := m.step.offset; i < end; i++ {
+			style := m.styles.Command
+			if i == m.step.selected {
+				style = m.styles.SelectedCommand
+			}
+			body = append(body, style.Width(rowWidth).Render(m.step.items[i]))
+		}
+	}
+	if m.step.err != "" {
+		body = append(body, m.styles.Error.Render(m.step.err))
+	}
+
+	help := "Enter confirm • Tab complete • Esc back"
+	switch {
+	case m.step.picker != nil:
+		help = "Enter choose • Esc cancel browsing"
+	case p.Kind == ParamPath:
+		help += " • Ctrl+O browse"
+	}
+	footer := m.styles.Help.Render(help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		query,
+		strings.Join(body, "\n"),
+		"",
+		footer,
+	)
+	return m.styles.Palette.Width(m.width - 8).Render(content)
+}
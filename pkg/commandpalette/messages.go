@@ -1,7 +1,15 @@
 package commandpalette
 
+import tea "github.com/charmbracelet/bubbletea"
+
 // ExecutedMsg is sent when a command is executed
 type ExecutedMsg struct {
 	Command string
 	Data    interface{}
 }
+
+// PickerMsg carries a message of the file picker opened for a path parameter. Models
+// embedding the palette pass it to Update like key messages.
+type PickerMsg struct {
+	Msg tea.Msg
+}
@@ -1,31 +1,87 @@
 package commandpalette
 
 import (
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+	"github.com/mattn/go-runewidth"
 	"github.com/sahilm/fuzzy"
 )
 
 // Command represents a command that can be executed
 type Command struct {
+	// ID identifies the command in the recently used list; Name is used when empty.
+	ID          string
 	Name        string
 	Description string
-	Action      func() tea.Cmd
+	// Category groups commands in the unfiltered list.
+	Category string
+	// Keywords are searched along with Name.
+	Keywords []string
+	// KeyHint is the key bound to the command, shown at the end of its row.
+	KeyHint string
+	// Enabled hides the command while it returns false; nil means always enabled.
+	Enabled func() bool
+	Action  func() tea.Cmd
+	// Params are asked for one by one after the command is selected; Run then receives them
+	// instead of Action being called.
+	Params []Param
+	Run    func(Args) tea.Cmd
 }
 
+func (c Command) execute(args Args) tea.Cmd {
+	if c.Run != nil {
+		return c.Run(args)
+	}
+	if c.Action != nil {
+		return c.Action()
+	}
+	return nil
+}
+
+func (c Command) key() string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return c.Name
+}
+
+const (
+	defaultMaxVisible = 8
+	defaultMaxRecent  = 5
+	// recentGroup labels the recently used commands at the top of the unfiltered list
+	recentGroup = "Recent"
+	// keywordPenalty ranks keyword matches below equally good name matches
+	keywordPenalty = 10
+	// recentBonus lifts recently used commands among matches of similar quality
+	recentBonus = 5
+)
+
 // Model represents the command palette state
 type Model struct {
 	commands     []Command
 	filteredCmds []Command
-	query        string
-	selected     int
-	visible      bool
-	maxVisible   int
-	width        int
-	height       int
-	styles       Styles
+	// groups holds the group label of each filtered command; nil while searching
+	groups     []string
+	query      string
+	selected   int
+	offset     int
+	visible    bool
+	maxVisible int
+	width      int
+	height     int
+	styles     Styles
+
+	// recent holds command keys, most recently used first
+	recent    []string
+	maxRecent int
+
+	// step collects the arguments of the selected command
+	step argumentStep
 }
 
 // New creates a new command palette model
@@ -36,7 +92,8 @@ func New() Model {
 		query:        "",
 		selected:     0,
 		visible:      false,
-		maxVisible:   8,
+		maxVisible:   defaultMaxVisible,
+		maxRecent:    defaultMaxRecent,
 		styles:       DefaultStyles(),
 	}
 }
@@ -57,7 +114,7 @@ func (m *Model) RegisterCommand(name, description string, action func() tea.Cmd)
 	m.updateFiltered()
 }
 
-// SetCommands replaces all commands in the palette.
+// SetCommands replaces all commands in the palette. The recently used list is kept.
 func (m *Model) SetCommands(commands []Command) {
 	m.commands = append([]Command(nil), commands...)
 	m.query = ""
@@ -70,6 +127,7 @@ func (m *Model) Show() {
 	m.visible = true
 	m.query = ""
 	m.selected = 0
+	m.resetArguments()
 	m.updateFiltered()
 }
 
@@ -78,6 +136,7 @@ func (m *Model) Hide() {
 	m.visible = false
 	m.query = ""
 	m.selected = 0
+	m.resetArguments()
 }
 
 // IsVisible returns whether the command palette is visible
@@ -94,35 +153,191 @@ func (m *Model) SetSize(width, height int) {
 // SetMaxVisible sets the maximum number of commands rendered in the list.
 func (m *Model) SetMaxVisible(maxVisible int) {
 	if maxVisible <= 0 {
-		m.maxVisible = 8
+		m.maxVisible = defaultMaxVisible
 		return
 	}
 	m.maxVisible = maxVisible
 }
 
-// updateFiltered updates the filtered commands based on the current query
-func (m *Model) updateFiltered() {
-	if m.query == "" {
-		m.filteredCmds = m.commands
-	} else {
-		// Use fuzzy matching
-		var targets []string
-		for _, cmd := range m.commands {
-			targets = append(targets, cmd.Name)
+// Recent returns the keys (ID, or Name when empty) of recently executed commands, most recent
+// first. Applications can persist it and restore it with SetRecent.
+func (m Model) Recent() []string {
+	return append([]string(nil), m.recent...)
+}
+
+// SetRecent replaces the recently used list.
+func (m *Model) SetRecent(keys []string) {
+	m.recent = append([]string(nil), keys...)
+	if len(m.recent) > m.maxRecent {
+		m.recent = m.recent[:m.maxRecent]
+	}
+	m.updateFiltered()
+}
+
+// SetMaxRecent sets how many recently used commands are remembered; 0 turns recents off.
+func (m *Model) SetMaxRecent(n int) {
+	m.maxRecent = max(0, n)
+	if len(m.recent) > m.maxRecent {
+		m.recent = m.recent[:m.maxRecent]
+	}
+	m.updateFiltered()
+}
+
+// SelectedCommand returns the highlighted command.
+func (m Model) SelectedCommand() (Command, bool) {
+	if m.selected < 0 || m.selected >= len(m.filteredCmds) {
+		return Command{}, false
+	}
+	return m.filteredCmds[m.selected], true
+}
+
+// FilteredCommands returns the commands currently listed, in display order.
+func (m Model) FilteredCommands() []Command {
+	return append([]Command(nil), m.filteredCmds...)
+}
+
+func (m *Model) markRecent(c Command) {
+	if m.maxRecent == 0 {
+		return
+	}
+	k := c.key()
+	ret := []string{k}
+	for _, r := range m.recent {
+		if r != k && len(ret) < m.maxRecent {
+			ret = append(ret, r)
 		}
+	}
+	m.recent = ret
+}
 
-		matches := fuzzy.Find(m.query, targets)
-		m.filteredCmds = []Command{}
+func (m Model) recentRank(c Command) int {
+	k := c.key()
+	for i, r := range m.recent {
+		if r == k {
+			return i
+		}
+	}
+	return -1
+}
 
-		for _, match := range matches {
-			m.filteredCmds = append(m.filteredCmds, m.commands[match.Index])
+// updateFiltered updates the filtered commands based on the current query. Without a query,
+// recently used commands come first, followed by the others grouped by category in order of
+// first appearance. With a query, commands are ranked by their best fuzzy match over name and
+// keywords.
+func (m *Model) updateFiltered() {
+	enabled := make([]Command, 0, len(m.commands))
+	for _, c := range m.commands {
+		if c.Enabled == nil || c.Enabled() {
+			enabled = append(enabled, c)
 		}
 	}
 
+	if m.query == "" {
+		m.filteredCmds, m.groups = m.grouped(enabled)
+	} else {
+		m.filteredCmds, m.groups = m.ranked(enabled), nil
+	}
+
 	// Reset selection if out of bounds
 	if m.selected >= len(m.filteredCmds) {
 		m.selected = 0
 	}
+	m.ensureSelectedVisible()
+}
+
+func (m Model) grouped(commands []Command) ([]Command, []string) {
+	ret := make([]Command, 0, len(commands))
+	groups := make([]string, 0, len(commands))
+	for _, k := range m.recent {
+		for _, c := range commands {
+			if c.key() == k {
+				ret = append(ret, c)
+				groups = append(groups, recentGroup)
+				break
+			}
+		}
+	}
+
+	var categories []string
+	byCategory := map[string][]Command{}
+	for _, c := range commands {
+		if m.recentRank(c) >= 0 {
+			continue
+		}
+		if _, ok := byCategory[c.Category]; !ok {
+			categories = append(categories, c.Category)
+		}
+		byCategory[c.Category] = append(byCategory[c.Category], c)
+	}
+	for _, cat := range categories {
+		for _, c := range byCategory[cat] {
+			ret = append(ret, c)
+			groups = append(groups, cat)
+		}
+	}
+	return ret, groups
+}
+
+func (m Model) ranked(commands []Command) []Command {
+	var targets []string
+	var owners, penalties []int
+	for i, c := range commands {
+		targets = append(targets, c.Name)
+		owners = append(owners, i)
+		penalties = append(penalties, 0)
+		for _, kw := range c.Keywords {
+			targets = append(targets, kw)
+			owners = append(owners, i)
+			penalties = append(penalties, keywordPenalty)
+		}
+	}
+
+	scores := map[int]int{}
+	for _, match := range fuzzy.Find(m.query, targets) {
+		i := owners[match.Index]
+		score := match.Score - penalties[match.Index]
+		if best, ok := scores[i]; !ok || score > best {
+			scores[i] = score
+		}
+	}
+
+	idx := make([]int, 0, len(scores))
+	for i := range commands {
+		if _, ok := scores[i]; !ok {
+			continue
+		}
+		if m.recentRank(commands[i]) >= 0 {
+			scores[i] += recentBonus
+		}
+		idx = append(idx, i)
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return scores[idx[a]] > scores[idx[b]]
+	})
+
+	ret := make([]Command, 0, len(idx))
+	for _, i := range idx {
+		ret = append(ret, commands[i])
+	}
+	return ret
+}
+
+func (m *Model) ensureSelectedVisible() {
+	limit := m.visibleLimit()
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+limit {
+		m.offset = m.selected - limit + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.filteredCmds)-limit))
+}
+
+func (m Model) visibleLimit() int {
+	if m.maxVisible <= 0 {
+		return defaultMaxVisible
+	}
+	return m.maxVisible
 }
 
 // Init initializes the command palette model
@@ -132,26 +347,40 @@ func (m Model) Init() tea.Cmd {
 
 // Update handles command palette updates
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(theme.ChangedMsg); ok {
+		m.styles = StylesFromTheme(msg.Theme)
+		return m, nil
+	}
 	if !m.visible {
 		return m, nil
 	}
 
 	switch msg := msg.(type) {
+	case PickerMsg:
+		if m.step.picker != nil {
+			return m.updatePicker(msg.Msg)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.step.active() {
+			return m.updateArguments(msg)
+		}
 		switch msg.String() {
 		case "esc", "escape", "ctrl+p":
-			m.visible = false
-			m.query = ""
-			m.selected = 0
+			m.Hide()
 			return m, nil
 
 		case "enter":
 			if len(m.filteredCmds) > 0 && m.selected < len(m.filteredCmds) {
-				cmd := m.filteredCmds[m.selected].Action()
-				m.visible = false
-				m.query = ""
-				m.selected = 0
-				return m, cmd
+				selected := m.filteredCmds[m.selected]
+				m.markRecent(selected)
+				if len(selected.Params) > 0 && selected.Run != nil {
+					m.beginArguments(selected)
+					return m, nil
+				}
+				m.Hide()
+				return m, selected.execute(Args{})
 			}
 			return m, nil
 
@@ -159,63 +388,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			if m.selected > 0 {
 				m.selected--
 			}
+			m.ensureSelectedVisible()
 			return m, nil
 
 		case "down", "ctrl+j":
 			if m.selected < len(m.filteredCmds)-1 {
 				m.selected++
 			}
+			m.ensureSelectedVisible()
 			return m, nil
 
 		case "backspace":
 			if len(m.query) > 0 {
-				m.query = m.query[:len(m.query)-1]
-				if m.query == "" {
-					m.filteredCmds = m.commands
-				} else {
-					// Use fuzzy matching
-					var targets []string
-					for _, cmd := range m.commands {
-						targets = append(targets, cmd.Name)
-					}
-
-					matches := fuzzy.Find(m.query, targets)
-					m.filteredCmds = []Command{}
-
-					for _, match := range matches {
-						m.filteredCmds = append(m.filteredCmds, m.commands[match.Index])
-					}
-				}
-				// Reset selection if out of bounds
-				if m.selected >= len(m.filteredCmds) {
-					m.selected = 0
-				}
+				_, size := utf8.DecodeLastRuneInString(m.query)
+				m.query = m.query[:len(m.query)-size]
+				m.selected = 0
+				m.updateFiltered()
 			}
 			return m, nil
 
 		default:
-			if len(msg.String()) == 1 {
-				m.query += msg.String()
-				if m.query == "" {
-					m.filteredCmds = m.commands
-				} else {
-					// Use fuzzy matching
-					var targets []string
-					for _, cmd := range m.commands {
-						targets = append(targets, cmd.Name)
-					}
-
-					matches := fuzzy.Find(m.query, targets)
-					m.filteredCmds = []Command{}
-
-					for _, match := range matches {
-						m.filteredCmds = append(m.filteredCmds, m.commands[match.Index])
-					}
-				}
-				// Reset selection if out of bounds
-				if m.selected >= len(m.filteredCmds) {
-					m.selected = 0
-				}
+			if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt {
+				m.query += string(msg.Runes)
+				m.selected = 0
+				m.updateFiltered()
 			}
 			return m, nil
 		}
@@ -229,6 +425,9 @@ func (m Model) View() string {
 	if !m.visible {
 		return ""
 	}
+	if m.step.active() {
+		return m.viewArguments()
+	}
 
 	// Header
 	header := m.styles.Header.Render("Command Palette")
@@ -241,25 +440,36 @@ func (m Model) View() string {
 	query := m.styles.Query.Width(m.width - 12).Render(queryPrompt)
 
 	// Commands list
-	var commandLines []string
-	maxCommands := m.maxVisible
-	if maxCommands <= 0 {
-		maxCommands = 8
-	}
-
-	for i, cmd := range m.filteredCmds {
-		if i >= maxCommands {
+	rowWidth := m.width - 12
+	showGroups := false
+	for _, g := range m.groups {
+		if g != "" {
+			showGroups = true
 			break
 		}
+	}
+	var commandLines []string
+	end := min(len(m.filteredCmds), m.offset+m.visibleLimit())
+	for i := m.offset; i < end; i++ {
+		cmd := m.filteredCmds[i]
+		if showGroups && (i == m.offset || m.groups[i] != m.groups[i-1]) {
+			label := m.groups[i]
+			if label == "" {
+				label = "Other"
+			}
+			commandLines = append(commandLines, m.styles.Category.Render(label))
+		}
 
-		name := m.styles.CommandName.Render(cmd.Name)
-		desc := m.styles.CommandDescription.Render(" - " + cmd.Description)
-		line := name + desc
-
+		// while searching the category is shown on the row instead of as a group header
+		tag := ""
+		if m.groups == nil {
+			tag = cmd.Category
+		}
+		line := m.renderRow(cmd, tag, rowWidth-m.styles.Command.GetHorizontalFrameSize())
 		if i == m.selected {
-			line = m.styles.SelectedCommand.Width(m.width - 12).Render(line)
+			line = m.styles.SelectedCommand.Width(rowWidth).Render(line)
 		} else {
-			line = m.styles.Command.Width(m.width - 12).Render(line)
+			line = m.styles.Command.Width(rowWidth).Render(line)
 		}
 
 		commandLines = append(commandLines, line)
@@ -282,3 +492,40 @@ func (m Model) View() string {
 
 	return m.styles.Palette.Width(m.width - 8).Render(content)
 }
+
+// renderRow renders a command's name, description and category tag, with its key hint aligned
+// to the right edge of width. The description is truncated first when the row is too narrow.
+func (m Model) renderRow(cmd Command, tag string, width int) string {
+	desc := ""
+	if cmd.Description != "" {
+		desc = " - " + cmd.Description
+	}
+	if tag != "" {
+		tag = "  " + tag
+	}
+	hint := ""
+	if cmd.KeyHint != "" {
+		hint = " " + cmd.KeyHint
+	}
+	if width > 0 {
+		room := width - runewidth.StringWidth(cmd.Name) - runewidth.StringWidth(tag) - runewidth.StringWidth(hint)
+		if room < runewidth.StringWidth(desc) {
+			desc = runewidth.Truncate(desc, max(0, room), "…")
+		}
+	}
+
+	left := m.styles.CommandName.Render(cmd.Name) +
+		m.styles.CommandDescription.Render(desc) +
+		m.styles.Category.Render(tag)
+	if hint == "" {
+		return left
+	}
+	gap := max(0, width-lipgloss.Width(left)-runewidth.StringWidth(hint))
+	return left + strings.Repeat(" ", gap) + m.styles.KeyHint.Render(hint)
+}
+
+// CollectingArguments reports whether the palette is asking for the selected command's
+// parameters. Esc then steps back instead of closing the palette.
+func (m Model) CollectingArguments() bool {
+	return m.visible && m.step.active()
+}
@@ -0,0 +1,101 @@
+package commandpalette
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func noop() tea.Cmd { return nil }
+
+func names(cmds []Command) []string {
+	ret := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		ret = append(ret, c.Name)
+	}
+	return ret
+}
+
+func typeQuery(m Model, q string) Model {
+	for _, r := range q {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+func TestRankingPrefersNameOverKeywordMatches(t *testing.T) {
+	m := New()
+	m.SetCommands([]Command{
+		{ID: "history.clear", Name: "Clear History", Keywords: []string{"reset"}, Action: noop},
+		{ID: "input.reset", Name: "Reset Input", Action: noop},
+		{ID: "quit", Name: "Quit", Keywords: []string{"exit"}, Action: noop},
+	})
+	m.Show()
+
+	m = typeQuery(m, "reset")
+	require.Equal(t, []string{"Reset Input", "Clear History"}, names(m.FilteredCommands()))
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = typeQuery(m, "xt")
+	require.Empty(t, m.FilteredCommands())
+}
+
+func TestGroupsByCategoryWithRecentFirst(t *testing.T) {
+	var ran string
+	run := func(name string) func() tea.Cmd {
+		return func() tea.Cmd { ran = name; return nil }
+	}
+	m := New()
+	m.SetSize(100, 40)
+	m.SetCommands([]Command{
+		{Name: "Open", Category: "file", KeyHint: "ctrl+o", Action: run("Open")},
+		{Name: "Quit", Category: "app", Action: run("Quit")},
+		{Name: "Save", Category: "file", Action: run("Save")},
+	})
+	m.Show()
+	require.Equal(t, []string{"Open", "Save", "Quit"}, names(m.FilteredCommands()))
+	view := m.View()
+	require.Contains(t, view, "file")
+	require.Contains(t, view, "ctrl+o")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, "Save", ran)
+	require.Equal(t, []string{"Save"}, m.Recent())
+
+	m.Show()
+	require.Equal(t, []string{"Save", "Open", "Quit"}, names(m.FilteredCommands()))
+	require.Contains(t, m.View(), recentGroup)
+}
+
+func TestDisabledCommandsAreHidden(t *testing.T) {
+	enabled := false
+	m := New()
+	m.SetCommands([]Command{
+		{Name: "Cancel", Enabled: func() bool { return enabled }, Action: noop},
+		{Name: "Quit", Action: noop},
+	})
+	m.Show()
+	require.Equal(t, []string{"Quit"}, names(m.FilteredCommands()))
+
+	enabled = true
+	m.Show()
+	require.Equal(t, []string{"Cancel", "Quit"}, names(m.FilteredCommands()))
+}
+
+func TestSelectionScrollsPastVisibleRows(t *testing.T) {
+	m := New()
+	m.SetSize(80, 40)
+	m.SetMaxVisible(2)
+	m.SetCommands([]Command{{Name: "One", Action: noop}, {Name: "Two", Action: noop}, {Name: "Three", Action: noop}})
+	m.Show()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	selected, ok := m.SelectedCommand()
+	require.True(t, ok)
+	require.Equal(t, "Three", selected.Name)
+	view := m.View()
+	require.Contains(t, view, "Three")
+	require.NotContains(t, view, "One")
+}
@@ -1,6 +1,9 @@
 package commandpalette
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+)
 
 // Styles defines the styling for the command palette
 type Styles struct {
@@ -12,6 +15,12 @@ type Styles struct {
 	CommandName        lipgloss.Style
 	CommandDescription lipgloss.Style
 	Help               lipgloss.Style
+	// Category styles group headers and the category tag shown while searching.
+	Category lipgloss.Style
+	// KeyHint styles the key bound to a command.
+	KeyHint lipgloss.Style
+	// Error styles validation messages of the argument step.
+	Error lipgloss.Style
 }
 
 // DefaultStyles returns the default styles for the command palette
@@ -53,5 +62,68 @@ func DefaultStyles() Styles {
 		Help: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
 			Italic(true),
+
+		Category: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("212")).
+			Faint(true),
+
+		KeyHint: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("244")),
+
+		Error: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("203")),
+	}
+}
+
+// StylesFromTheme returns the palette styles for a theme.
+func StylesFromTheme(t theme.Theme) Styles {
+	p := t.Palette
+	return Styles{
+		Palette: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(p.Accent).
+			Background(p.Surface).
+			Padding(1).
+			Margin(2, 4),
+
+		Header: lipgloss.NewStyle().
+			Foreground(p.Secondary).
+			Bold(true).
+			Margin(0, 0, 1, 0),
+
+		Query: lipgloss.NewStyle().
+			Foreground(p.Foreground).
+			Background(p.Border).
+			Padding(0, 1).
+			Margin(0, 0, 1, 0),
+
+		Command: lipgloss.NewStyle().
+			Padding(0, 1),
+
+		SelectedCommand: lipgloss.NewStyle().
+			Background(p.Selection).
+			Foreground(p.SelectionForeground).
+			Padding(0, 1),
+
+		CommandName: lipgloss.NewStyle().
+			Foreground(p.Accent).
+			Bold(true),
+
+		CommandDescription: lipgloss.NewStyle().
+			Foreground(p.Muted),
+
+		Help: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Italic(true),
+
+		Category: lipgloss.NewStyle().
+			Foreground(p.Secondary).
+			Faint(true),
+
+		KeyHint: lipgloss.NewStyle().
+			Foreground(p.Muted),
+
+		Error: lipgloss.NewStyle().
+			Foreground(p.Error),
 	}
 }
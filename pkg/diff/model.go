@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 )
 
 type focus int
@@ -96,6 +97,9 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
+	case theme.ChangedMsg:
+		m.SetStyles(StylesFromTheme(msg.Theme))
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -198,6 +202,12 @@ func (m *Model) SetSize(width, height int) {
 	m.updateDetailContent()
 }
 
+// SetStyles replaces the styles, e.g. with StylesFromTheme.
+func (m *Model) SetStyles(styles Styles) {
+	m.styles = styles
+	m.updateDetailContent()
+}
+
 func (m *Model) SetRedactSensitive(enabled bool) {
 	m.redacted = enabled
 	m.updateDetailContent()
@@ -2,6 +2,7 @@ package diff
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 )
 
 // Styles contains style definitions used by the diff component.
@@ -65,3 +66,32 @@ func defaultStyles() Styles {
 		FilterOff:      lipgloss.NewStyle().Faint(true),
 	}
 }
+
+// StylesFromTheme returns the diff styles for a theme.
+func StylesFromTheme(t theme.Theme) Styles {
+	p := t.Palette
+	frame := func(border lipgloss.Color) lipgloss.Style {
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(border).
+			Padding(0, 1)
+	}
+	return Styles{
+		Title:          lipgloss.NewStyle().Bold(true).Foreground(p.Foreground).Padding(0, 1),
+		ListBase:       frame(p.Border).MarginRight(1),
+		ListFocused:    frame(p.Accent).MarginRight(1),
+		DetailBase:     frame(p.Border),
+		DetailFocused:  frame(p.Accent),
+		CategoryHeader: lipgloss.NewStyle().Bold(true).Foreground(p.Secondary),
+		Path:           lipgloss.NewStyle().Foreground(p.Muted),
+		RemovedLine:    lipgloss.NewStyle().Foreground(p.Error),
+		AddedLine:      lipgloss.NewStyle().Foreground(p.Success),
+		UpdatedLine:    lipgloss.NewStyle().Foreground(p.Warning),
+		SensitiveValue: lipgloss.NewStyle().Foreground(p.Muted),
+		BadgeAdded:     lipgloss.NewStyle().Foreground(p.Success).Bold(true),
+		BadgeRemoved:   lipgloss.NewStyle().Foreground(p.Error).Bold(true),
+		BadgeUpdated:   lipgloss.NewStyle().Foreground(p.Warning).Bold(true),
+		FilterOn:       lipgloss.NewStyle().Bold(true).Foreground(p.Foreground),
+		FilterOff:      lipgloss.NewStyle().Foreground(p.Muted),
+	}
+}
@@ -3,7 +3,7 @@ package filepicker
 import (
 	"bufio"
 	"fmt"
-	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -15,6 +15,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 )
 
 // Messages for compatibility with existing bobatea filepicker API
@@ -47,6 +48,7 @@ const (
 	ViewStateCreateDir
 	ViewStateSearch
 	ViewStateGlob
+	ViewStateFind
 )
 
 // Operation represents file operations
@@ -118,6 +120,37 @@ type AdvancedModel struct {
 
 	// Directory restriction (jail)
 	jailDirectory string // Absolute path of the jail directory, empty means no restriction
+
+	// Filesystem backend; the local disk unless set with WithFS
+	fsys fs.FS
+	loc  location
+
+	// Asynchronous directory loading
+	loading    bool
+	loadSeq    int    // Sequence number of the latest load, older listings are dropped
+	listedPath string // Directory the files were listed from
+	// reloadPending is set by setters that change the listing, the next Update reloads it
+	reloadPending bool
+
+	// Background file operations
+	op         *fileOp
+	opSeq      int
+	opErr      error // Summary of the last operation, shown until the next key press
+	opFailures []fileOpFailure
+
+	// Recursive find mode
+	finder finder
+	reveal string // Name to put the cursor on once the directory is loaded
+
+	// Undo journal
+	useTrash  bool // Delete to the trash when the filesystem has one
+	undoStack []journalEntry
+	redoStack []journalEntry
+
+	// Live updates
+	watch    bool // Watch the local disk for changes made by other programs
+	watcher  *dirWatcher
+	watchSeq int
 }
 
 // advancedKeyMap defines the key bindings for the advanced file picker
@@ -143,6 +176,8 @@ type advancedKeyMap struct {
 	Rename  key.Binding
 	NewFile key.Binding
 	NewDir  key.Binding
+	Undo    key.Binding
+	Redo    key.Binding
 
 	// Navigation
 	Escape    key.Binding
@@ -154,6 +189,7 @@ type advancedKeyMap struct {
 	// Tier 4 features
 	TogglePreview key.Binding
 	Search        key.Binding
+	Find          key.Binding
 	Glob          key.Binding
 	ClearGlob     key.Binding
 	ToggleHidden  key.Binding
@@ -179,9 +215,9 @@ func (k advancedKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Home, k.End},
 		{k.Enter, k.Space, k.SelectAll, k.DeselectAll},
-		{k.Copy, k.Cut, k.Paste, k.Delete},
+		{k.Copy, k.Cut, k.Paste, k.Delete, k.Undo, k.Redo},
 		{k.Rename, k.NewFile, k.NewDir, k.Refresh},
-		{k.TogglePreview, k.Search, k.Glob, k.ClearGlob, k.ToggleHidden, k.ToggleDetail},
+		{k.TogglePreview, k.Search, k.Find, k.Glob, k.ClearGlob, k.ToggleHidden, k.ToggleDetail},
 		{k.CycleSort, k.Backspace, k.Back, k.Forward},
 		{k.SelectCurrentDir, k.ToggleDirSelection},
 		{k.Escape, k.Help, k.Quit},
@@ -200,9 +236,9 @@ func (fp *AdvancedModel) FullHelpForMode() [][]key.Binding {
 		return [][]key.Binding{
 			{fp.keys.Up, fp.keys.Down, fp.keys.Home, fp.keys.End},
 			{fp.keys.Enter, spaceKey, fp.keys.SelectAll, fp.keys.DeselectAll},
-			{fp.keys.Copy, fp.keys.Cut, fp.keys.Paste, fp.keys.Delete},
+			{fp.keys.Copy, fp.keys.Cut, fp.keys.Paste, fp.keys.Delete, fp.keys.Undo, fp.keys.Redo},
 			{fp.keys.Rename, fp.keys.NewFile, fp.keys.NewDir, fp.keys.Refresh},
-			{fp.keys.TogglePreview, fp.keys.Search, fp.keys.Glob, fp.keys.ClearGlob, fp.keys.ToggleHidden, fp.keys.ToggleDetail},
+			{fp.keys.TogglePreview, fp.keys.Search, fp.keys.Find, fp.keys.Glob, fp.keys.ClearGlob, fp.keys.ToggleHidden, fp.keys.ToggleDetail},
 			{fp.keys.CycleSort, fp.keys.Backspace, fp.keys.Back, fp.keys.Forward},
 			{fp.keys.SelectCurrentDir, fp.keys.ToggleDirSelection},
 			{fp.keys.Escape, fp.keys.Help, fp.keys.Quit},
@@ -280,6 +316,14 @@ func defaultAdvancedKeyMap() advancedKeyMap {
 			key.WithKeys("m"),
 			key.WithHelp("m", "new directory"),
 		),
+		Undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo"),
+		),
+		Redo: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "redo"),
+		),
 		Escape: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "cancel"),
@@ -308,6 +352,10 @@ func defaultAdvancedKeyMap() advancedKeyMap {
 			key.WithKeys("/"),
 			key.WithHelp("/", "search"),
 		),
+		Find: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "find recursively"),
+		),
 		Glob: key.NewBinding(
 			key.WithKeys("g"),
 			key.WithHelp("g", "glob filter"),
@@ -403,6 +451,10 @@ var (
 
 	errorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("196"))
+
+	matchStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Bold(true)
 )
 
 // Option represents a configuration option for the file picker
@@ -493,23 +545,47 @@ func WithGlobPattern(pattern string) Option {
 	}
 }
 
-// WithJailDirectory sets a directory restriction boundary - navigation will be limited to this directory and subdirectories
+// WithJailDirectory sets a directory restriction boundary - navigation will be limited to this directory and subdirectories.
+// File operations are checked against it too, see Jail.
 func WithJailDirectory(path string) Option {
 	return func(fp *AdvancedModel) {
-		if absPath, err := filepath.Abs(path); err == nil {
-			fp.jailDirectory = absPath
-		}
+		fp.jailDirectory = path
 	}
 }
 
-// New creates a new file picker with the specified options
-func New(options ...Option) *AdvancedModel {
-	// Get current working directory as default
-	wd, err := os.Getwd()
-	if err != nil {
-		wd = "."
+// WithFS makes the picker browse fsys instead of the local disk: an archive (zip.Reader),
+// an embed.FS, an fstest.MapFS in tests or an adapter to a remote store. File operations
+// need fsys to be a WritableFS and report ErrReadOnly otherwise. The paths the picker
+// shows and returns are slash separated and rooted at "/", e.g. "/docs/readme.md"; the
+// start path defaults to "/".
+func WithFS(fsys fs.FS) Option {
+	return func(fp *AdvancedModel) {
+		fp.fsys = fsys
 	}
+}
+
+// WithTrash sets whether deleting moves files to the trash, a freedesktop.org trash
+// on the local disk, so the delete can be undone. It is on by default; on a
+// filesystem that is not a TrashFS files are always deleted permanently.
+func WithTrash(enabled bool) Option {
+	return func(fp *AdvancedModel) {
+		fp.useTrash = enabled
+	}
+}
 
+// WithWatch sets whether the picker watches the current directory, and the directory
+// shown in the preview, for changes made by other programs. It is off by default. The
+// watcher starts with Init and stops once a file is chosen, the picker is cancelled or
+// Close is called; a program that enables it must call Close when it drops the picker
+// before that. Only the local disk is watched.
+func WithWatch(enabled bool) Option {
+	return func(fp *AdvancedModel) {
+		fp.watch = enabled
+	}
+}
+
+// New creates a new file picker with the specified options
+func New(options ...Option) *AdvancedModel {
 	ti := textinput.New()
 	ti.Placeholder = "Enter name..."
 	ti.CharLimit = 255
@@ -523,7 +599,6 @@ func New(options ...Option) *AdvancedModel {
 	gi.CharLimit = 100
 
 	fp := &AdvancedModel{
-		currentPath:    wd,
 		showIcons:      true,
 		showSizes:      true,
 		multiSelected:  make(map[string]bool),
@@ -533,6 +608,7 @@ func New(options ...Option) *AdvancedModel {
 		textInput:      ti,
 		searchInput:    si,
 		globInput:      gi,
+		finder:         newFinder(),
 		help:           help.New(),
 		keys:           defaultAdvancedKeyMap(),
 		showPreview:    true,
@@ -543,6 +619,7 @@ func New(options ...Option) *AdvancedModel {
 		history:        make([]string, 0),
 		historyIndex:   -1,
 		maxHistorySize: 50,
+		useTrash:       true,
 	}
 
 	// Apply options
@@ -550,10 +627,7 @@ func New(options ...Option) *AdvancedModel {
 		option(fp)
 	}
 
-	// Resolve the starting path
-	if absPath, err := filepath.Abs(fp.currentPath); err == nil {
-		fp.currentPath = absPath
-	}
+	fp.setupFS()
 
 	// Add initial directory to history
 	fp.addToHistory(fp.currentPath)
@@ -571,6 +645,45 @@ func New(options ...Option) *AdvancedModel {
 	return fp
 }
 
+// setupFS resolves the start and jail paths against the filesystem, the local disk
+// rooted at the volume of the start path unless WithFS was given, and applies the jail.
+func (fp *AdvancedModel) setupFS() {
+	if fp.fsys == nil {
+		if fp.currentPath == "" {
+			// Get current working directory as default
+			wd, err := os.Getwd()
+			if err != nil {
+				wd = "."
+			}
+			fp.currentPath = wd
+		}
+		if absPath, err := filepath.Abs(fp.currentPath); err == nil {
+			fp.currentPath = absPath
+		}
+		fp.loc = localLocation(fp.currentPath)
+		fp.fsys = DirFS(fp.loc.root)
+	} else {
+		fp.loc = virtualLocation
+		if fp.currentPath == "" {
+			fp.currentPath = "/"
+		}
+		fp.currentPath, _ = fp.loc.abs(fp.currentPath)
+	}
+
+	if fp.jailDirectory == "" {
+		return
+	}
+	absJail, err := fp.loc.abs(fp.jailDirectory)
+	if err != nil {
+		fp.jailDirectory = ""
+		return
+	}
+	fp.jailDirectory = fp.loc.clean(absJail)
+	if name, err := fp.loc.name(fp.jailDirectory); err == nil {
+		fp.fsys = Jail(fp.fsys, name)
+	}
+}
+
 // NewAdvancedModel creates a new advanced file picker
 // Deprecated: Use New(WithStartPath(startPath)) instead
 func NewAdvancedModel(startPath string) *AdvancedModel {
@@ -583,18 +696,18 @@ func (fp *AdvancedModel) isWithinJail(path string) bool {
 		return true // No jail restriction
 	}
 
-	// Resolve to absolute path
-	absPath, err := filepath.Abs(path)
+	// Compare filesystem names, which are clean and slash separated
+	name, err := fp.loc.name(path)
+	if err != nil {
+		return false
+	}
+	jailName, err := fp.loc.name(fp.jailDirectory)
 	if err != nil {
 		return false
 	}
-
-	// Clean paths to handle .. and . components
-	cleanJail := filepath.Clean(fp.jailDirectory)
-	cleanPath := filepath.Clean(absPath)
 
 	// Check if path is within jail (must be equal or a subdirectory)
-	return cleanPath == cleanJail || strings.HasPrefix(cleanPath+string(filepath.Separator), cleanJail+string(filepath.Separator))
+	return withinDir(name, jailName)
 }
 
 // isAtJailRoot checks if the current path is at the jail root
@@ -603,8 +716,8 @@ func (fp *AdvancedModel) isAtJailRoot() bool {
 		return false // No jail restriction
 	}
 
-	cleanJail := filepath.Clean(fp.jailDirectory)
-	cleanCurrent := filepath.Clean(fp.currentPath)
+	cleanJail := fp.loc.clean(fp.jailDirectory)
+	cleanCurrent := fp.loc.clean(fp.currentPath)
 	return cleanJail == cleanCurrent
 }
 
@@ -615,7 +728,7 @@ func (fp *AdvancedModel) validateNavigationPath(path string) bool {
 	}
 
 	// Resolve to absolute path
-	absPath, err := filepath.Abs(path)
+	absPath, err := fp.loc.abs(path)
 	if err != nil {
 		return false
 	}
@@ -691,13 +804,15 @@ func (m Model) Init() tea.Cmd {
 // Update handles updates for the compatibility wrapper
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Sync compatibility settings to advanced model if they changed
+	var loadCmd tea.Cmd
 	if m.Filepicker.CurrentDirectory != m.currentPath {
 		m.currentPath = m.Filepicker.CurrentDirectory
-		m.loadDirectory()
+		loadCmd = m.loadDirectoryCmd()
 	}
 
 	// Delegate to the advanced model
 	updatedAdvanced, cmd := m.AdvancedModel.Update(msg)
+	cmd = tea.Batch(loadCmd, cmd)
 
 	// Update our wrapper
 	m.AdvancedModel = updatedAdvanced.(*AdvancedModel)
@@ -734,9 +849,9 @@ func (m Model) View() string {
 	return m.AdvancedModel.View()
 }
 
-// Init initializes the file picker
+// Init initializes the file picker and starts watching the current directory
 func (fp *AdvancedModel) Init() tea.Cmd {
-	return nil
+	return fp.startWatching()
 }
 
 // addToHistory adds a directory to the navigation history
@@ -782,9 +897,9 @@ func (fp *AdvancedModel) canGoForward() bool {
 }
 
 // goBack navigates to the previous directory in history
-func (fp *AdvancedModel) goBack() {
+func (fp *AdvancedModel) goBack() tea.Cmd {
 	if !fp.canGoBack() {
-		return
+		return nil
 	}
 
 	if fp.historyIndex == -1 {
@@ -793,30 +908,30 @@ func (fp *AdvancedModel) goBack() {
 		fp.historyIndex--
 	}
 
-	fp.navigateToHistoryIndex()
+	return fp.navigateToHistoryIndex()
 }
 
 // goForward navigates to the next directory in history
-func (fp *AdvancedModel) goForward() {
+func (fp *AdvancedModel) goForward() tea.Cmd {
 	if !fp.canGoForward() {
-		return
+		return nil
 	}
 
 	fp.historyIndex++
-	fp.navigateToHistoryIndex()
+	return fp.navigateToHistoryIndex()
 }
 
 // navigateToHistoryIndex navigates to the directory at the current history index
-func (fp *AdvancedModel) navigateToHistoryIndex() {
+func (fp *AdvancedModel) navigateToHistoryIndex() tea.Cmd {
 	if fp.historyIndex < 0 || fp.historyIndex >= len(fp.history) {
-		return
+		return nil
 	}
 
 	targetPath := fp.history[fp.historyIndex]
 
 	// Validate against jail directory
 	if !fp.isWithinJail(targetPath) {
-		return
+		return nil
 	}
 
 	fp.currentPath = targetPath
@@ -824,20 +939,60 @@ func (fp *AdvancedModel) navigateToHistoryIndex() {
 	fp.multiSelected = make(map[string]bool)
 	fp.searchQuery = ""
 	fp.globPattern = ""
-	fp.loadDirectory()
+	return fp.loadDirectoryCmd()
 }
 
 // Update handles messages for Tier 4
 func (fp *AdvancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !fp.reloadPending {
+		return fp.update(msg)
+	}
+	// A setter changed what the listing shows since the last message
+	reload := fp.loadDirectoryCmd()
+	m, cmd := fp.update(msg)
+	return m, tea.Batch(reload, cmd)
+}
+
+func (fp *AdvancedModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// Stop watching once a file is chosen or the picker is cancelled
+	finished := fp.cancelled || len(fp.selectedFiles) > 0
+	defer func() {
+		if !finished && (fp.cancelled || len(fp.selectedFiles) > 0) {
+			fp.stopWatching()
+		}
+	}()
+
 	switch msg := msg.(type) {
+	case theme.ChangedMsg:
+		ApplyTheme(msg.Theme)
+
 	case tea.WindowSizeMsg:
 		fp.width = msg.Width
 		fp.height = msg.Height
 		fp.help.Width = msg.Width
 
+	case directoryLoadedMsg:
+		fp.handleDirectoryLoaded(msg)
+
+	case fileOpProgressMsg:
+		return fp, fp.handleFileOpProgress(msg)
+
+	case fileOpDoneMsg:
+		return fp, fp.handleFileOpDone(msg)
+
+	case finderBatchMsg:
+		return fp, fp.handleFinderBatch(msg)
+
+	case dirChangedMsg:
+		return fp, fp.handleDirChanged(msg)
+
 	case tea.KeyMsg:
+		if fp.op == nil {
+			// The summary of the last file operation stays until the next key press
+			fp.opErr, fp.opFailures = nil, nil
+		}
 		switch fp.viewState {
 		case ViewStateNormal:
 			return fp.updateNormal(msg)
@@ -849,6 +1004,8 @@ func (fp *AdvancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return fp.updateSearch(msg)
 		case ViewStateGlob:
 			return fp.updateGlob(msg)
+		case ViewStateFind:
+			return fp.updateFind(msg)
 		}
 	}
 
@@ -859,9 +1016,13 @@ func (fp *AdvancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, fp.keys.Quit):
+		fp.cancelFileOp()
 		fp.cancelled = true
 		return fp, tea.Quit
 
+	case key.Matches(msg, fp.keys.Escape) && fp.op != nil:
+		fp.cancelFileOp()
+
 	case key.Matches(msg, fp.keys.Escape):
 		if fp.searchQuery != "" {
 			fp.searchQuery = ""
@@ -886,6 +1047,9 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		fp.viewState = ViewStateSearch
 		return fp, textinput.Blink
 
+	case key.Matches(msg, fp.keys.Find):
+		return fp, fp.startFind()
+
 	case key.Matches(msg, fp.keys.Glob):
 		fp.globInput.SetValue(fp.globPattern)
 		fp.globInput.Focus()
@@ -898,7 +1062,7 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, fp.keys.ToggleHidden):
 		fp.showHidden = !fp.showHidden
-		fp.loadDirectory()
+		return fp, fp.loadDirectoryCmd()
 
 	case key.Matches(msg, fp.keys.ToggleDetail):
 		fp.detailedView = !fp.detailedView
@@ -996,7 +1160,7 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Always navigate into directories, regardless of mode
 				var newPath string
 				if selectedFile.Name == ".." {
-					newPath = filepath.Dir(fp.currentPath)
+					newPath = fp.loc.dir(fp.currentPath)
 				} else {
 					newPath = selectedFile.Path
 				}
@@ -1008,7 +1172,7 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					fp.multiSelected = make(map[string]bool)
 					fp.searchQuery = ""
 					fp.globPattern = ""
-					fp.loadDirectory()
+					return fp, fp.loadDirectoryCmd()
 				}
 			} else {
 				// For files, behavior depends on mode
@@ -1032,7 +1196,7 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case key.Matches(msg, fp.keys.Backspace):
-		newPath := filepath.Dir(fp.currentPath)
+		newPath := fp.loc.dir(fp.currentPath)
 		// Prevent navigation outside jail directory
 		if fp.validateNavigationPath(newPath) {
 			fp.currentPath = newPath
@@ -1041,17 +1205,17 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			fp.multiSelected = make(map[string]bool)
 			fp.searchQuery = ""
 			fp.globPattern = ""
-			fp.loadDirectory()
+			return fp, fp.loadDirectoryCmd()
 		}
 
 	case key.Matches(msg, fp.keys.Back):
-		fp.goBack()
+		return fp, fp.goBack()
 
 	case key.Matches(msg, fp.keys.Forward):
-		fp.goForward()
+		return fp, fp.goForward()
 
 	case key.Matches(msg, fp.keys.Refresh):
-		fp.loadDirectory()
+		return fp, fp.loadDirectoryCmd()
 
 	case key.Matches(msg, fp.keys.Delete):
 		filesToDelete := fp.getSelectedFiles()
@@ -1070,9 +1234,15 @@ func (fp *AdvancedModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case key.Matches(msg, fp.keys.Paste):
 		if len(fp.clipboard) > 0 {
-			fp.performPaste()
+			return fp, fp.performPaste()
 		}
 
+	case key.Matches(msg, fp.keys.Undo):
+		return fp, fp.undo()
+
+	case key.Matches(msg, fp.keys.Redo):
+		return fp, fp.redo()
+
 	case key.Matches(msg, fp.keys.Rename):
 		if len(fp.filteredFiles) > 0 && fp.filteredFiles[fp.cursor].Name != ".." {
 			fp.textInput.SetValue(fp.filteredFiles[fp.cursor].Name)
@@ -1146,8 +1316,8 @@ func (fp *AdvancedModel) updateGlob(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (fp *AdvancedModel) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "y", "Y":
-		fp.performDelete()
 		fp.viewState = ViewStateNormal
+		return fp, fp.performDelete()
 	case "n", "N", "esc":
 		fp.viewState = ViewStateNormal
 	}
@@ -1164,12 +1334,12 @@ func (fp *AdvancedModel) updateTextInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if name != "" {
 			switch fp.viewState {
 			case ViewStateRename:
-				fp.performRename(name)
+				cmd = fp.performRename(name)
 			case ViewStateCreateFile:
-				fp.performCreateFile(name)
+				cmd = fp.performCreateFile(name)
 			case ViewStateCreateDir:
-				fp.performCreateDir(name)
-			case ViewStateNormal, ViewStateConfirmDelete, ViewStateSearch, ViewStateGlob:
+				cmd = fp.performCreateDir(name)
+			case ViewStateNormal, ViewStateConfirmDelete, ViewStateSearch, ViewStateGlob, ViewStateFind:
 				// These states shouldn't be handled here
 			}
 		}
@@ -1263,18 +1433,21 @@ func (fp *AdvancedModel) sortFiles() {
 
 // updatePreview updates the preview content for current file
 func (fp *AdvancedModel) updatePreview() {
+	fp.updateWatches()
 	if !fp.showPreview || len(fp.filteredFiles) == 0 {
 		fp.previewContent = ""
 		return
 	}
 
-	file := fp.filteredFiles[fp.cursor]
+	fp.previewContent = fp.buildPreview(fp.filteredFiles[fp.cursor])
+}
 
+// buildPreview builds the preview content for a file or directory
+func (fp *AdvancedModel) buildPreview(file File) string {
 	if file.IsDir {
-		fp.previewContent = fp.buildDirectoryPreview(file)
-	} else {
-		fp.previewContent = fp.buildFilePreview(file)
+		return fp.buildDirectoryPreview(file)
 	}
+	return fp.buildFilePreview(file)
 }
 
 // buildDirectoryPreview builds preview content for directories
@@ -1287,7 +1460,7 @@ func (fp *AdvancedModel) buildDirectoryPreview(file File) string {
 	fmt.Fprintf(&content, "Permissions: %s\n", file.Mode.String())
 
 	// Try to count items in directory
-	if entries, err := os.ReadDir(file.Path); err == nil {
+	if entries, err := fp.readDir(file.Path); err == nil {
 		visibleCount := 0
 		hiddenCount := 0
 		for _, entry := range entries {
@@ -1326,7 +1499,7 @@ func (fp *AdvancedModel) buildFilePreview(file File) string {
 		}
 	} else if fp.isImageFile(file.Name) {
 		content.WriteString("[Image file]\n")
-		if info, err := os.Stat(file.Path); err == nil {
+		if info, err := fp.stat(file.Path); err == nil {
 			fmt.Fprintf(&content, "Size: %dx? pixels\n", info.Size())
 		}
 	} else if fp.isArchiveFile(file.Name) {
@@ -1385,7 +1558,11 @@ func (fp *AdvancedModel) isArchiveFile(filename string) bool {
 
 // readFilePreview reads a preview of a text file
 func (fp *AdvancedModel) readFilePreview(filePath string) string {
-	file, err := os.Open(filePath)
+	name, err := fp.loc.name(filePath)
+	if err != nil {
+		return ""
+	}
+	file, err := fp.fsys.Open(name)
 	if err != nil {
 		return ""
 	}
@@ -1431,135 +1608,151 @@ func (fp *AdvancedModel) getSelectedFiles() []string {
 	return []string{}
 }
 
-// File operation methods (same as Tier 3, but working with filteredFiles)
-func (fp *AdvancedModel) performDelete() {
-	for _, filePath := range fp.confirmFiles {
-		if err := os.RemoveAll(filePath); err != nil {
-			fp.err = fmt.Errorf("failed to delete %s: %v", filepath.Base(filePath), err)
-			return
-		}
-		delete(fp.multiSelected, filePath)
+// File operation methods (same as Tier 3, but working with filteredFiles). They go
+// through the picker's filesystem, so the jail applies to them as well. Deleting and
+// pasting run in the background, see startFileOp.
+func (fp *AdvancedModel) performDelete() tea.Cmd {
+	w, err := fp.fileOpFS()
+	if err != nil {
+		fp.err = fmt.Errorf("failed to delete: %v", err)
+		return nil
 	}
-	fp.loadDirectory()
+	return fp.startDelete(w, fp.confirmFiles)
 }
 
-func (fp *AdvancedModel) performPaste() {
-	for _, src := range fp.clipboard {
-		dst := filepath.Join(fp.currentPath, filepath.Base(src))
-
-		switch fp.clipboardOp {
-		case OpCopy:
-			if err := fp.copyFile(src, dst); err != nil {
-				fp.err = fmt.Errorf("failed to copy %s: %v", filepath.Base(src), err)
-				return
-			}
-		case OpCut:
-			if err := os.Rename(src, dst); err != nil {
-				fp.err = fmt.Errorf("failed to move %s: %v", filepath.Base(src), err)
-				return
-			}
-		case OpNone:
-			// No operation to perform
-		}
+func (fp *AdvancedModel) performPaste() tea.Cmd {
+	w, err := fp.fileOpFS()
+	if err != nil {
+		fp.err = fmt.Errorf("failed to paste: %v", err)
+		return nil
 	}
 
-	if fp.clipboardOp == OpCut {
-		fp.clipboard = []string{}
-		fp.clipboardOp = OpNone
+	switch fp.clipboardOp {
+	case OpCopy:
+		return fp.startCopy(w, fp.clipboard)
+	case OpCut:
+		return fp.startMove(w, fp.clipboard)
+	case OpNone:
+		// No operation to perform
 	}
-
-	fp.loadDirectory()
+	return nil
 }
 
-func (fp *AdvancedModel) copyFile(src, dst string) error {
-	srcInfo, err := os.Stat(src)
-	if err != nil {
-		return err
-	}
+func (fp *AdvancedModel) performRename(newName string) tea.Cmd {
+	if len(fp.filteredFiles) > 0 && fp.filteredFiles[fp.cursor].Name != ".." {
+		oldPath := fp.filteredFiles[fp.cursor].Path
+		newPath := fp.loc.join(fp.currentPath, newName)
+
+		var step *journalStep
+		w, err := fp.writableFS()
+		if err == nil {
+			err = fp.withNames(func(names ...string) error {
+				step = &journalStep{kind: stepRename, from: names[0], name: names[1]}
+				return w.Rename(names[0], names[1])
+			}, oldPath, newPath)
+		}
+		if err != nil {
+			fp.err = fmt.Errorf("failed to rename: %v", err)
+			return nil
+		}
+		fp.record("rename", step)
 
-	if srcInfo.IsDir() {
-		return fp.copyDir(src, dst)
+		delete(fp.multiSelected, oldPath)
+		return fp.loadDirectoryCmd()
 	}
+	return nil
+}
 
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = srcFile.Close() // Ignore close errors in defer
-	}()
+func (fp *AdvancedModel) performCreateFile(name string) tea.Cmd {
+	filePath := fp.loc.join(fp.currentPath, name)
 
-	dstFile, err := os.Create(dst)
+	var step *journalStep
+	w, err := fp.writableFS()
+	if err == nil {
+		err = fp.withNames(func(names ...string) error {
+			step = &journalStep{kind: stepCreate, name: names[0]}
+			file, err := w.Create(names[0])
+			if err != nil {
+				return err
+			}
+			return file.Close()
+		}, filePath)
+	}
 	if err != nil {
-		return err
+		fp.err = fmt.Errorf("failed to create file: %v", err)
+		return nil
 	}
-	defer func() {
-		_ = dstFile.Close() // Ignore close errors in defer
-	}()
+	fp.record("create", step)
 
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	return fp.loadDirectoryCmd()
 }
 
-func (fp *AdvancedModel) copyDir(src, dst string) error {
-	if err := os.MkdirAll(dst, 0755); err != nil {
-		return err
-	}
+func (fp *AdvancedModel) performCreateDir(name string) tea.Cmd {
+	dirPath := fp.loc.join(fp.currentPath, name)
 
-	entries, err := os.ReadDir(src)
+	var step *journalStep
+	w, err := fp.writableFS()
+	if err == nil {
+		err = fp.withNames(func(names ...string) error {
+			step = &journalStep{kind: stepMkdir, name: names[0]}
+			return w.Mkdir(names[0], 0755)
+		}, dirPath)
+	}
 	if err != nil {
-		return err
+		fp.err = fmt.Errorf("failed to create directory: %v", err)
+		return nil
 	}
+	fp.record("mkdir", step)
 
-	for _, entry := range entries {
-		srcPath := filepath.Join(src, entry.Name())
-		dstPath := filepath.Join(dst, entry.Name())
+	return fp.loadDirectoryCmd()
+}
 
-		if err := fp.copyFile(srcPath, dstPath); err != nil {
-			return err
-		}
+// fileOpFS returns the filesystem for a background operation, unless one is running.
+func (fp *AdvancedModel) fileOpFS() (WritableFS, error) {
+	if fp.op != nil {
+		return nil, fmt.Errorf("a %s is still running", fp.op.verb)
 	}
-
-	return nil
+	return fp.writableFS()
 }
 
-func (fp *AdvancedModel) performRename(newName string) {
-	if len(fp.filteredFiles) > 0 && fp.filteredFiles[fp.cursor].Name != ".." {
-		oldPath := fp.filteredFiles[fp.cursor].Path
-		newPath := filepath.Join(fp.currentPath, newName)
+// writableFS returns the picker's filesystem if it supports file operations.
+func (fp *AdvancedModel) writableFS() (WritableFS, error) {
+	w, ok := fp.fsys.(WritableFS)
+	if !ok {
+		return nil, ErrReadOnly
+	}
+	return w, nil
+}
 
-		if err := os.Rename(oldPath, newPath); err != nil {
-			fp.err = fmt.Errorf("failed to rename: %v", err)
-			return
+// withNames calls f with the filesystem names of the picker paths.
+func (fp *AdvancedModel) withNames(f func(names ...string) error, paths ...string) error {
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		name, err := fp.loc.name(p)
+		if err != nil {
+			return err
 		}
-
-		delete(fp.multiSelected, oldPath)
-		fp.loadDirectory()
+		names[i] = name
 	}
+	return f(names...)
 }
 
-func (fp *AdvancedModel) performCreateFile(name string) {
-	filePath := filepath.Join(fp.currentPath, name)
-
-	file, err := os.Create(filePath)
+// readDir lists the directory at the picker path p.
+func (fp *AdvancedModel) readDir(p string) ([]fs.DirEntry, error) {
+	name, err := fp.loc.name(p)
 	if err != nil {
-		fp.err = fmt.Errorf("failed to create file: %v", err)
-		return
+		return nil, err
 	}
-	_ = file.Close() // Ignore close errors
-
-	fp.loadDirectory()
+	return fs.ReadDir(fp.fsys, name)
 }
 
-func (fp *AdvancedModel) performCreateDir(name string) {
-	dirPath := filepath.Join(fp.currentPath, name)
-
-	if err := os.Mkdir(dirPath, 0755); err != nil {
-		fp.err = fmt.Errorf("failed to create directory: %v", err)
-		return
+// stat describes the file at the picker path p.
+func (fp *AdvancedModel) stat(p string) (fs.FileInfo, error) {
+	name, err := fp.loc.name(p)
+	if err != nil {
+		return nil, err
 	}
-
-	fp.loadDirectory()
+	return fs.Stat(fp.fsys, name)
 }
 
 // View renders the advanced file picker (Tier 4)
@@ -1571,7 +1764,7 @@ func (fp *AdvancedModel) View() string {
 	switch fp.viewState {
 	case ViewStateConfirmDelete:
 		return fp.viewConfirmDelete()
-	case ViewStateNormal, ViewStateRename, ViewStateCreateFile, ViewStateCreateDir, ViewStateSearch, ViewStateGlob:
+	case ViewStateNormal, ViewStateRename, ViewStateCreateFile, ViewStateCreateDir, ViewStateSearch, ViewStateGlob, ViewStateFind:
 		return fp.viewNormal()
 	default:
 		return fp.viewNormal()
@@ -1661,6 +1854,10 @@ func (fp *AdvancedModel) SetSize(width, height int) {
 
 // buildFileListPanel builds the file list panel content
 func (fp *AdvancedModel) buildFileListPanel(width int) string {
+	if fp.viewState == ViewStateFind {
+		return fp.buildFinderPanel(width)
+	}
+
 	var b strings.Builder
 	contentWidth := width - 2
 
@@ -1680,18 +1877,8 @@ func (fp *AdvancedModel) buildFileListPanel(width int) string {
 	}
 	b.WriteString(title + "\n")
 
-	// Current path (show relative path from jail if jailed)
-	displayPath := fp.currentPath
-	if fp.jailDirectory != "" {
-		if relPath, err := filepath.Rel(fp.jailDirectory, fp.currentPath); err == nil && !strings.HasPrefix(relPath, "..") {
-			if relPath == "." {
-				displayPath = "[jail]"
-			} else {
-				displayPath = "[jail]/" + relPath
-			}
-		}
-	}
-	path := pathStyle.Render("Path: " + displayPath)
+	// Current path
+	path := pathStyle.Render("Path: " + fp.displayPath(fp.currentPath))
 	b.WriteString(path + "\n")
 
 	// Separator
@@ -1725,9 +1912,14 @@ func (fp *AdvancedModel) buildFileListPanel(width int) string {
 		line := fp.formatFileEntry(file, i == fp.cursor, contentWidth)
 		b.WriteString(line + "\n")
 	}
+	shown := endIdx - startIdx
+	if fp.loading && shown == 0 {
+		b.WriteString(statusStyle.Render("Loading...") + "\n")
+		shown++
+	}
 
 	// Fill remaining space
-	remaining := contentHeight - (endIdx - startIdx)
+	remaining := contentHeight - shown
 	for i := 0; i < remaining; i++ {
 		b.WriteString(strings.Repeat(" ", contentWidth) + "\n")
 	}
@@ -1759,7 +1951,7 @@ func (fp *AdvancedModel) buildFileListPanel(width int) string {
 			prompt = "New file: "
 		case ViewStateCreateDir:
 			prompt = "New directory: "
-		case ViewStateNormal, ViewStateConfirmDelete, ViewStateSearch, ViewStateGlob:
+		case ViewStateNormal, ViewStateConfirmDelete, ViewStateSearch, ViewStateGlob, ViewStateFind:
 			// These states don't need prompts
 		}
 		if fp.viewState == ViewStateSearch {
@@ -1779,6 +1971,11 @@ func (fp *AdvancedModel) buildFileListPanel(width int) string {
 	status := fp.buildStatusLine()
 	b.WriteString(status)
 
+	// Running file operation or the failures of the last one
+	if opView := fp.buildOpView(contentWidth); opView != "" {
+		b.WriteString("\n" + opView)
+	}
+
 	// Error display
 	if fp.err != nil {
 		b.WriteString("\n" + errorStyle.Render("Error: "+fp.err.Error()))
@@ -1788,6 +1985,19 @@ func (fp *AdvancedModel) buildFileListPanel(width int) string {
 	return b.String()
 }
 
+// displayPath shows a path relative to the jail directory if jailed
+func (fp *AdvancedModel) displayPath(p string) string {
+	if fp.jailDirectory != "" {
+		if relPath, err := fp.loc.rel(fp.jailDirectory, p); err == nil && !strings.HasPrefix(relPath, "..") {
+			if relPath == "." {
+				return "[jail]"
+			}
+			return "[jail]/" + relPath
+		}
+	}
+	return p
+}
+
 // buildPreviewPanel builds the preview panel content
 func (fp *AdvancedModel) buildPreviewPanel(width int) string {
 	var b strings.Builder
@@ -1823,10 +2033,14 @@ func (fp *AdvancedModel) buildPreviewPanel(width int) string {
 func (fp *AdvancedModel) viewConfirmDelete() string {
 	var b strings.Builder
 
-	b.WriteString("Delete the following files?\n\n")
+	if w, err := fp.writableFS(); err == nil && fp.trash(w) != nil {
+		b.WriteString("Move the following files to the trash?\n\n")
+	} else {
+		b.WriteString("Delete the following files permanently?\n\n")
+	}
 
 	for _, filePath := range fp.confirmFiles {
-		b.WriteString("• " + filepath.Base(filePath) + "\n")
+		b.WriteString("• " + fp.loc.base(filePath) + "\n")
 	}
 
 	b.WriteString("\n[Y] Yes    [N] No")
@@ -1841,7 +2055,9 @@ func (fp *AdvancedModel) buildStatusLine() string {
 	var parts []string
 
 	// File count and filtering info
-	if fp.searchQuery != "" || fp.globPattern != "" {
+	if fp.loading {
+		parts = append(parts, "Loading...")
+	} else if fp.searchQuery != "" || fp.globPattern != "" {
 		parts = append(parts, fmt.Sprintf("%d of %d items", len(fp.filteredFiles), len(fp.files)))
 	} else {
 		parts = append(parts, fmt.Sprintf("%d items", len(fp.files)))
@@ -2125,75 +2341,14 @@ func (fp *AdvancedModel) formatFileSize(size int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
 
-// loadDirectory loads the contents of the current directory (enhanced for Tier 4)
+// loadDirectory loads the contents of the current directory (enhanced for Tier 4). Key
+// handlers use loadDirectoryCmd instead, which doesn't block the UI.
 func (fp *AdvancedModel) loadDirectory() {
-	fp.files = []File{}
-	fp.err = nil
-
-	entries, err := os.ReadDir(fp.currentPath)
-	if err != nil {
-		fp.err = err
-		return
-	}
-
-	// Add parent directory entry if not at root and not at jail root
-	if fp.currentPath != "/" && fp.currentPath != "\\" && !fp.isAtJailRoot() {
-		parentPath := filepath.Dir(fp.currentPath)
-		// Only add .. if parent directory is within jail (or no jail is set)
-		if fp.validateNavigationPath(parentPath) {
-			if info, err := os.Stat(parentPath); err == nil {
-				fp.files = append(fp.files, File{
-					Name:    "..",
-					Path:    parentPath,
-					IsDir:   true,
-					Size:    0,
-					ModTime: info.ModTime(),
-					Mode:    info.Mode(),
-					Hidden:  false,
-				})
-			}
-		}
-	}
-
-	// Add directory entries
-	for _, entry := range entries {
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
-
-		isHidden := strings.HasPrefix(entry.Name(), ".")
-
-		// Skip hidden files if not showing them
-		if isHidden && !fp.showHidden {
-			continue
-		}
-
-		file := File{
-			Name:    entry.Name(),
-			Path:    filepath.Join(fp.currentPath, entry.Name()),
-			IsDir:   entry.IsDir(),
-			Size:    info.Size(),
-			ModTime: info.ModTime(),
-			Mode:    info.Mode(),
-			Hidden:  isHidden,
-		}
-		fp.files = append(fp.files, file)
-	}
-
-	// Sort files
-	fp.sortFiles()
-
-	// Reset cursor if out of bounds
-	if fp.cursor >= len(fp.filteredFiles) {
-		fp.cursor = len(fp.filteredFiles) - 1
-	}
-	if fp.cursor < 0 {
-		fp.cursor = 0
-	}
-
-	// Update preview for current file
-	fp.updatePreview()
+	// A synchronous load supersedes pending ones
+	fp.loadSeq++
+	fp.loading = false
+	files, err := fp.lister().list(fp.currentPath)
+	fp.applyListing(fp.currentPath, files, err)
 }
 
 // GetSelected returns the selected file paths
@@ -2204,6 +2359,11 @@ func (fp *AdvancedModel) GetSelected() ([]string, bool) {
 	return fp.selectedFiles, len(fp.selectedFiles) > 0
 }
 
+// Cancelled reports whether the picker was closed without choosing a file.
+func (fp *AdvancedModel) Cancelled() bool {
+	return fp.cancelled
+}
+
 // GetError returns any error that occurred
 func (fp *AdvancedModel) GetError() error {
 	return fp.err
@@ -2214,10 +2374,11 @@ func (fp *AdvancedModel) SetShowPreview(show bool) {
 	fp.showPreview = show
 }
 
-// SetShowHidden sets whether to show hidden files
+// SetShowHidden sets whether to show hidden files; the listing is reloaded with the
+// next message the picker gets
 func (fp *AdvancedModel) SetShowHidden(show bool) {
 	fp.showHidden = show
-	fp.loadDirectory() // Reload to apply the hidden file setting
+	fp.reloadPending = true
 }
 
 // GetDirectorySelectionMode returns whether directory selection mode is enabled
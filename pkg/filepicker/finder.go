@@ -0,0 +1,452 @@
+package filepicker
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+const (
+	// maxFinderEntries caps the number of files the finder collects
+	maxFinderEntries = 100000
+	finderBatchSize  = 256
+)
+
+// finderEntry is a file found by the finder.
+type finderEntry struct {
+	File
+	// rel is the slash separated path below the finder root, directories end in "/"
+	rel string
+}
+
+type finderSource []finderEntry
+
+func (s finderSource) String(i int) string { return s[i].rel }
+func (s finderSource) Len() int            { return len(s) }
+
+// finderBatchMsg carries files found by the finder walk.
+type finderBatchMsg struct {
+	seq       int
+	entries   []finderEntry
+	done      bool
+	truncated bool
+	err       error
+}
+
+// finder is the state of the find mode, which fuzzy-matches all files below the
+// directory it was started in.
+type finder struct {
+	input     textinput.Model
+	root      string
+	seq       int
+	cancel    context.CancelFunc
+	updates   <-chan tea.Msg
+	walking   bool
+	truncated bool
+	err       error
+	entries   []finderEntry
+	// matches ranks the entries for a query, nil while the query is empty
+	matches []fuzzy.Match
+	cursor  int
+}
+
+func newFinder() finder {
+	fi := textinput.New()
+	fi.Placeholder = "Find files..."
+	fi.CharLimit = 100
+	return finder{input: fi}
+}
+
+func (f *finder) count() int {
+	if f.input.Value() == "" {
+		return len(f.entries)
+	}
+	return len(f.matches)
+}
+
+// match returns the i-th result and the byte offsets of the matched characters.
+func (f *finder) match(i int) (finderEntry, []int) {
+	if f.input.Value() == "" {
+		return f.entries[i], nil
+	}
+	m := f.matches[i]
+	return f.entries[m.Index], m.MatchedIndexes
+}
+
+func (f *finder) selected() (finderEntry, bool) {
+	if f.cursor >= f.count() {
+		return finderEntry{}, false
+	}
+	e, _ := f.match(f.cursor)
+	return e, true
+}
+
+func (f *finder) rank() {
+	f.matches = nil
+	if query := f.input.Value(); query != "" {
+		f.matches = fuzzy.FindFrom(query, finderSource(f.entries))
+	}
+	if f.cursor >= f.count() {
+		f.cursor = max(f.count()-1, 0)
+	}
+}
+
+// finderWalker walks the tree below the finder root in a goroutine.
+type finderWalker struct {
+	ctx        context.Context
+	seq        int
+	updates    chan<- tea.Msg
+	fsys       fs.FS
+	loc        location
+	root       string // picker path of the root
+	rootName   string
+	showHidden bool
+}
+
+func (w finderWalker) send(msg finderBatchMsg) bool {
+	select {
+	case w.updates <- msg:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+func (w finderWalker) walk() {
+	defer close(w.updates)
+
+	ignore := &gitignore{}
+	ignore.loadParents(w.fsys, w.rootName)
+
+	var batch []finderEntry
+	found, truncated, sent := 0, false, time.Now()
+	err := fs.WalkDir(w.fsys, w.rootName, func(name string, d fs.DirEntry, err error) error {
+		if w.ctx.Err() != nil {
+			return w.ctx.Err()
+		}
+		if err != nil {
+			if name == w.rootName {
+				return err
+			}
+			// Leave out what can't be read
+			return nil
+		}
+		if name == w.rootName {
+			ignore.load(w.fsys, name)
+			return nil
+		}
+
+		hidden := strings.HasPrefix(d.Name(), ".")
+		if d.Name() == ".git" || (hidden && !w.showHidden) || ignore.ignored(name, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			ignore.load(w.fsys, name)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		rel := name
+		if w.rootName != "." {
+			rel = name[len(w.rootName)+1:]
+		}
+		entry := finderEntry{
+			File: File{
+				Name:    d.Name(),
+				Path:    w.loc.join(w.root, rel),
+				IsDir:   d.IsDir(),
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+				Mode:    info.Mode(),
+				Hidden:  hidden,
+			},
+			rel: rel,
+		}
+		if entry.IsDir {
+			entry.rel += "/"
+		}
+		batch = append(batch, entry)
+
+		found++
+		if found == maxFinderEntries {
+			truncated = true
+			return fs.SkipAll
+		}
+		if len(batch) >= finderBatchSize || time.Since(sent) >= progressInterval {
+			if !w.send(finderBatchMsg{seq: w.seq, entries: batch}) {
+				return w.ctx.Err()
+			}
+			batch, sent = nil, time.Now()
+		}
+		return nil
+	})
+	if w.ctx.Err() != nil {
+		return
+	}
+	w.send(finderBatchMsg{seq: w.seq, entries: batch, done: true, truncated: truncated, err: err})
+}
+
+// startFind enters find mode and starts walking the current directory. The walk goes
+// through the picker's filesystem, so it stays inside the jail, and skips what
+// .gitignore files ignore as well as hidden files unless they are shown.
+func (fp *AdvancedModel) startFind() tea.Cmd {
+	fp.stopFind()
+	rootName, err := fp.loc.name(fp.currentPath)
+	if err != nil {
+		fp.err = fmt.Errorf("failed to find files: %v", err)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan tea.Msg, 1)
+	f := &fp.finder
+	f.seq++
+	f.root = fp.currentPath
+	f.cancel, f.updates = cancel, updates
+	f.walking, f.truncated, f.err = true, false, nil
+	f.entries, f.matches, f.cursor = nil, nil, 0
+	f.input.SetValue("")
+	f.input.Focus()
+	fp.viewState = ViewStateFind
+	fp.previewContent = ""
+
+	w := finderWalker{
+		ctx:        ctx,
+		seq:        f.seq,
+		updates:    updates,
+		fsys:       fp.fsys,
+		loc:        fp.loc,
+		root:       fp.currentPath,
+		rootName:   rootName,
+		showHidden: fp.showHidden,
+	}
+	go w.walk()
+	return tea.Batch(textinput.Blink, waitForUpdate(updates))
+}
+
+// stopFind cancels the walk; batches still on their way are dropped.
+func (fp *AdvancedModel) stopFind() {
+	f := &fp.finder
+	if f.cancel != nil {
+		f.cancel()
+		f.cancel = nil
+	}
+	f.seq++
+	f.walking = false
+	f.input.Blur()
+}
+
+func (fp *AdvancedModel) handleFinderBatch(msg finderBatchMsg) tea.Cmd {
+	f := &fp.finder
+	if msg.seq != f.seq {
+		return nil
+	}
+	f.entries = append(f.entries, msg.entries...)
+	f.rank()
+	fp.updateFinderPreview()
+	if !msg.done {
+		return waitForUpdate(f.updates)
+	}
+	f.walking, f.truncated, f.err = false, msg.truncated, msg.err
+	return nil
+}
+
+// updateFind handles find mode: typing refines the query, enter selects a file or opens
+// a directory, ctrl+o jumps to the match in the file list.
+func (fp *AdvancedModel) updateFind(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := &fp.finder
+
+	switch msg.String() {
+	case "esc":
+		fp.stopFind()
+		fp.viewState = ViewStateNormal
+		fp.updatePreview()
+
+	case "ctrl+c":
+		fp.stopFind()
+		fp.cancelled = true
+		return fp, tea.Quit
+
+	case "up", "ctrl+p":
+		if f.cursor > 0 {
+			f.cursor--
+			fp.updateFinderPreview()
+		}
+
+	case "down", "ctrl+n":
+		if f.cursor < f.count()-1 {
+			f.cursor++
+			fp.updateFinderPreview()
+		}
+
+	case "enter":
+		return fp.openFinderMatch(false)
+
+	case "ctrl+o":
+		return fp.openFinderMatch(true)
+
+	default:
+		query := f.input.Value()
+		var cmd tea.Cmd
+		f.input, cmd = f.input.Update(msg)
+		if f.input.Value() != query {
+			f.cursor = 0
+			f.rank()
+			fp.updateFinderPreview()
+		}
+		return fp, cmd
+	}
+
+	return fp, nil
+}
+
+// openFinderMatch leaves find mode with the selected match. Enter selects a file like in
+// the file list and opens a directory; jumping opens the directory holding the match
+// with the cursor on it.
+func (fp *AdvancedModel) openFinderMatch(jump bool) (tea.Model, tea.Cmd) {
+	entry, ok := fp.finder.selected()
+	if !ok {
+		return fp, nil
+	}
+	if !jump && !entry.IsDir && fp.directorySelectionMode {
+		// Only directories can be selected
+		return fp, nil
+	}
+
+	fp.stopFind()
+	fp.viewState = ViewStateNormal
+	if !jump && !entry.IsDir {
+		fp.selectedFiles = []string{entry.Path}
+		return fp, tea.Quit
+	}
+
+	dir := entry.Path
+	if jump {
+		dir = fp.loc.dir(entry.Path)
+		fp.reveal = entry.Name
+	}
+	if !fp.validateNavigationPath(dir) {
+		return fp, nil
+	}
+	fp.currentPath = dir
+	fp.addToHistory(dir)
+	fp.cursor = 0
+	fp.multiSelected = make(map[string]bool)
+	fp.searchQuery = ""
+	fp.globPattern = ""
+	return fp, fp.loadDirectoryCmd()
+}
+
+func (fp *AdvancedModel) updateFinderPreview() {
+	entry, ok := fp.finder.selected()
+	if !fp.showPreview || !ok {
+		fp.previewContent = ""
+		return
+	}
+	fp.previewContent = fp.buildPreview(entry.File)
+}
+
+// buildFinderPanel renders find mode in place of the file list.
+func (fp *AdvancedModel) buildFinderPanel(width int) string {
+	var b strings.Builder
+	contentWidth := width - 2
+	f := &fp.finder
+
+	b.WriteString(titleStyle.Render("Find") + pathStyle.Render(" in "+fp.displayPath(f.root)) + "\n")
+	b.WriteString(searchStyle.Render("Find: ") + f.input.View() + "\n")
+	b.WriteString(strings.Repeat("─", contentWidth) + "\n")
+
+	contentHeight := fp.height - 9
+	start := 0
+	if f.cursor >= contentHeight {
+		start = f.cursor - contentHeight + 1
+	}
+	end := min(start+contentHeight, f.count())
+	for i := start; i < end; i++ {
+		entry, matched := f.match(i)
+		b.WriteString(fp.formatFinderEntry(entry, matched, i == f.cursor, contentWidth) + "\n")
+	}
+	shown := end - start
+	if shown == 0 && f.walking {
+		b.WriteString(statusStyle.Render("Searching...") + "\n")
+		shown++
+	}
+	for i := shown; i < contentHeight; i++ {
+		b.WriteString(strings.Repeat(" ", contentWidth) + "\n")
+	}
+
+	b.WriteString(strings.Repeat("─", contentWidth) + "\n")
+	parts := []string{fmt.Sprintf("%d/%d files", f.count(), len(f.entries))}
+	if f.walking {
+		parts = append(parts, "scanning...")
+	}
+	if f.truncated {
+		parts = append(parts, fmt.Sprintf("stopped after %d files", maxFinderEntries))
+	}
+	parts = append(parts, "enter open | ctrl+o jump | esc back")
+	b.WriteString(statusStyle.Render(strings.Join(parts, " | ")))
+	if f.err != nil {
+		b.WriteString("\n" + errorStyle.Render("Error: "+f.err.Error()))
+	}
+
+	return b.String()
+}
+
+// formatFinderEntry renders a match with its matched characters highlighted. Paths too
+// long for the panel are cut at the front, keeping the file name visible.
+func (fp *AdvancedModel) formatFinderEntry(entry finderEntry, matched []int, isCursor bool, width int) string {
+	base := normalStyle
+	if entry.IsDir {
+		base = dirStyle
+	}
+	prefix := "  "
+	if isCursor {
+		base = selectedStyle
+		prefix = "> "
+	}
+	if fp.showIcons {
+		prefix += fp.getFileIcon(entry.File) + " "
+	}
+
+	highlight := make(map[int]bool, len(matched))
+	for _, idx := range matched {
+		highlight[utf8.RuneCountInString(entry.rel[:idx])] = true
+	}
+	runes := []rune(entry.rel)
+	offset := 0
+	if avail := width - lipgloss.Width(prefix) - 1; avail > 0 && len(runes) > avail {
+		offset = len(runes) - avail
+		runes = runes[offset:]
+		prefix += "…"
+	}
+
+	var b strings.Builder
+	b.WriteString(base.Render(prefix))
+	for i := 0; i < len(runes); {
+		hl := highlight[i+offset]
+		j := i + 1
+		for j < len(runes) && highlight[j+offset] == hl {
+			j++
+		}
+		style := base
+		if hl {
+			style = matchStyle.Inherit(base)
+		}
+		b.WriteString(style.Render(string(runes[i:j])))
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package filepicker
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func finderTree() fstest.MapFS {
+	return fstest.MapFS{
+		".gitignore":            {Data: []byte("# build output\n*.log\n!keep.log\nbuild/\n/docs/generated\n")},
+		".git/config":           {Data: []byte("[core]")},
+		".env":                  {Data: []byte("SECRET=1")},
+		"main.go":               {Data: []byte("package main")},
+		"debug.log":             {Data: []byte("debug")},
+		"keep.log":              {Data: []byte("keep")},
+		"build/out.bin":         {Data: []byte("bin")},
+		"docs/readme.md":        {Data: []byte("# Docs")},
+		"docs/generated/api.md": {Data: []byte("# API")},
+		"pkg/util/strings.go":   {Data: []byte("package util")},
+		"pkg/util/.gitignore":   {Data: []byte("*_gen.go\n")},
+		"pkg/util/types_gen.go": {Data: []byte("package util")},
+	}
+}
+
+func finderResults(fp *AdvancedModel) []string {
+	var rels []string
+	for i := 0; i < fp.finder.count(); i++ {
+		e, _ := fp.finder.match(i)
+		rels = append(rels, e.rel)
+	}
+	return rels
+}
+
+func typeKeys(fp *AdvancedModel, s string) {
+	for _, r := range s {
+		fp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestFinderWalksTreeRespectingGitignore(t *testing.T) {
+	fp := New(WithFS(finderTree()))
+	fp.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := fp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if fp.viewState != ViewStateFind {
+		t.Fatal("expected f to enter find mode")
+	}
+	drain(fp, cmd)
+	if fp.finder.walking {
+		t.Fatal("expected the walk to be done")
+	}
+	got := strings.Join(finderResults(fp), ",")
+	if got != "docs/,docs/readme.md,keep.log,main.go,pkg/,pkg/util/,pkg/util/strings.go" {
+		t.Fatalf("unexpected results %s", got)
+	}
+
+	typeKeys(fp, "strgo")
+	results := finderResults(fp)
+	if len(results) != 1 || results[0] != "pkg/util/strings.go" {
+		t.Fatalf("unexpected matches %v", results)
+	}
+	if _, matched := fp.finder.match(0); len(matched) != 5 {
+		t.Errorf("expected 5 highlighted characters, got %v", matched)
+	}
+	if view := fp.View(); !strings.Contains(view, "pkg/util/strings.go") || !strings.Contains(view, "1/7 files") {
+		t.Errorf("unexpected view %q", view)
+	}
+
+	_, cmd = fp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected selecting a file to quit")
+	}
+	if selected, ok := fp.GetSelected(); !ok || selected[0] != "/pkg/util/strings.go" {
+		t.Errorf("unexpected selection %v", selected)
+	}
+}
+
+func TestFinderReadsGitignoreUpToTheWorkTree(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":       {Data: []byte("*.md\n")},
+		"repo/.git/HEAD":   {Data: []byte("ref: refs/heads/main")},
+		"repo/.gitignore":  {Data: []byte("*.log\n")},
+		"repo/src/a.md":    {Data: []byte("# A")},
+		"repo/src/b.log":   {Data: []byte("b")},
+		"repo/src/c.go":    {Data: []byte("package src")},
+		"other/.gitignore": {Data: []byte("*.md\n")},
+		"other/src/d.md":   {Data: []byte("# D")},
+	}
+
+	// the .gitignore above the work tree doesn't apply
+	fp := New(WithFS(fsys), WithStartPath("/repo/src"))
+	drain(fp, fp.startFind())
+	if got := strings.Join(finderResults(fp), ","); got != "a.md,c.go" {
+		t.Errorf("unexpected results %s", got)
+	}
+
+	// outside a work tree no .gitignore above the start applies
+	fp = New(WithFS(fsys), WithStartPath("/other/src"))
+	drain(fp, fp.startFind())
+	if got := strings.Join(finderResults(fp), ","); got != "d.md" {
+		t.Errorf("unexpected results %s", got)
+	}
+}
+
+func TestFinderJumpsToMatchInsideJail(t *testing.T) {
+	fp := New(WithFS(finderTree()), WithJailDirectory("/pkg"))
+	drain(fp, fp.startFind())
+	if got := strings.Join(finderResults(fp), ","); got != "util/,util/strings.go" {
+		t.Fatalf("expected the walk to stay in the jail, got %s", got)
+	}
+
+	typeKeys(fp, "strings")
+	_, cmd := fp.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	drain(fp, cmd)
+	if fp.viewState != ViewStateNormal || fp.currentPath != "/pkg/util" {
+		t.Fatalf("expected to jump to /pkg/util, at %s", fp.currentPath)
+	}
+	if f := fp.filteredFiles[fp.cursor]; f.Name != "strings.go" {
+		t.Errorf("expected the cursor on strings.go, got %s", f.Name)
+	}
+
+	// esc leaves find mode where it was started
+	drain(fp, fp.startFind())
+	fp.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if fp.viewState != ViewStateNormal || fp.currentPath != "/pkg/util" || fp.cancelled {
+		t.Errorf("expected esc to return to the file list")
+	}
+}
+
+func TestGitignoreRules(t *testing.T) {
+	g := &gitignore{}
+	g.rules = append(g.rules, parseGitignore(".", "*.tmp\n/vendor/\nlogs/**/*.txt\n!important.tmp\n\\#notes")...)
+	g.rules = append(g.rules, parseGitignore("web", "dist/\n")...)
+
+	for _, tc := range []struct {
+		name    string
+		isDir   bool
+		ignored bool
+	}{
+		{"a.tmp", false, true},
+		{"src/b.tmp", false, true},
+		{"important.tmp", false, false},
+		{"vendor", true, true},
+		{"src/vendor", true, false},
+		{"vendor", false, false},
+		{"logs/x.txt", false, true},
+		{"logs/a/b/x.txt", false, true},
+		{"logs/x.md", false, false},
+		{"#notes", false, true},
+		{"web/dist", true, true},
+		{"dist", true, false},
+	} {
+		if got := g.ignored(tc.name, tc.isDir); got != tc.ignored {
+			t.Errorf("ignored(%q, %v) = %v, want %v", tc.name, tc.isDir, got, tc.ignored)
+		}
+	}
+}
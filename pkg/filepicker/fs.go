@@ -0,0 +1,269 @@
+package filepicker
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WritableFS is a filesystem the picker can modify. Reads go through io/fs like any
+// fs.FS; the picker copies files with Open and Create. Names are io/fs names: slash
+// separated and relative to the root of the filesystem.
+type WritableFS interface {
+	fs.FS
+	// Create creates or truncates the named file.
+	Create(name string) (io.WriteCloser, error)
+	Mkdir(name string, perm fs.FileMode) error
+	Rename(oldname, newname string) error
+	// RemoveAll removes name and everything it contains.
+	RemoveAll(name string) error
+}
+
+// ErrReadOnly is reported by file operations on a filesystem that is not a WritableFS.
+var ErrReadOnly = errors.New("read-only filesystem")
+
+// DirFS returns the tree rooted at dir on the local disk as a WritableFS. Reads behave
+// like os.DirFS; writes use the os package. It is also a TrashFS.
+func DirFS(dir string) WritableFS {
+	return dirFS(dir)
+}
+
+type dirFS string
+
+func (d dirFS) Open(name string) (fs.File, error) { return os.DirFS(string(d)).Open(name) }
+
+func (d dirFS) Stat(name string) (fs.FileInfo, error) { return fs.Stat(os.DirFS(string(d)), name) }
+
+func (d dirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(os.DirFS(string(d)), name)
+}
+
+func (d dirFS) Create(name string) (io.WriteCloser, error) {
+	p, err := d.join("create", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Create(p)
+}
+
+func (d dirFS) Mkdir(name string, perm fs.FileMode) error {
+	p, err := d.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.Mkdir(p, perm)
+}
+
+func (d dirFS) Rename(oldname, newname string) error {
+	oldPath, err := d.join("rename", oldname)
+	if err != nil {
+		return err
+	}
+	newPath, err := d.join("rename", newname)
+	if err != nil {
+		return err
+	}
+	return os.Rename(oldPath, newPath)
+}
+
+func (d dirFS) RemoveAll(name string) error {
+	p, err := d.join("removeall", name)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(p)
+}
+
+func (d dirFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(string(d), filepath.FromSlash(name)), nil
+}
+
+// Jail restricts fsys to the tree under the directory dir (an io/fs name): every
+// operation on a name outside it fails with fs.ErrPermission. The result is a
+// WritableFS or TrashFS when fsys is one. WithJailDirectory wraps the picker's
+// filesystem with it.
+func Jail(fsys fs.FS, dir string) fs.FS {
+	j := jailFS{fsys: fsys, dir: path.Clean(dir)}
+	if t, ok := fsys.(TrashFS); ok {
+		return trashJailFS{writableJailFS: writableJailFS{jailFS: j, w: t}, t: t}
+	}
+	if w, ok := fsys.(WritableFS); ok {
+		return writableJailFS{jailFS: j, w: w}
+	}
+	return j
+}
+
+type jailFS struct {
+	fsys fs.FS
+	dir  string
+}
+
+func (j jailFS) check(op, name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if !withinDir(name, j.dir) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+	}
+	return nil
+}
+
+func (j jailFS) Open(name string) (fs.File, error) {
+	if err := j.check("open", name); err != nil {
+		return nil, err
+	}
+	return j.fsys.Open(name)
+}
+
+func (j jailFS) Stat(name string) (fs.FileInfo, error) {
+	if err := j.check("stat", name); err != nil {
+		return nil, err
+	}
+	return fs.Stat(j.fsys, name)
+}
+
+func (j jailFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if err := j.check("readdir", name); err != nil {
+		return nil, err
+	}
+	return fs.ReadDir(j.fsys, name)
+}
+
+type writableJailFS struct {
+	jailFS
+	w WritableFS
+}
+
+func (j writableJailFS) Create(name string) (io.WriteCloser, error) {
+	if err := j.check("create", name); err != nil {
+		return nil, err
+	}
+	return j.w.Create(name)
+}
+
+func (j writableJailFS) Mkdir(name string, perm fs.FileMode) error {
+	if err := j.check("mkdir", name); err != nil {
+		return err
+	}
+	return j.w.Mkdir(name, perm)
+}
+
+func (j writableJailFS) Rename(oldname, newname string) error {
+	if err := j.check("rename", oldname); err != nil {
+		return err
+	}
+	if err := j.check("rename", newname); err != nil {
+		return err
+	}
+	return j.w.Rename(oldname, newname)
+}
+
+func (j writableJailFS) RemoveAll(name string) error {
+	if err := j.check("removeall", name); err != nil {
+		return err
+	}
+	return j.w.RemoveAll(name)
+}
+
+// withinDir reports whether the io/fs name is dir or below it.
+func withinDir(name, dir string) bool {
+	return dir == "." || name == dir || strings.HasPrefix(name, dir+"/")
+}
+
+// location maps the paths the picker shows and returns to names in its filesystem. On
+// the local disk paths are absolute OS paths and the filesystem is rooted at the volume
+// root; a filesystem set with WithFS uses slash separated paths rooted at "/".
+type location struct {
+	local bool
+	root  string
+}
+
+func localLocation(start string) location {
+	return location{local: true, root: filepath.VolumeName(start) + string(filepath.Separator)}
+}
+
+var virtualLocation = location{root: "/"}
+
+func (l location) abs(p string) (string, error) {
+	if l.local {
+		return filepath.Abs(p)
+	}
+	return path.Join("/", p), nil
+}
+
+func (l location) clean(p string) string {
+	if l.local {
+		return filepath.Clean(p)
+	}
+	return path.Clean(p)
+}
+
+func (l location) join(elem ...string) string {
+	if l.local {
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+func (l location) dir(p string) string {
+	if l.local {
+		return filepath.Dir(p)
+	}
+	return path.Dir(p)
+}
+
+func (l location) base(p string) string {
+	if l.local {
+		return filepath.Base(p)
+	}
+	return path.Base(p)
+}
+
+func (l location) rel(base, target string) (string, error) {
+	if l.local {
+		return filepath.Rel(base, target)
+	}
+	b, t := virtualName(base), virtualName(target)
+	switch {
+	case b == t:
+		return ".", nil
+	case b == ".":
+		return t, nil
+	case withinDir(t, b):
+		return t[len(b)+1:], nil
+	}
+	return "", errors.Errorf("%s is not below %s", target, base)
+}
+
+// virtualName turns a "/"-rooted path into an io/fs name.
+func virtualName(p string) string {
+	if name := strings.TrimPrefix(path.Clean("/"+p), "/"); name != "" {
+		return name
+	}
+	return "."
+}
+
+func (l location) isRoot(p string) bool {
+	return l.clean(p) == l.clean(l.root)
+}
+
+// name returns the io/fs name of the picker path p.
+func (l location) name(p string) (string, error) {
+	p, err := l.abs(p)
+	if err != nil {
+		return "", err
+	}
+	rel, err := l.rel(l.root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s is outside of %s", p, l.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,231 @@
+package filepicker
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// memFS is a writable in-memory filesystem for tests.
+type memFS struct{ fstest.MapFS }
+
+type memFile struct {
+	bytes.Buffer
+	fsys memFS
+	name string
+}
+
+func (f *memFile) Close() error {
+	f.fsys.MapFS[f.name] = &fstest.MapFile{Data: f.Bytes(), Mode: 0644}
+	return nil
+}
+
+func (m memFS) Create(name string) (io.WriteCloser, error) {
+	return &memFile{fsys: m, name: name}, nil
+}
+
+func (m memFS) Mkdir(name string, perm fs.FileMode) error {
+	if _, err := fs.Stat(m.MapFS, name); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	m.MapFS[name] = &fstest.MapFile{Mode: fs.ModeDir | perm}
+	return nil
+}
+
+func (m memFS) Rename(oldname, newname string) error {
+	for name, f := range m.MapFS {
+		if withinDir(name, oldname) {
+			delete(m.MapFS, name)
+			m.MapFS[newname+strings.TrimPrefix(name, oldname)] = f
+		}
+	}
+	return nil
+}
+
+func (m memFS) RemoveAll(name string) error {
+	for n := range m.MapFS {
+		if withinDir(n, name) {
+			delete(m.MapFS, n)
+		}
+	}
+	return nil
+}
+
+func fileNames(fp *AdvancedModel) []string {
+	var names []string
+	for _, f := range fp.filteredFiles {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func moveCursorTo(t *testing.T, fp *AdvancedModel, name string) {
+	t.Helper()
+	for i, f := range fp.filteredFiles {
+		if f.Name == name {
+			fp.cursor = i
+			return
+		}
+	}
+	t.Fatalf("%s not listed in %v", name, fileNames(fp))
+}
+
+func TestWithFSBrowsesVirtualFilesystem(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":          {Data: []byte("a")},
+		"docs/readme.md": {Data: []byte("# Hello\nworld")},
+	}
+	fp := New(WithFS(fsys))
+	if fp.currentPath != "/" {
+		t.Fatalf("expected to start at /, got %s", fp.currentPath)
+	}
+	if got := strings.Join(fileNames(fp), ","); got != "docs,a.txt" {
+		t.Fatalf("unexpected listing %s", got)
+	}
+
+	moveCursorTo(t, fp, "docs")
+	_, cmd := fp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	drain(fp, cmd)
+	if fp.currentPath != "/docs" {
+		t.Fatalf("expected to enter /docs, got %s", fp.currentPath)
+	}
+	moveCursorTo(t, fp, "readme.md")
+	fp.updatePreview()
+	if !strings.Contains(fp.previewContent, "world") {
+		t.Errorf("expected the preview to show the file, got %q", fp.previewContent)
+	}
+
+	_, cmd = fp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected selecting a file to quit")
+	}
+	if selected, ok := fp.GetSelected(); !ok || selected[0] != "/docs/readme.md" {
+		t.Errorf("unexpected selection %v", selected)
+	}
+}
+
+func TestSetShowHiddenReloadsOnNextUpdate(t *testing.T) {
+	fp := New(WithFS(fstest.MapFS{
+		"a.txt":   {Data: []byte("a")},
+		".hidden": {Data: []byte("h")},
+	}))
+	if got := strings.Join(fileNames(fp), ","); got != "a.txt" {
+		t.Fatalf("unexpected listing %s", got)
+	}
+
+	fp.SetShowHidden(true)
+	_, cmd := fp.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	drain(fp, cmd)
+	if got := strings.Join(fileNames(fp), ","); got != ".hidden,a.txt" {
+		t.Errorf("expected the hidden file after the next update, got %s", got)
+	}
+}
+
+func TestWithFSBrowsesZipArchive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("src/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("package main\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fp := New(WithFS(zr), WithStartPath("/src"))
+	if got := strings.Join(fileNames(fp), ","); got != "..,main.go" {
+		t.Fatalf("unexpected listing %s", got)
+	}
+
+	// archives are read-only
+	drain(fp, fp.performCreateDir("new"))
+	if fp.GetError() == nil || !strings.Contains(fp.GetError().Error(), ErrReadOnly.Error()) {
+		t.Errorf("expected a read-only error, got %v", fp.GetError())
+	}
+}
+
+func TestFileOperationsOnWritableFS(t *testing.T) {
+	fsys := memFS{fstest.MapFS{"notes/todo.txt": {Data: []byte("todo")}}}
+	fp := New(WithFS(fsys))
+
+	drain(fp, fp.performCreateDir("archive"))
+	cmd := fp.performCreateFile("new.txt")
+	if !fp.loading {
+		t.Error("expected the directory to be reloaded in a command")
+	}
+	drain(fp, cmd)
+	if fp.GetError() != nil {
+		t.Fatal(fp.GetError())
+	}
+	if got := strings.Join(fileNames(fp), ","); got != "archive,notes,new.txt" {
+		t.Fatalf("unexpected listing %s", got)
+	}
+
+	fp.clipboard, fp.clipboardOp = []string{"/notes"}, OpCopy
+	fp.currentPath = "/archive"
+	drain(fp, fp.performPaste())
+	if string(fsys.MapFS["archive/notes/todo.txt"].Data) != "todo" {
+		t.Fatalf("expected notes to be copied, got %v", fsys.MapFS)
+	}
+
+	moveCursorTo(t, fp, "notes")
+	drain(fp, fp.performRename("old-notes"))
+	fp.confirmFiles = []string{"/notes"}
+	fp.currentPath = "/"
+	drain(fp, fp.performDelete())
+	if fp.GetError() != nil {
+		t.Fatal(fp.GetError())
+	}
+	if _, ok := fsys.MapFS["archive/old-notes/todo.txt"]; !ok {
+		t.Errorf("expected the copy to be renamed, got %v", fsys.MapFS)
+	}
+	if _, ok := fsys.MapFS["notes/todo.txt"]; ok {
+		t.Errorf("expected notes to be deleted")
+	}
+}
+
+func TestJailAppliesToFileOperations(t *testing.T) {
+	tmpDir := t.TempDir()
+	jailDir := filepath.Join(tmpDir, "jail")
+	if err := os.MkdirAll(jailDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(jailDir, "secret.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fp := New(WithJailDirectory(jailDir))
+	moveCursorTo(t, fp, "secret.txt")
+	drain(fp, fp.performRename("../escaped.txt"))
+	if fp.GetError() == nil {
+		t.Fatal("expected renaming out of the jail to fail")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "escaped.txt")); err == nil {
+		t.Fatal("file escaped the jail")
+	}
+
+	jailed := Jail(fstest.MapFS{"in/a": {}, "out/b": {}}, "in")
+	if _, err := fs.Stat(jailed, "in/a"); err != nil {
+		t.Errorf("expected in/a to be readable: %v", err)
+	}
+	if _, err := fs.Stat(jailed, "out/b"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected a permission error, got %v", err)
+	}
+	if _, ok := jailed.(WritableFS); ok {
+		t.Error("jailing a read-only filesystem must not make it writable")
+	}
+}
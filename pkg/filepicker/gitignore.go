@@ -0,0 +1,138 @@
+package filepicker
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// gitignore holds the .gitignore rules the finder applies: comments, negation with "!",
+// directory-only patterns ending in "/", patterns anchored by a slash, and the "*", "?",
+// "[...]" and "**" wildcards. Rules only apply below the directory of their file, and
+// the last matching rule wins, like in git.
+type gitignore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	base     string   // io/fs name of the directory holding the .gitignore
+	segments []string // the pattern split at slashes
+	anchored bool     // match the path below base instead of any name
+	negate   bool
+	dirOnly  bool
+}
+
+// load adds the rules of the .gitignore in the directory dir, if there is one.
+func (g *gitignore) load(fsys fs.FS, dir string) {
+	data, err := fs.ReadFile(fsys, path.Join(dir, ".gitignore"))
+	if err != nil {
+		return
+	}
+	g.rules = append(g.rules, parseGitignore(dir, string(data))...)
+}
+
+// loadParents adds the rules of the directories above dir, outermost first, up to the top
+// of its git work tree, the first directory holding .git. Like git, it reads nothing
+// outside the work tree, so nothing is added when dir is its top or isn't in one.
+// Unreadable directories, e.g. above a jail, are skipped.
+func (g *gitignore) loadParents(fsys fs.FS, dir string) {
+	var parents []string
+	for !isWorkTreeTop(fsys, dir) {
+		if dir == "." {
+			return
+		}
+		dir = path.Dir(dir)
+		parents = append(parents, dir)
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		g.load(fsys, parents[i])
+	}
+}
+
+// isWorkTreeTop reports whether dir holds .git, which is a file in linked worktrees and
+// submodules.
+func isWorkTreeTop(fsys fs.FS, dir string) bool {
+	_, err := fs.Stat(fsys, path.Join(dir, ".git"))
+	return err == nil
+}
+
+func parseGitignore(base, data string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			// "\#" and "\!" escape the first character
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		r.segments = strings.Split(line, "/")
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// ignored reports whether the io/fs name is ignored.
+func (g *gitignore) ignored(name string, isDir bool) bool {
+	ignored := false
+	for _, r := range g.rules {
+		if (r.dirOnly && !isDir) || name == r.base || !withinDir(name, r.base) {
+			continue
+		}
+		rel := name
+		if r.base != "." {
+			rel = name[len(r.base)+1:]
+		}
+		if r.matches(rel) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func (r ignoreRule) matches(rel string) bool {
+	if !r.anchored {
+		ok, _ := path.Match(r.segments[0], path.Base(rel))
+		return ok
+	}
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments matches a path against a pattern segment by segment; "**" matches any
+// number of segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,252 @@
+package filepicker
+
+import (
+	"fmt"
+	"io/fs"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxJournalSize is the number of operations that can be undone
+const maxJournalSize = 50
+
+type stepKind int
+
+const (
+	stepRename stepKind = iota
+	stepCreate
+	stepMkdir
+	stepCopy
+	stepTrash
+)
+
+// journalStep is a single change to the filesystem, in io/fs names.
+type journalStep struct {
+	kind stepKind
+	// name is the entry the step created, renamed to or trashed
+	name string
+	// from is the source of a rename or copy
+	from string
+	// trashKey is set while the entry is in the trash
+	trashKey string
+}
+
+// journalEntry is an operation that is undone and redone as a whole.
+type journalEntry struct {
+	verb  string
+	steps []*journalStep
+}
+
+// record adds an operation to the journal; it can't be redone past a new operation.
+func (fp *AdvancedModel) record(verb string, steps ...*journalStep) {
+	if len(steps) == 0 {
+		return
+	}
+	fp.undoStack = append(fp.undoStack, journalEntry{verb: verb, steps: steps})
+	if len(fp.undoStack) > maxJournalSize {
+		fp.undoStack = fp.undoStack[1:]
+	}
+	fp.redoStack = nil
+}
+
+// journalReplay is the entry an undo or redo file operation is working on.
+type journalReplay struct {
+	entry journalEntry
+	undo  bool
+}
+
+// undo reverts the last operation.
+func (fp *AdvancedModel) undo() tea.Cmd {
+	return fp.replay(&fp.undoStack, true)
+}
+
+// redo repeats the last undone operation.
+func (fp *AdvancedModel) redo() tea.Cmd {
+	return fp.replay(&fp.redoStack, false)
+}
+
+// replay takes the last entry of from and undoes or redoes its steps in a file
+// operation, which reports progress and can be cancelled; see finishReplay.
+func (fp *AdvancedModel) replay(from *[]journalEntry, undo bool) tea.Cmd {
+	action := "redo"
+	if undo {
+		action = "undo"
+	}
+	if len(*from) == 0 {
+		fp.err = fmt.Errorf("nothing to %s", action)
+		return nil
+	}
+	w, err := fp.fileOpFS()
+	if err != nil {
+		fp.err = fmt.Errorf("failed to %s: %v", action, err)
+		return nil
+	}
+
+	t := fp.trash(w)
+	entry := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	steps := entry.steps
+	cmd := fp.startFileOp(action, func(r *opRunner) {
+		for _, s := range steps {
+			if s.kind == stepCopy && !undo {
+				files, bytes := scanTotals(r.ctx, w, []string{s.from})
+				r.progress.TotalFiles += files
+				r.progress.TotalBytes += bytes
+			} else {
+				r.progress.TotalFiles++
+			}
+		}
+		for i := range steps {
+			if r.ctx.Err() != nil {
+				return
+			}
+			s := steps[i]
+			if undo {
+				s = steps[len(steps)-1-i]
+			}
+			r.progress.Current = s.name
+			var err error
+			if undo {
+				err = s.undo(w, t)
+			} else {
+				err = s.redo(r, w, t)
+			}
+			if undo || s.kind != stepCopy {
+				r.progress.Files++
+			}
+			r.report()
+			if err != nil {
+				if r.ctx.Err() == nil {
+					r.fail(s.name, err)
+				}
+				return
+			}
+			r.steps = append(r.steps, s)
+		}
+	})
+	fp.op.replay = &journalReplay{entry: entry, undo: undo}
+	return cmd
+}
+
+// finishReplay moves the steps a replay got through to the other stack. When a step
+// failed or the replay was cancelled, the rest stay, so both stacks keep matching the
+// filesystem.
+func (fp *AdvancedModel) finishReplay(rp *journalReplay, msg fileOpDoneMsg) {
+	steps, done := rp.entry.steps, len(msg.steps)
+	from, to := &fp.redoStack, &fp.undoStack
+	finished, rest := steps[:done], steps[done:]
+	if rp.undo {
+		from, to = &fp.undoStack, &fp.redoStack
+		finished, rest = steps[len(steps)-done:], steps[:len(steps)-done]
+	}
+	if len(finished) > 0 {
+		*to = append(*to, journalEntry{verb: rp.entry.verb, steps: finished})
+	}
+	if len(rest) > 0 {
+		*from = append(*from, journalEntry{verb: rp.entry.verb, steps: rest})
+	}
+}
+
+// undo reverts the step. Entries it created go to the trash t when there is one, since
+// they may have been edited since.
+func (s *journalStep) undo(w WritableFS, t TrashFS) error {
+	switch s.kind {
+	case stepRename:
+		return renameNoClobber(w, s.name, s.from)
+	case stepTrash:
+		return s.restore(t)
+	case stepCreate, stepMkdir, stepCopy:
+		if t != nil {
+			return s.trash(t)
+		}
+		return w.RemoveAll(s.name)
+	}
+	return nil
+}
+
+// redo repeats the step; a copy runs on r, so it reports progress and stops when r is
+// cancelled, leaving nothing behind.
+func (s *journalStep) redo(r *opRunner, w WritableFS, t TrashFS) error {
+	switch s.kind {
+	case stepRename:
+		return renameNoClobber(w, s.from, s.name)
+	case stepTrash:
+		return s.trash(t)
+	case stepCreate, stepMkdir, stepCopy:
+		// Bring back what the undo trashed, or create it again
+		if s.trashKey != "" {
+			return s.restore(t)
+		}
+	}
+	if _, err := fs.Stat(w, s.name); err == nil {
+		return &fs.PathError{Op: "redo", Path: s.name, Err: fs.ErrExist}
+	}
+
+	switch s.kind {
+	case stepCreate:
+		f, err := w.Create(s.name)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	case stepMkdir:
+		return w.Mkdir(s.name, 0755)
+	case stepCopy:
+		before := len(r.failures)
+		copyTree(r, w, s.from, s.name)
+		if len(r.failures) == before && r.ctx.Err() == nil {
+			return nil
+		}
+		// Don't leave a partial copy behind
+		_ = w.RemoveAll(s.name)
+		if len(r.failures) > before {
+			err := r.failures[before].Err
+			r.failures = r.failures[:before]
+			return err
+		}
+		return r.ctx.Err()
+	case stepRename, stepTrash:
+		// Handled above
+	}
+	return nil
+}
+
+func (s *journalStep) trash(t TrashFS) error {
+	if t == nil {
+		return fmt.Errorf("no trash to move %s to", s.name)
+	}
+	key, err := t.Trash(s.name)
+	if err != nil {
+		return err
+	}
+	s.trashKey = key
+	return nil
+}
+
+func (s *journalStep) restore(t TrashFS) error {
+	if t == nil {
+		return fmt.Errorf("no trash to restore %s from", s.name)
+	}
+	if err := t.Restore(s.trashKey, s.name); err != nil {
+		return err
+	}
+	s.trashKey = ""
+	return nil
+}
+
+// trash returns the filesystem as a TrashFS, or nil if it has no trash or the picker
+// deletes permanently.
+func (fp *AdvancedModel) trash(w WritableFS) TrashFS {
+	if t, ok := w.(TrashFS); ok && fp.useTrash {
+		return t
+	}
+	return nil
+}
+
+// renameNoClobber renames unless newname exists, which a rename would replace.
+func renameNoClobber(w WritableFS, oldname, newname string) error {
+	if _, err := fs.Stat(w, newname); err == nil {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrExist}
+	}
+	return w.Rename(oldname, newname)
+}
@@ -0,0 +1,215 @@
+package filepicker
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func exists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+func TestUndoRedoOnLocalDiskUsesTrash(t *testing.T) {
+	trashDir := filepath.Join(t.TempDir(), "Trash")
+	t.Setenv("XDG_DATA_HOME", filepath.Dir(trashDir))
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+
+	fp := New(WithStartPath(dir))
+	drain(fp, fp.performCreateFile("a.txt"))
+	if err := os.WriteFile(a, []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	moveCursorTo(t, fp, "a.txt")
+	drain(fp, fp.performRename("b.txt"))
+	fp.confirmFiles = []string{b}
+	drain(fp, fp.performDelete())
+	if fp.GetError() != nil || exists(b) {
+		t.Fatalf("expected b.txt to be deleted: %v", fp.GetError())
+	}
+	info, err := os.ReadFile(filepath.Join(trashDir, "info", "b.txt.trashinfo"))
+	if err != nil || !exists(filepath.Join(trashDir, "files", "b.txt")) {
+		t.Fatalf("expected b.txt in the trash: %v", err)
+	}
+	if !strings.Contains(string(info), "[Trash Info]\nPath="+b+"\nDeletionDate=") {
+		t.Errorf("unexpected trash info %q", info)
+	}
+
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		_, cmd := fp.Update(msg)
+		drain(fp, cmd)
+		if fp.GetError() != nil || fp.opErr != nil {
+			t.Fatal(fp.GetError(), fp.opErr, fp.opFailures)
+		}
+	}
+	u := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}}
+	redo := tea.KeyMsg{Type: tea.KeyCtrlR}
+
+	press(u)
+	if !exists(b) || exists(filepath.Join(trashDir, "info", "b.txt.trashinfo")) {
+		t.Fatal("expected the delete to be undone")
+	}
+	press(u)
+	if !exists(a) || exists(b) {
+		t.Fatal("expected the rename to be undone")
+	}
+	press(u)
+	if exists(a) || !exists(filepath.Join(trashDir, "files", "a.txt")) {
+		t.Fatal("expected the created file to go to the trash")
+	}
+	fp.Update(u)
+	if fp.GetError() == nil || !strings.Contains(fp.GetError().Error(), "nothing to undo") {
+		t.Errorf("expected nothing to undo, got %v", fp.GetError())
+	}
+	fp.err = nil
+
+	press(redo)
+	if data, err := os.ReadFile(a); err != nil || string(data) != "edited" {
+		t.Fatalf("expected the file to come back with its content, got %q %v", data, err)
+	}
+	press(redo)
+	press(redo)
+	if exists(a) || exists(b) || !exists(filepath.Join(trashDir, "files", "b.txt")) {
+		t.Fatal("expected the rename and delete to be redone")
+	}
+
+	// a new operation drops what could be redone
+	press(u)
+	drain(fp, fp.performCreateDir("new"))
+	fp.Update(redo)
+	if fp.GetError() == nil || !strings.Contains(fp.GetError().Error(), "nothing to redo") {
+		t.Errorf("expected nothing to redo, got %v", fp.GetError())
+	}
+}
+
+func TestDeleteOnAnotherFilesystemUsesTopdirTrash(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir, err := os.MkdirTemp("/dev/shm", "filepicker")
+	if err != nil {
+		t.Skip("no tmpfs to test with:", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	dev, ok := deviceOf(dir)
+	if homeDev, _ := deviceOf(os.Getenv("XDG_DATA_HOME")); !ok || dev == homeDev {
+		t.Skip("/dev/shm is on the filesystem of the home trash")
+	}
+	top := mountPoint(dir, dev)
+	trashDir := filepath.Join(top, ".Trash-"+strconv.Itoa(os.Getuid()))
+	key := filepath.Base(dir) + ".txt"
+	defer func() {
+		_ = os.Remove(filepath.Join(trashDir, "info", key+".trashinfo"))
+		_ = os.Remove(filepath.Join(trashDir, "files", key))
+		// only removed when nothing else is in the trash
+		_ = os.Remove(filepath.Join(trashDir, "info"))
+		_ = os.Remove(filepath.Join(trashDir, "files"))
+		_ = os.Remove(trashDir)
+	}()
+
+	a := filepath.Join(dir, key)
+	writeFile(t, a, "a")
+	fp := New(WithStartPath(dir))
+	fp.confirmFiles = []string{a}
+	drain(fp, fp.performDelete())
+	if fp.opErr != nil || exists(a) {
+		t.Fatalf("expected the file to be deleted: %v %v", fp.opErr, fp.opFailures)
+	}
+	info, err := os.ReadFile(filepath.Join(trashDir, "info", key+".trashinfo"))
+	if err != nil || !exists(filepath.Join(trashDir, "files", key)) {
+		t.Fatalf("expected the file in the trash of %s: %v", top, err)
+	}
+	rel, _ := filepath.Rel(top, a)
+	if !strings.Contains(string(info), "\nPath="+rel+"\n") {
+		t.Errorf("expected a path relative to %s, got %q", top, info)
+	}
+	if fi, err := os.Stat(trashDir); err != nil || fi.Mode().Perm() != 0700 {
+		t.Errorf("expected the trash to be private, got %v %v", fi.Mode(), err)
+	}
+
+	_, cmd := fp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	drain(fp, cmd)
+	if fp.opErr != nil || !exists(a) {
+		t.Fatalf("expected the delete to be undone: %v %v", fp.opErr, fp.opFailures)
+	}
+}
+
+func TestUndoCopyAndMoveWithoutTrash(t *testing.T) {
+	fsys := memFS{fstest.MapFS{
+		"src/a.txt": {Data: []byte("a")},
+		"dst/.keep": {},
+	}}
+	fp := New(WithFS(fsys), WithStartPath("/dst"))
+	fp.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	fp.clipboard, fp.clipboardOp = []string{"/src"}, OpCopy
+	drain(fp, fp.performPaste())
+	drain(fp, fp.undo())
+	if _, ok := fsys.MapFS["dst/src/a.txt"]; ok || fp.GetError() != nil {
+		t.Fatalf("expected the copy to be removed: %v", fp.GetError())
+	}
+	drain(fp, fp.redo())
+	if string(fsys.MapFS["dst/src/a.txt"].Data) != "a" {
+		t.Fatal("expected the copy to be redone")
+	}
+	drain(fp, fp.undo())
+
+	fp.clipboard, fp.clipboardOp = []string{"/src"}, OpCut
+	drain(fp, fp.performPaste())
+	if _, ok := fsys.MapFS["src/a.txt"]; ok {
+		t.Fatal("expected src to be moved")
+	}
+	drain(fp, fp.undo())
+	if _, ok := fsys.MapFS["src/a.txt"]; !ok || fp.GetError() != nil {
+		t.Fatalf("expected the move to be undone: %v", fp.GetError())
+	}
+
+	// without a trash deletes are permanent and say so
+	fp.confirmFiles = []string{"/dst/.keep"}
+	fp.viewState = ViewStateConfirmDelete
+	if !strings.Contains(fp.View(), "permanently") {
+		t.Error("expected the confirmation to warn about deleting permanently")
+	}
+}
+
+func TestCancelRedoOfCopy(t *testing.T) {
+	fsys := blockingFS{
+		memFS: memFS{fstest.MapFS{
+			"src/a.txt":   {Data: []byte("a")},
+			"src/big.bin": {Data: []byte(strings.Repeat("x", 4096))},
+			"dst/.keep":   {},
+		}},
+		name:    "src/big.bin",
+		opened:  make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	fp := New(WithFS(fsys), WithStartPath("/dst"))
+	fp.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	fp.redoStack = []journalEntry{{verb: "copy", steps: []*journalStep{{kind: stepCopy, from: "src", name: "dst/src"}}}}
+
+	// the redo runs as a file operation, with progress and esc to cancel
+	_, cmd := fp.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	<-fsys.opened
+	if !strings.Contains(fp.View(), "esc to cancel") {
+		t.Error("expected the progress of the redo")
+	}
+	fp.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	close(fsys.release)
+	drain(fp, cmd)
+
+	if fp.opErr == nil || !strings.Contains(fp.opErr.Error(), "cancelled") {
+		t.Fatalf("expected the redo to be cancelled, got %v", fp.opErr)
+	}
+	if _, ok := fsys.MapFS["dst/src/a.txt"]; ok {
+		t.Error("expected the partial copy to be removed")
+	}
+	if len(fp.redoStack) != 1 || len(fp.undoStack) != 0 {
+		t.Errorf("expected the copy to stay redoable, got %d undo and %d redo entries", len(fp.undoStack), len(fp.redoStack))
+	}
+}
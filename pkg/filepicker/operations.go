@@ -0,0 +1,594 @@
+package filepicker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/errors"
+)
+
+// directoryLoadedMsg carries a listing read by loadDirectoryCmd.
+type directoryLoadedMsg struct {
+	seq   int
+	path  string
+	files []File
+	err   error
+}
+
+// dirLister reads directory listings. It copies the picker settings it needs so it can
+// run in a command, off the Update goroutine.
+type dirLister struct {
+	fsys       fs.FS
+	loc        location
+	showHidden bool
+	// parent is the path of the ".." entry, empty for none
+	parent string
+}
+
+func (fp *AdvancedModel) lister() dirLister {
+	l := dirLister{fsys: fp.fsys, loc: fp.loc, showHidden: fp.showHidden}
+	// Add parent directory entry if not at root and not at jail root, and only if the
+	// parent directory is within jail (or no jail is set)
+	if !fp.loc.isRoot(fp.currentPath) && !fp.isAtJailRoot() {
+		if parentPath := fp.loc.dir(fp.currentPath); fp.validateNavigationPath(parentPath) {
+			l.parent = parentPath
+		}
+	}
+	return l
+}
+
+func (l dirLister) list(dir string) ([]File, error) {
+	name, err := l.loc.name(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fs.ReadDir(l.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]File, 0, len(entries)+1)
+	if l.parent != "" {
+		if parentName, err := l.loc.name(l.parent); err == nil {
+			if info, err := fs.Stat(l.fsys, parentName); err == nil {
+				files = append(files, File{
+					Name:    "..",
+					Path:    l.parent,
+					IsDir:   true,
+					ModTime: info.ModTime(),
+					Mode:    info.Mode(),
+				})
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		isHidden := strings.HasPrefix(entry.Name(), ".")
+		// Skip hidden files if not showing them
+		if isHidden && !l.showHidden {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, File{
+			Name:    entry.Name(),
+			Path:    l.loc.join(dir, entry.Name()),
+			IsDir:   entry.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Mode:    info.Mode(),
+			Hidden:  isHidden,
+		})
+	}
+	return files, nil
+}
+
+// loadDirectoryCmd reads the current directory in a command; the picker shows a loading
+// indicator until the listing arrives. Listings of directories left in the meantime are
+// dropped.
+func (fp *AdvancedModel) loadDirectoryCmd() tea.Cmd {
+	fp.loadSeq++
+	fp.loading = true
+	fp.reloadPending = false
+	seq, dir, l := fp.loadSeq, fp.currentPath, fp.lister()
+	if dir != fp.listedPath {
+		// Don't keep offering the entries of the previous directory
+		fp.files, fp.filteredFiles = nil, nil
+		fp.previewContent = ""
+	}
+	return func() tea.Msg {
+		files, err := l.list(dir)
+		return directoryLoadedMsg{seq: seq, path: dir, files: files, err: err}
+	}
+}
+
+func (fp *AdvancedModel) handleDirectoryLoaded(msg directoryLoadedMsg) {
+	if msg.seq != fp.loadSeq {
+		return
+	}
+	fp.loading = false
+	fp.applyListing(msg.path, msg.files, msg.err)
+}
+
+// applyListing shows the files of dir.
+func (fp *AdvancedModel) applyListing(dir string, files []File, err error) {
+	// Keep the cursor on the same entry when the directory is reloaded
+	if dir == fp.listedPath && fp.reveal == "" && fp.cursor < len(fp.filteredFiles) {
+		fp.reveal = fp.filteredFiles[fp.cursor].Name
+	}
+
+	fp.files = []File{}
+	fp.err = nil
+	fp.listedPath = dir
+	if err != nil {
+		fp.err = err
+		fp.reveal = ""
+		return
+	}
+	fp.files = files
+
+	// Sort files
+	fp.sortFiles()
+
+	// Put the cursor on the entry the finder jumped to or that was under it
+	if fp.reveal != "" {
+		for i, f := range fp.filteredFiles {
+			if f.Name == fp.reveal {
+				fp.cursor = i
+			}
+		}
+		fp.reveal = ""
+	}
+
+	// Reset cursor if out of bounds
+	if fp.cursor >= len(fp.filteredFiles) {
+		fp.cursor = len(fp.filteredFiles) - 1
+	}
+	if fp.cursor < 0 {
+		fp.cursor = 0
+	}
+
+	// Update preview for current file
+	fp.updatePreview()
+}
+
+// fileOpFailure records an entry a file operation could not process.
+type fileOpFailure struct {
+	Path string
+	Err  error
+}
+
+// fileOpProgress is a snapshot of a running file operation.
+type fileOpProgress struct {
+	Files, TotalFiles int
+	Bytes, TotalBytes int64
+	// Current is the entry being processed
+	Current string
+}
+
+func (p fileOpProgress) percent() float64 {
+	switch {
+	case p.TotalBytes > 0:
+		return float64(p.Bytes) / float64(p.TotalBytes)
+	case p.TotalFiles > 0:
+		return float64(p.Files) / float64(p.TotalFiles)
+	}
+	return 0
+}
+
+type fileOpProgressMsg struct {
+	id       int
+	progress fileOpProgress
+}
+
+type fileOpDoneMsg struct {
+	id        int
+	progress  fileOpProgress
+	failures  []fileOpFailure
+	cancelled bool
+	// done lists the picker paths processed successfully
+	done []string
+	// steps journal what was done, for undo
+	steps []*journalStep
+}
+
+// fileOp is a copy, move or delete running in the background.
+type fileOp struct {
+	id       int
+	verb     string
+	cancel   context.CancelFunc
+	updates  <-chan tea.Msg
+	progress fileOpProgress
+	// replay is set for undo and redo, which move journal entries instead of adding one
+	replay *journalReplay
+}
+
+// opRunner is the side of a file operation running in its command.
+type opRunner struct {
+	ctx      context.Context
+	id       int
+	updates  chan<- tea.Msg
+	progress fileOpProgress
+	failures []fileOpFailure
+	done     []string
+	steps    []*journalStep
+	sent     time.Time
+}
+
+// progressInterval throttles the progress messages of file operations.
+const progressInterval = 50 * time.Millisecond
+
+func (r *opRunner) report() {
+	if time.Since(r.sent) < progressInterval {
+		return
+	}
+	r.sent = time.Now()
+	select {
+	case r.updates <- fileOpProgressMsg{id: r.id, progress: r.progress}:
+	default:
+		// The previous update was not picked up yet; the next one will be
+	}
+}
+
+func (r *opRunner) fail(p string, err error) {
+	r.failures = append(r.failures, fileOpFailure{Path: p, Err: err})
+}
+
+// startFileOp runs op in a command and returns the command waiting for its first
+// update. Esc cancels it through the context.
+func (fp *AdvancedModel) startFileOp(verb string, op func(r *opRunner)) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan tea.Msg, 1)
+	fp.opSeq++
+	fp.op = &fileOp{id: fp.opSeq, verb: verb, cancel: cancel, updates: updates}
+	fp.opFailures = nil
+	r := &opRunner{ctx: ctx, id: fp.opSeq, updates: updates}
+
+	go func() {
+		defer close(updates)
+		defer cancel()
+		op(r)
+		updates <- fileOpDoneMsg{
+			id:        r.id,
+			progress:  r.progress,
+			failures:  r.failures,
+			cancelled: ctx.Err() != nil,
+			done:      r.done,
+			steps:     r.steps,
+		}
+	}()
+	return waitForUpdate(updates)
+}
+
+// waitForUpdate waits for the next message of a background command, such as a file
+// operation or the finder walk.
+func waitForUpdate(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// cancelFileOp stops the running operation; entries already processed stay processed.
+func (fp *AdvancedModel) cancelFileOp() {
+	if fp.op != nil {
+		fp.op.cancel()
+	}
+}
+
+func (fp *AdvancedModel) handleFileOpProgress(msg fileOpProgressMsg) tea.Cmd {
+	if fp.op == nil || msg.id != fp.op.id {
+		return nil
+	}
+	fp.op.progress = msg.progress
+	return waitForUpdate(fp.op.updates)
+}
+
+func (fp *AdvancedModel) handleFileOpDone(msg fileOpDoneMsg) tea.Cmd {
+	if fp.op == nil || msg.id != fp.op.id {
+		return nil
+	}
+	verb, replay := fp.op.verb, fp.op.replay
+	fp.op = nil
+	fp.opFailures = msg.failures
+	if replay != nil {
+		fp.finishReplay(replay, msg)
+	} else {
+		fp.record(verb, msg.steps...)
+	}
+
+	switch verb {
+	case "delete":
+		for _, p := range msg.done {
+			delete(fp.multiSelected, p)
+		}
+	case "move":
+		// Keep the entries that could not be moved on the clipboard
+		fp.clipboard = removePaths(fp.clipboard, msg.done)
+		if len(fp.clipboard) == 0 {
+			fp.clipboardOp = OpNone
+		}
+	}
+
+	cmd := fp.loadDirectoryCmd()
+	switch {
+	case msg.cancelled:
+		fp.opErr = fmt.Errorf("%s cancelled after %d of %d files", verb, msg.progress.Files, msg.progress.TotalFiles)
+	case len(msg.failures) > 0:
+		fp.opErr = fmt.Errorf("%s: %d failed", verb, len(msg.failures))
+	}
+	return cmd
+}
+
+func removePaths(paths, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, p := range remove {
+		removed[p] = true
+	}
+	ret := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if !removed[p] {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
+
+// scanTotals counts the files and bytes below the filesystem names.
+func scanTotals(ctx context.Context, fsys fs.FS, names []string) (files int, bytes int64) {
+	for _, name := range names {
+		_ = fs.WalkDir(fsys, name, func(_ string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			files++
+			if info, err := d.Info(); err == nil {
+				bytes += info.Size()
+			}
+			return nil
+		})
+	}
+	return files, bytes
+}
+
+// opTarget is an entry of a file operation: the picker path and the filesystem names of
+// the entry and, for copies and moves, of its destination.
+type opTarget struct {
+	path     string
+	src, dst string
+}
+
+// opTargets resolves the picker paths of an operation. Paths outside the filesystem
+// are reported as failures right away.
+func (fp *AdvancedModel) opTargets(paths []string, withDst bool) ([]opTarget, []fileOpFailure) {
+	var targets []opTarget
+	var failures []fileOpFailure
+	for _, p := range paths {
+		t := opTarget{path: p}
+		var err error
+		t.src, err = fp.loc.name(p)
+		if err == nil && withDst {
+			t.dst, err = fp.loc.name(fp.loc.join(fp.currentPath, fp.loc.base(p)))
+			if err == nil && withinDir(t.dst, t.src) {
+				err = errors.Errorf("%s cannot be pasted into itself", fp.loc.base(p))
+			}
+		}
+		if err != nil {
+			failures = append(failures, fileOpFailure{Path: p, Err: err})
+			continue
+		}
+		targets = append(targets, t)
+	}
+	return targets, failures
+}
+
+func sourceNames(targets []opTarget) []string {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.src
+	}
+	return names
+}
+
+// startCopy copies the entries into the current directory.
+func (fp *AdvancedModel) startCopy(w WritableFS, paths []string) tea.Cmd {
+	targets, failures := fp.opTargets(paths, true)
+	return fp.startFileOp("copy", func(r *opRunner) {
+		r.failures = failures
+		r.progress.TotalFiles, r.progress.TotalBytes = scanTotals(r.ctx, w, sourceNames(targets))
+		for _, t := range targets {
+			if r.ctx.Err() != nil {
+				return
+			}
+			// Copies merged into an existing entry can't be undone by removing them
+			_, err := fs.Stat(w, t.dst)
+			existed := err == nil
+			before := len(r.failures)
+			copyTree(r, w, t.src, t.dst)
+			if _, err := fs.Stat(w, t.dst); err == nil && !existed {
+				r.steps = append(r.steps, &journalStep{kind: stepCopy, from: t.src, name: t.dst})
+			}
+			if len(r.failures) == before && r.ctx.Err() == nil {
+				r.done = append(r.done, t.path)
+			}
+		}
+	})
+}
+
+// startMove moves the entries into the current directory.
+func (fp *AdvancedModel) startMove(w WritableFS, paths []string) tea.Cmd {
+	targets, failures := fp.opTargets(paths, true)
+	return fp.startFileOp("move", func(r *opRunner) {
+		r.failures = failures
+		r.progress.TotalFiles = len(targets)
+		for _, t := range targets {
+			if r.ctx.Err() != nil {
+				return
+			}
+			r.progress.Current = t.src
+			if err := renameNoClobber(w, t.src, t.dst); err != nil {
+				r.fail(t.path, err)
+			} else {
+				r.done = append(r.done, t.path)
+				r.steps = append(r.steps, &journalStep{kind: stepRename, from: t.src, name: t.dst})
+			}
+			r.progress.Files++
+			r.report()
+		}
+	})
+}
+
+// startDelete moves the entries to the trash, or removes them and everything they
+// contain if there is none.
+func (fp *AdvancedModel) startDelete(w WritableFS, paths []string) tea.Cmd {
+	targets, failures := fp.opTargets(paths, false)
+	trash := fp.trash(w)
+	return fp.startFileOp("delete", func(r *opRunner) {
+		r.failures = failures
+		r.progress.TotalFiles, r.progress.TotalBytes = scanTotals(r.ctx, w, sourceNames(targets))
+		for _, t := range targets {
+			if r.ctx.Err() != nil {
+				return
+			}
+			r.progress.Current = t.src
+			files, bytes := scanTotals(r.ctx, w, []string{t.src})
+			var err error
+			if trash != nil {
+				step := &journalStep{kind: stepTrash, name: t.src}
+				if err = step.trash(trash); err == nil {
+					r.steps = append(r.steps, step)
+				}
+			} else {
+				err = w.RemoveAll(t.src)
+			}
+			if err != nil {
+				r.fail(t.path, err)
+			} else {
+				r.done = append(r.done, t.path)
+			}
+			r.progress.Files += files
+			r.progress.Bytes += bytes
+			r.report()
+		}
+	})
+}
+
+// copyTree copies the file or directory tree src to dst, recording failures per file and
+// stopping when the operation is cancelled. A file interrupted by the cancellation is
+// removed again.
+func copyTree(r *opRunner, w WritableFS, src, dst string) {
+	if r.ctx.Err() != nil {
+		return
+	}
+	info, err := fs.Stat(w, src)
+	if err != nil {
+		r.fail(src, err)
+		return
+	}
+	if info.IsDir() {
+		if err := w.Mkdir(dst, 0755); err != nil && !errors.Is(err, fs.ErrExist) {
+			r.fail(src, err)
+			return
+		}
+		entries, err := fs.ReadDir(w, src)
+		if err != nil {
+			r.fail(src, err)
+			return
+		}
+		for _, entry := range entries {
+			copyTree(r, w, path.Join(src, entry.Name()), path.Join(dst, entry.Name()))
+		}
+		return
+	}
+
+	r.progress.Current = src
+	err = copyFile(r, w, src, dst)
+	switch {
+	case r.ctx.Err() != nil:
+		_ = w.RemoveAll(dst)
+	case err != nil:
+		r.fail(src, err)
+	default:
+		r.progress.Files++
+	}
+	r.report()
+}
+
+func copyFile(r *opRunner, w WritableFS, src, dst string) error {
+	in, err := w.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close() // Ignore close errors in defer
+	}()
+	out, err := w.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, &progressReader{r: in, runner: r}); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// progressReader counts the bytes copied and aborts the copy on cancellation.
+type progressReader struct {
+	r      io.Reader
+	runner *opRunner
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	if err := p.runner.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := p.r.Read(b)
+	p.runner.progress.Bytes += int64(n)
+	p.runner.report()
+	return n, err
+}
+
+// buildOpView renders the progress of the running operation, or the failures of the
+// last one.
+func (fp *AdvancedModel) buildOpView(width int) string {
+	if fp.op != nil {
+		p := fp.op.progress
+		verb := strings.ToUpper(fp.op.verb[:1]) + fp.op.verb[1:]
+		line := fmt.Sprintf("%s %d/%d files", verb, p.Files, p.TotalFiles)
+		if p.TotalBytes > 0 {
+			line += fmt.Sprintf(" · %s/%s", fp.formatFileSize(p.Bytes), fp.formatFileSize(p.TotalBytes))
+		}
+		bar := progress.New(progress.WithDefaultGradient(), progress.WithoutPercentage(), progress.WithWidth(max(width, 10)))
+		return statusStyle.Render(line+" (esc to cancel)") + "\n" + bar.ViewAs(p.percent())
+	}
+
+	if fp.opErr == nil {
+		return ""
+	}
+	lines := []string{errorStyle.Render("Error: " + fp.opErr.Error())}
+	const maxFailures = 5
+	for i, f := range fp.opFailures {
+		if i == maxFailures {
+			lines = append(lines, errorStyle.Render(fmt.Sprintf("  … and %d more", len(fp.opFailures)-maxFailures)))
+			break
+		}
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  • %s: %v", fp.loc.base(f.Path), f.Err)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,167 @@
+package filepicker
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/errors"
+)
+
+// drain runs cmd and feeds its messages back into the picker until no command is left.
+func drain(fp *AdvancedModel, cmd tea.Cmd) {
+	for cmd != nil {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				drain(fp, c)
+			}
+			return
+		}
+		_, cmd = fp.Update(msg)
+	}
+}
+
+// blockingFS holds the copy of a file until it is released.
+type blockingFS struct {
+	memFS
+	name    string
+	opened  chan struct{}
+	release chan struct{}
+}
+
+func (b blockingFS) Open(name string) (fs.File, error) {
+	if name == b.name {
+		close(b.opened)
+		<-b.release
+	}
+	return b.memFS.Open(name)
+}
+
+// failingFS refuses to create the named file.
+type failingFS struct {
+	memFS
+	name string
+}
+
+func (f failingFS) Create(name string) (io.WriteCloser, error) {
+	if name == f.name {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrPermission}
+	}
+	return f.memFS.Create(name)
+}
+
+func TestLoadDirectoryCmdDropsStaleListings(t *testing.T) {
+	fp := New(WithFS(fstest.MapFS{
+		"docs/readme.md": {Data: []byte("readme")},
+		"src/main.go":    {Data: []byte("package main")},
+	}))
+	fp.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	moveCursorTo(t, fp, "docs")
+	_, docsCmd := fp.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !fp.loading || len(fp.filteredFiles) != 0 {
+		t.Fatalf("expected an empty listing while loading, got %v", fileNames(fp))
+	}
+	if !strings.Contains(fp.View(), "Loading...") {
+		t.Error("expected a loading indicator")
+	}
+
+	// leave docs before its listing arrives
+	_, backCmd := fp.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	drain(fp, docsCmd)
+	if !fp.loading {
+		t.Fatal("the listing of docs must not end the load of /")
+	}
+	drain(fp, backCmd)
+	if fp.loading || fp.currentPath != "/" {
+		t.Fatalf("expected / to be loaded, at %s loading=%v", fp.currentPath, fp.loading)
+	}
+	if got := strings.Join(fileNames(fp), ","); got != "docs,src" {
+		t.Errorf("unexpected listing %s", got)
+	}
+}
+
+func TestCancelFileOp(t *testing.T) {
+	fsys := blockingFS{
+		memFS: memFS{fstest.MapFS{
+			"a.txt":      {Data: []byte("a")},
+			"big.bin":    {Data: []byte(strings.Repeat("x", 4096))},
+			"backup/old": {Data: []byte("old")},
+		}},
+		name:    "big.bin",
+		opened:  make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	fp := New(WithFS(fsys), WithStartPath("/backup"))
+	fp.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	fp.clipboard, fp.clipboardOp = []string{"/a.txt", "/big.bin"}, OpCopy
+
+	_, cmd := fp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	<-fsys.opened
+	if !strings.Contains(fp.View(), "esc to cancel") {
+		t.Error("expected the progress of the copy")
+	}
+	if fp.performPaste() != nil || fp.GetError() == nil {
+		t.Error("expected a second paste to be refused while copying")
+	}
+
+	_, _ = fp.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	close(fsys.release)
+	drain(fp, cmd)
+
+	if fp.op != nil || fp.opErr == nil || !strings.Contains(fp.opErr.Error(), "cancelled") {
+		t.Fatalf("expected the copy to be cancelled, got %v", fp.opErr)
+	}
+	if _, ok := fsys.MapFS["backup/big.bin"]; ok {
+		t.Error("expected the interrupted file to be removed")
+	}
+	if _, ok := fsys.MapFS["backup/a.txt"]; !ok {
+		t.Error("expected the files copied before the cancellation to stay")
+	}
+	if fp.cancelled {
+		t.Error("esc must cancel the operation, not the picker")
+	}
+}
+
+func TestFileOpFailuresAreSummarized(t *testing.T) {
+	fsys := failingFS{
+		memFS: memFS{fstest.MapFS{
+			"src/a.txt":      {Data: []byte("a")},
+			"src/locked.txt": {Data: []byte("b")},
+			"dst/.keep":      {},
+		}},
+		name: "dst/src/locked.txt",
+	}
+	fp := New(WithFS(fsys), WithStartPath("/dst"))
+	fp.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	fp.clipboard, fp.clipboardOp = []string{"/src"}, OpCopy
+	drain(fp, fp.performPaste())
+
+	if len(fp.opFailures) != 1 || !errors.Is(fp.opFailures[0].Err, fs.ErrPermission) {
+		t.Fatalf("expected one failure, got %v", fp.opFailures)
+	}
+	if _, ok := fsys.MapFS["dst/src/a.txt"]; !ok {
+		t.Error("expected the other files to be copied")
+	}
+	view := fp.View()
+	if !strings.Contains(view, "copy: 1 failed") || !strings.Contains(view, "locked.txt") {
+		t.Errorf("expected a failure summary, got %q", view)
+	}
+
+	// the summary is cleared by the next key press
+	_, _ = fp.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if fp.opErr != nil || fp.opFailures != nil {
+		t.Error("expected the summary to be cleared")
+	}
+
+	// a directory can't be pasted into itself
+	fp.clipboard = []string{"/dst"}
+	drain(fp, fp.performPaste())
+	if len(fp.opFailures) != 1 || !strings.Contains(fp.opFailures[0].Err.Error(), "into itself") {
+		t.Errorf("expected pasting into itself to fail, got %v", fp.opFailures)
+	}
+}
@@ -0,0 +1,58 @@
+package filepicker
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+)
+
+// ApplyTheme restyles every file picker from a theme palette. The picker
+// styles are shared by all instances, so this affects pickers already on
+// screen; AdvancedModel.Update calls it on theme.ChangedMsg.
+func ApplyTheme(t theme.Theme) {
+	p := t.Palette
+
+	borderStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(p.Accent)
+	titleStyle = lipgloss.NewStyle().
+		Foreground(p.Secondary).
+		Bold(true)
+	pathStyle = lipgloss.NewStyle().
+		Foreground(p.Muted)
+	selectedStyle = lipgloss.NewStyle().
+		Background(p.Selection).
+		Foreground(p.SelectionForeground)
+	multiSelectedStyle = lipgloss.NewStyle().
+		Background(p.Accent).
+		Foreground(p.SelectionForeground)
+	normalStyle = lipgloss.NewStyle().
+		Foreground(p.Foreground)
+	dirStyle = lipgloss.NewStyle().
+		Foreground(p.Accent).
+		Bold(true)
+	dirSelectionStyle = lipgloss.NewStyle().
+		Foreground(p.Highlight).
+		Background(p.Surface).
+		Bold(true)
+	hiddenStyle = lipgloss.NewStyle().
+		Foreground(p.Muted)
+	statusStyle = lipgloss.NewStyle().
+		Foreground(p.Secondary)
+	previewTitleStyle = lipgloss.NewStyle().
+		Foreground(p.Highlight).
+		Bold(true)
+	searchStyle = lipgloss.NewStyle().
+		Background(p.Surface).
+		Foreground(p.Highlight)
+	confirmStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(p.Error).
+		Background(p.Surface).
+		Foreground(p.Foreground).
+		Padding(1, 2)
+	errorStyle = lipgloss.NewStyle().
+		Foreground(p.Error)
+	matchStyle = lipgloss.NewStyle().
+		Foreground(p.Highlight).
+		Bold(true)
+}
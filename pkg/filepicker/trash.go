@@ -0,0 +1,228 @@
+package filepicker
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TrashFS is a WritableFS that can move entries to a trash instead of removing them.
+// The picker deletes to the trash when its filesystem is a TrashFS, which DirFS is,
+// unless WithTrash(false) is given.
+type TrashFS interface {
+	WritableFS
+	// Trash moves name to the trash and returns the key to restore it with.
+	Trash(name string) (string, error)
+	// Restore moves the trashed entry back to name, which must not exist.
+	Restore(key, name string) error
+}
+
+// Trash moves the entry to a trash of the freedesktop.org trash specification, next to
+// a .trashinfo file recording where it came from, so file managers can restore it too.
+// Entries on the filesystem of the home trash, $XDG_DATA_HOME/Trash, go there; entries
+// on another filesystem go to the trash at the top of their mount, see trashFor.
+func (d dirFS) Trash(name string) (string, error) {
+	p, err := d.join("trash", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(p); err != nil {
+		return "", err
+	}
+	t, err := trashFor(filepath.Dir(p))
+	if err != nil {
+		return "", err
+	}
+	return t.put(p)
+}
+
+// Restore looks for the key in the trash Trash used for name.
+func (d dirFS) Restore(key, name string) error {
+	p, err := d.join("restore", name)
+	if err != nil {
+		return err
+	}
+	t, err := trashFor(filepath.Dir(p))
+	if err != nil {
+		return err
+	}
+	return t.restore(key, p)
+}
+
+// xdgTrash is a trash directory with the files and info subdirectories of the spec.
+// The paths in the info files of a trash at the top of a mount are relative to topdir.
+type xdgTrash struct {
+	dir    string
+	topdir string
+}
+
+func homeTrash() (xdgTrash, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return xdgTrash{}, errors.Wrap(err, "failed to find the trash")
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return makeTrash(xdgTrash{dir: filepath.Join(dataHome, "Trash")})
+}
+
+// trashFor returns the trash for entries in dir: the home trash when dir is on its
+// filesystem, which a rename can't leave, or else the trash at the top of the mount dir
+// is on. Without device numbers, as on Windows, it is always the home trash.
+func trashFor(dir string) (xdgTrash, error) {
+	home, err := homeTrash()
+	if err != nil {
+		return xdgTrash{}, err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return xdgTrash{}, err
+	}
+	dev, ok := deviceOf(dir)
+	homeDev, homeOk := deviceOf(home.dir)
+	if !ok || !homeOk || dev == homeDev {
+		return home, nil
+	}
+	return topdirTrash(mountPoint(dir, dev))
+}
+
+// mountPoint returns the top directory of the mount dir, on device dev, is on.
+func mountPoint(dir string, dev uint64) string {
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		if d, ok := deviceOf(parent); !ok || d != dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// topdirTrash returns the trash at the top of a mount: $topdir/.Trash/$uid when the
+// administrator has set up $topdir/.Trash, a directory with the sticky bit, or else
+// $topdir/.Trash-$uid. Trashes that aren't directories of the user are refused, since
+// others could read what is moved there.
+func topdirTrash(top string) (xdgTrash, error) {
+	uid := strconv.Itoa(os.Getuid())
+	if fi, err := os.Lstat(filepath.Join(top, ".Trash")); err == nil && fi.IsDir() && fi.Mode()&fs.ModeSticky != 0 {
+		if t, err := makeTrash(xdgTrash{dir: filepath.Join(top, ".Trash", uid), topdir: top}); err == nil {
+			return t, nil
+		}
+	}
+	return makeTrash(xdgTrash{dir: filepath.Join(top, ".Trash-"+uid), topdir: top})
+}
+
+// makeTrash creates the directories of t, readable only by the user.
+func makeTrash(t xdgTrash) (xdgTrash, error) {
+	if t.topdir != "" {
+		if err := os.Mkdir(t.dir, 0700); err != nil && !errors.Is(err, fs.ErrExist) {
+			return xdgTrash{}, errors.Wrap(err, "failed to create the trash")
+		}
+		if fi, err := os.Lstat(t.dir); err != nil || !fi.IsDir() || !ownedByUser(fi) {
+			return xdgTrash{}, errors.Errorf("%s is not a trash directory of the user", t.dir)
+		}
+	}
+	for _, dir := range []string{t.files(), t.info()} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return xdgTrash{}, errors.Wrap(err, "failed to create the trash")
+		}
+	}
+	return t, nil
+}
+
+func (t xdgTrash) files() string { return filepath.Join(t.dir, "files") }
+func (t xdgTrash) info() string  { return filepath.Join(t.dir, "info") }
+
+func (t xdgTrash) infoPath(key string) string {
+	return filepath.Join(t.info(), key+".trashinfo")
+}
+
+// put moves the absolute path p to the trash. The .trashinfo file is created first,
+// which reserves a name not used by another trashed entry.
+func (t xdgTrash) put(p string) (string, error) {
+	infoPath := p
+	if t.topdir != "" {
+		if rel, err := filepath.Rel(t.topdir, p); err == nil {
+			infoPath = rel
+		}
+	}
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: infoPath}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+
+	base := filepath.Base(p)
+	for i := 1; ; i++ {
+		key := base
+		if i > 1 {
+			key = fmt.Sprintf("%s.%d", base, i)
+		}
+		f, err := os.OpenFile(t.infoPath(key), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "failed to write the trash info")
+		}
+		if _, err := os.Lstat(filepath.Join(t.files(), key)); err == nil {
+			// A trashed entry without its info file
+			_ = f.Close()
+			_ = os.Remove(t.infoPath(key))
+			continue
+		}
+		_, err = f.WriteString(info)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(p, filepath.Join(t.files(), key))
+		}
+		if err != nil {
+			_ = os.Remove(t.infoPath(key))
+			return "", errors.Wrap(err, "failed to move to the trash")
+		}
+		return key, nil
+	}
+}
+
+func (t xdgTrash) restore(key, p string) error {
+	if strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return &fs.PathError{Op: "restore", Path: key, Err: fs.ErrInvalid}
+	}
+	if _, err := os.Lstat(p); err == nil {
+		return &fs.PathError{Op: "restore", Path: p, Err: fs.ErrExist}
+	}
+	if err := os.Rename(filepath.Join(t.files(), key), p); err != nil {
+		return errors.Wrap(err, "failed to restore from the trash")
+	}
+	_ = os.Remove(t.infoPath(key))
+	return nil
+}
+
+type trashJailFS struct {
+	writableJailFS
+	t TrashFS
+}
+
+func (j trashJailFS) Trash(name string) (string, error) {
+	if err := j.check("trash", name); err != nil {
+		return "", err
+	}
+	return j.t.Trash(name)
+}
+
+func (j trashJailFS) Restore(key, name string) error {
+	if err := j.check("restore", name); err != nil {
+		return err
+	}
+	return j.t.Restore(key, name)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package filepicker
+
+import "os"
+
+// deviceOf returns false: without device numbers everything goes to the home trash.
+func deviceOf(string) (uint64, bool) {
+	return 0, false
+}
+
+func ownedByUser(os.FileInfo) bool {
+	return false
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package filepicker
+
+import (
+	"os"
+	"syscall"
+)
+
+// deviceOf returns the device of the filesystem p is on.
+func deviceOf(p string) (uint64, bool) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return 0, false
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Dev), true
+}
+
+// ownedByUser reports whether fi belongs to the user running the picker.
+func ownedByUser(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid()
+}
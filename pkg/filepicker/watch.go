@@ -0,0 +1,198 @@
+package filepicker
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long changes are collected before the picker reloads, so a burst
+// of events, such as a build writing many files, causes a single reload.
+const watchDebounce = 100 * time.Millisecond
+
+// dirChangedMsg reports the paths that changed in the watched directories.
+type dirChangedMsg struct {
+	seq   int
+	paths []string
+}
+
+// dirWatcher watches the current directory and the directory shown in the preview.
+type dirWatcher struct {
+	seq     int
+	w       *fsnotify.Watcher
+	updates chan tea.Msg
+	done    chan struct{}
+	// watched is only used by the picker, fsnotify runs its own goroutine
+	watched map[string]bool
+}
+
+// run collects events until watchDebounce after the first one and sends them as a
+// dirChangedMsg. An empty path means events were lost and everything should reload.
+// Sending never blocks, so a picker that is dropped without Close doesn't hold run on
+// the send; it still watches until Close stops it.
+func (dw *dirWatcher) run() {
+	defer close(dw.updates)
+
+	pending := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-dw.done:
+			return
+		case ev, ok := <-dw.w.Events:
+			if !ok {
+				return
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			pending[ev.Name] = true
+		case err, ok := <-dw.w.Errors:
+			if !ok {
+				return
+			}
+			if !errors.Is(err, fsnotify.ErrEventOverflow) {
+				continue
+			}
+			pending[""] = true
+		case <-timer:
+			timer = nil
+			msg := dirChangedMsg{seq: dw.seq}
+			for p := range pending {
+				msg.paths = append(msg.paths, p)
+			}
+			pending = map[string]bool{}
+			dw.send(msg)
+			continue
+		}
+		if timer == nil && len(pending) > 0 {
+			timer = time.After(watchDebounce)
+		}
+	}
+}
+
+// send queues msg for the picker. When the picker hasn't taken the previous update
+// yet, the two are merged, so updates holds at most one message.
+func (dw *dirWatcher) send(msg dirChangedMsg) {
+	for {
+		select {
+		case dw.updates <- msg:
+			return
+		default:
+		}
+		select {
+		case old := <-dw.updates:
+			msg.paths = append(old.(dirChangedMsg).paths, msg.paths...)
+		default:
+		}
+	}
+}
+
+// startWatching starts watching the local disk for changes made by other programs,
+// replacing the watcher of an earlier Init. Virtual filesystems aren't watched.
+func (fp *AdvancedModel) startWatching() tea.Cmd {
+	fp.stopWatching()
+	if !fp.watch || !fp.loc.local {
+		return nil
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		// Without a watcher the list is still refreshed by the picker's own changes
+		return nil
+	}
+
+	fp.watchSeq++
+	dw := &dirWatcher{
+		seq:     fp.watchSeq,
+		w:       w,
+		updates: make(chan tea.Msg, 1),
+		done:    make(chan struct{}),
+		watched: map[string]bool{},
+	}
+	fp.watcher = dw
+	fp.updateWatches()
+	go dw.run()
+	return waitForUpdate(dw.updates)
+}
+
+// stopWatching stops the watcher, if there is one.
+func (fp *AdvancedModel) stopWatching() {
+	if fp.watcher == nil {
+		return
+	}
+	close(fp.watcher.done)
+	_ = fp.watcher.w.Close()
+	fp.watcher = nil
+}
+
+// Close stops watching the filesystem, see WithWatch. It does nothing for a picker that
+// doesn't watch.
+func (fp *AdvancedModel) Close() error {
+	fp.stopWatching()
+	return nil
+}
+
+// previewTarget returns the directory shown in the preview, if any.
+func (fp *AdvancedModel) previewTarget() string {
+	if !fp.showPreview || len(fp.filteredFiles) == 0 {
+		return ""
+	}
+	if f := fp.filteredFiles[fp.cursor]; f.IsDir {
+		return f.Path
+	}
+	return ""
+}
+
+// updateWatches makes the watcher follow the current directory and the preview target.
+// Directories that can't be watched are tried again on the next call.
+func (fp *AdvancedModel) updateWatches() {
+	dw := fp.watcher
+	if dw == nil {
+		return
+	}
+	want := map[string]bool{fp.currentPath: true}
+	if target := fp.previewTarget(); target != "" {
+		want[target] = true
+	}
+	for p := range dw.watched {
+		if !want[p] {
+			_ = dw.w.Remove(p)
+			delete(dw.watched, p)
+		}
+	}
+	for p := range want {
+		if !dw.watched[p] && dw.w.Add(p) == nil {
+			dw.watched[p] = true
+		}
+	}
+}
+
+// handleDirChanged reloads the listing when the current directory changed and the
+// preview when only the directory under the cursor did.
+func (fp *AdvancedModel) handleDirChanged(msg dirChangedMsg) tea.Cmd {
+	if fp.watcher == nil || msg.seq != fp.watcher.seq {
+		return nil
+	}
+	next := waitForUpdate(fp.watcher.updates)
+
+	target := fp.previewTarget()
+	reload, preview := false, false
+	for _, p := range msg.paths {
+		switch {
+		case p == "" || p == fp.currentPath || filepath.Dir(p) == fp.currentPath:
+			reload = true
+		case target != "" && (p == target || filepath.Dir(p) == target):
+			preview = true
+		}
+	}
+	if reload {
+		return tea.Batch(next, fp.loadDirectoryCmd())
+	}
+	if preview {
+		fp.updatePreview()
+	}
+	return next
+}
@@ -0,0 +1,161 @@
+package filepicker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchPump runs the commands of a watching picker in the background, like a program
+// would, and feeds their messages back to it.
+type watchPump struct {
+	t    *testing.T
+	fp   *AdvancedModel
+	msgs chan tea.Msg
+}
+
+func newWatchPump(t *testing.T, fp *AdvancedModel) *watchPump {
+	p := &watchPump{t: t, fp: fp, msgs: make(chan tea.Msg, 16)}
+	p.run(fp.Init())
+	return p
+}
+
+func (p *watchPump) run(cmd tea.Cmd) {
+	if cmd != nil {
+		go func() { p.msgs <- cmd() }()
+	}
+}
+
+// until processes messages until done returns true for one of them.
+func (p *watchPump) until(done func(tea.Msg) bool) {
+	p.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-p.msgs:
+			if batch, ok := msg.(tea.BatchMsg); ok {
+				for _, c := range batch {
+					p.run(c)
+				}
+				continue
+			}
+			_, cmd := p.fp.Update(msg)
+			p.run(cmd)
+			if done(msg) {
+				return
+			}
+		case <-timeout:
+			p.t.Fatalf("timed out waiting for the picker to notice the change, listing %v", fileNames(p.fp))
+		}
+	}
+}
+
+// untilListed processes messages until the picker lists the names in want.
+func (p *watchPump) untilListed(want string) {
+	p.t.Helper()
+	p.until(func(tea.Msg) bool {
+		return strings.Join(fileNames(p.fp), ",") == want
+	})
+}
+
+func writeFile(t *testing.T, p, data string) {
+	t.Helper()
+	if err := os.WriteFile(p, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchReloadsOnExternalChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "b.txt"), "b")
+	writeFile(t, filepath.Join(dir, "c.txt"), "c")
+
+	fp := New(WithStartPath(dir), WithWatch(true))
+	defer func() { _ = fp.Close() }()
+	moveCursorTo(t, fp, "c.txt")
+	p := newWatchPump(t, fp)
+	if fp.watcher == nil {
+		t.Fatal("expected Init to start watching")
+	}
+
+	// a new entry before the cursor doesn't move it off c.txt
+	writeFile(t, filepath.Join(dir, "a.txt"), "a")
+	p.untilListed("..,a.txt,b.txt,c.txt")
+	if f := fp.filteredFiles[fp.cursor]; f.Name != "c.txt" {
+		t.Errorf("expected the cursor to stay on c.txt, got %s", f.Name)
+	}
+
+	writeFile(t, filepath.Join(dir, "b.txt"), "modified")
+	p.until(func(tea.Msg) bool {
+		return fp.filteredFiles[2].Size == int64(len("modified"))
+	})
+
+	if err := os.Remove(filepath.Join(dir, "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	p.untilListed("..,a.txt,c.txt")
+	if f := fp.filteredFiles[fp.cursor]; f.Name != "c.txt" {
+		t.Errorf("expected the cursor to stay on c.txt, got %s", f.Name)
+	}
+
+	// the watcher stops with the picker
+	fp.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if fp.watcher != nil {
+		t.Error("expected quitting to stop the watcher")
+	}
+}
+
+func TestWatchRefreshesDirectoryPreview(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fp := New(WithStartPath(dir), WithWatch(true))
+	defer func() { _ = fp.Close() }()
+	moveCursorTo(t, fp, "sub")
+	fp.updatePreview()
+	p := newWatchPump(t, fp)
+	if !strings.Contains(fp.previewContent, "Items: 0") {
+		t.Fatalf("unexpected preview %q", fp.previewContent)
+	}
+
+	writeFile(t, filepath.Join(sub, "new.txt"), "new")
+	p.until(func(tea.Msg) bool {
+		return strings.Contains(fp.previewContent, "Items: 1")
+	})
+	if fp.loading {
+		t.Error("a change inside the previewed directory should not reload the listing")
+	}
+}
+
+func TestWatchIsOptInAndOnlyLocalDisk(t *testing.T) {
+	if New(WithStartPath(t.TempDir())).Init() != nil {
+		t.Error("expected watching to be off by default")
+	}
+	if New(WithFS(fstest.MapFS{"a.txt": {}}), WithWatch(true)).Init() != nil {
+		t.Error("expected a virtual filesystem not to be watched")
+	}
+}
+
+func TestWatchMergesUpdatesThePickerHasNotTaken(t *testing.T) {
+	dw := &dirWatcher{seq: 1, updates: make(chan tea.Msg, 1)}
+
+	// a picker that isn't reading updates doesn't block the watcher
+	dw.send(dirChangedMsg{seq: 1, paths: []string{"/a"}})
+	dw.send(dirChangedMsg{seq: 1, paths: []string{"/b"}})
+
+	msg := (<-dw.updates).(dirChangedMsg)
+	if strings.Join(msg.paths, ",") != "/a,/b" {
+		t.Errorf("expected the updates to be merged, got %v", msg.paths)
+	}
+	if len(dw.updates) != 0 {
+		t.Error("expected a single update")
+	}
+}
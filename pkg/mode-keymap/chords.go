@@ -0,0 +1,309 @@
+package mode_keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+)
+
+// LeaderKey is the placeholder for the leader key in chord bindings, e.g.
+// key.WithKeys("leader s"). Bindings using it are inactive until a leader is
+// set with WithLeader.
+const LeaderKey = "leader"
+
+const defaultChordTimeout = time.Second
+
+// ChordMsg is sent when a key sequence completes. It implements fmt.Stringer
+// with the binding's key as written, so key.Matches(msg, binding) works like
+// it does for a tea.KeyMsg.
+type ChordMsg struct {
+	Keys string
+}
+
+func (c ChordMsg) String() string { return c.Keys }
+
+// ChordTimeoutMsg abandons a pending sequence. Hosts route it to Chords.Update.
+type ChordTimeoutMsg struct {
+	id uint64
+}
+
+// Continuation is a key that can follow the pending sequence.
+type Continuation struct {
+	Key  string
+	Help string
+	// Prefix is set when more keys follow; Help then counts the bindings below.
+	Prefix bool
+}
+
+// WhichKeyStyles styles the popup rendered by Chords.View.
+type WhichKeyStyles struct {
+	Popup   lipgloss.Style
+	Pending lipgloss.Style
+	Key     lipgloss.Style
+	Help    lipgloss.Style
+	Prefix  lipgloss.Style
+}
+
+// DefaultWhichKeyStyles returns the default popup styles.
+func DefaultWhichKeyStyles() WhichKeyStyles {
+	return WhichKeyStyles{
+		Popup: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(0, 1),
+		Pending: lipgloss.NewStyle().Bold(true),
+		Key:     lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
+		Help:    lipgloss.NewStyle().Foreground(lipgloss.Color("250")),
+		Prefix:  lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Italic(true),
+	}
+}
+
+// WhichKeyStylesFromTheme returns the popup styles for a theme.
+func WhichKeyStylesFromTheme(t theme.Theme) WhichKeyStyles {
+	p := t.Palette
+	return WhichKeyStyles{
+		Popup: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(p.Accent).
+			Padding(0, 1),
+		Pending: lipgloss.NewStyle().Foreground(p.Foreground).Bold(true),
+		Key:     lipgloss.NewStyle().Foreground(p.Secondary),
+		Help:    lipgloss.NewStyle().Foreground(p.Foreground),
+		Prefix:  lipgloss.NewStyle().Foreground(p.Muted).Italic(true),
+	}
+}
+
+// Chords recognizes multi-key bindings such as "ctrl+x ctrl+s" or
+// "leader g". A binding is a chord when its key contains spaces; key.Matches
+// never matches such keys against a single key press, so chords can be added
+// to existing keymaps without changing how they dispatch single keys.
+//
+// Chords is a value type, like a bubbles component: keep it in the model and
+// assign the result of Update. It only looks at enabled bindings, so the
+// current mode applies once EnableMode ran.
+type Chords struct {
+	leader  string
+	timeout time.Duration
+	styles  WhichKeyStyles
+
+	pending []string
+	id      uint64
+}
+
+// ChordOption configures Chords.
+type ChordOption func(*Chords)
+
+// WithLeader sets the key that replaces LeaderKey in chord bindings.
+func WithLeader(k string) ChordOption {
+	return func(c *Chords) {
+		c.leader = k
+	}
+}
+
+// WithChordTimeout sets how long a pending sequence waits for its next key
+// (default 1s).
+func WithChordTimeout(d time.Duration) ChordOption {
+	return func(c *Chords) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithWhichKeyStyles sets the popup styles.
+func WithWhichKeyStyles(s WhichKeyStyles) ChordOption {
+	return func(c *Chords) {
+		c.styles = s
+	}
+}
+
+// NewChords returns a chord recognizer.
+func NewChords(options ...ChordOption) Chords {
+	ret := Chords{timeout: defaultChordTimeout, styles: DefaultWhichKeyStyles()}
+	for _, option := range options {
+		option(&ret)
+	}
+	return ret
+}
+
+// WithStyles returns c with new popup styles, keeping the pending sequence.
+func (c Chords) WithStyles(s WhichKeyStyles) Chords {
+	c.styles = s
+	return c
+}
+
+// Pending reports whether a sequence was started and waits for more keys.
+func (c Chords) Pending() bool { return len(c.pending) > 0 }
+
+// PendingKeys returns the keys pressed so far, e.g. "ctrl+x".
+func (c Chords) PendingKeys() string { return strings.Join(c.pending, " ") }
+
+// Reset abandons the pending sequence.
+func (c Chords) Reset() Chords {
+	c.pending = nil
+	return c
+}
+
+type chordBinding struct {
+	raw   string
+	keys  []string
+	help  string
+	first bool
+}
+
+// chordBindings lists the enabled multi-key bindings of keymap with the
+// leader expanded.
+func (c Chords) chordBindings(keymap interface{}) []chordBinding {
+	var ret []chordBinding
+	ForEachKeyBinding(keymap, func(b *key.Binding, _ Modes) {
+		if !b.Enabled() {
+			return
+		}
+		for i, raw := range b.Keys() {
+			keys := strings.Fields(raw)
+			if len(keys) < 2 {
+				continue
+			}
+			ok := true
+			for j, k := range keys {
+				if k == LeaderKey {
+					if c.leader == "" {
+						ok = false
+						break
+					}
+					keys[j] = c.leader
+				}
+			}
+			if ok {
+				ret = append(ret, chordBinding{raw: raw, keys: keys, help: b.Help().Desc, first: i == 0})
+			}
+		}
+	})
+	return ret
+}
+
+func hasPrefix(keys, prefix []string) bool {
+	if len(keys) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if keys[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Update feeds a message to the recognizer. handled reports that the host
+// must not process msg further: the key started, continued or completed a
+// sequence (a completed one is delivered as a ChordMsg by the returned
+// command), broke a pending sequence, or msg is a ChordTimeoutMsg. Esc
+// abandons a pending sequence.
+func (c Chords) Update(keymap interface{}, msg tea.Msg) (Chords, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case ChordTimeoutMsg:
+		if msg.id == c.id {
+			c.pending = nil
+		}
+		return c, nil, true
+
+	case tea.KeyMsg:
+		k := msg.String()
+		if len(c.pending) > 0 && (k == "esc" || k == "escape") {
+			c.pending = nil
+			return c, nil, true
+		}
+		seq := append(append([]string{}, c.pending...), k)
+		continues := false
+		for _, b := range c.chordBindings(keymap) {
+			if len(b.keys) == len(seq) && hasPrefix(b.keys, seq) {
+				c.pending = nil
+				chord := ChordMsg{Keys: b.raw}
+				return c, func() tea.Msg { return chord }, true
+			}
+			if hasPrefix(b.keys, seq) {
+				continues = true
+			}
+		}
+		if continues {
+			c.pending = seq
+			c.id++
+			id := c.id
+			return c, tea.Tick(c.timeout, func(time.Time) tea.Msg { return ChordTimeoutMsg{id: id} }), true
+		}
+		if len(c.pending) > 0 {
+			// an unbound continuation ends the sequence without reaching the host
+			c.pending = nil
+			return c, nil, true
+		}
+	}
+	return c, nil, false
+}
+
+// Continuations lists the keys that can follow the pending sequence, sorted
+// by key.
+func (c Chords) Continuations(keymap interface{}) []Continuation {
+	if len(c.pending) == 0 {
+		return nil
+	}
+	byKey := map[string]*Continuation{}
+	counts := map[string]int{}
+	var keys []string
+	for _, b := range c.chordBindings(keymap) {
+		if len(b.keys) <= len(c.pending) || !hasPrefix(b.keys, c.pending) {
+			continue
+		}
+		next := b.keys[len(c.pending)]
+		cont, ok := byKey[next]
+		if !ok {
+			cont = &Continuation{Key: next}
+			byKey[next] = cont
+			keys = append(keys, next)
+		}
+		if len(b.keys) > len(c.pending)+1 {
+			cont.Prefix = true
+			counts[next]++
+		} else if cont.Help == "" || b.first {
+			cont.Help = b.help
+		}
+	}
+	sort.Strings(keys)
+	ret := make([]Continuation, 0, len(keys))
+	for _, k := range keys {
+		cont := *byKey[k]
+		if cont.Prefix && cont.Help == "" {
+			cont.Help = fmt.Sprintf("+%d more", counts[k])
+		}
+		ret = append(ret, cont)
+	}
+	return ret
+}
+
+// View renders the which-key popup: the pending keys and their
+// continuations. It is empty when no sequence is pending.
+func (c Chords) View(keymap interface{}) string {
+	if len(c.pending) == 0 {
+		return ""
+	}
+	conts := c.Continuations(keymap)
+	keyWidth := 0
+	for _, cont := range conts {
+		keyWidth = max(keyWidth, lipgloss.Width(cont.Key))
+	}
+	lines := []string{c.styles.Pending.Render(c.PendingKeys() + " …")}
+	for _, cont := range conts {
+		helpStyle := c.styles.Help
+		if cont.Prefix {
+			helpStyle = c.styles.Prefix
+		}
+		pad := strings.Repeat(" ", keyWidth-lipgloss.Width(cont.Key))
+		lines = append(lines, c.styles.Key.Render(cont.Key)+pad+"  "+helpStyle.Render(cont.Help))
+	}
+	return c.styles.Popup.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,111 @@
+package mode_keymap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chordKeyMap struct {
+	Save   key.Binding `keymap-mode:"edit"`
+	Open   key.Binding `keymap-mode:"edit"`
+	Grep   key.Binding `keymap-mode:"edit"`
+	Files  key.Binding `keymap-mode:"edit"`
+	Browse key.Binding `keymap-mode:"browse"`
+	Quit   key.Binding `keymap-mode:"*"`
+}
+
+func newChordKeyMap() *chordKeyMap {
+	return &chordKeyMap{
+		Save:   key.NewBinding(key.WithKeys("ctrl+x ctrl+s"), key.WithHelp("C-x C-s", "save")),
+		Open:   key.NewBinding(key.WithKeys("ctrl+x ctrl+f"), key.WithHelp("C-x C-f", "open")),
+		Grep:   key.NewBinding(key.WithKeys("leader s g"), key.WithHelp("<leader> s g", "grep")),
+		Files:  key.NewBinding(key.WithKeys("leader s f"), key.WithHelp("<leader> s f", "files")),
+		Browse: key.NewBinding(key.WithKeys("ctrl+x b"), key.WithHelp("C-x b", "browse")),
+		Quit:   key.NewBinding(key.WithKeys("ctrl+c")),
+	}
+}
+
+func press(t *testing.T, c Chords, km interface{}, k tea.KeyMsg) (Chords, tea.Cmd) {
+	t.Helper()
+	c, cmd, handled := c.Update(km, k)
+	require.True(t, handled, "%s should be handled", k)
+	return c, cmd
+}
+
+func TestChordsCompleteSequenceAndMatchBinding(t *testing.T) {
+	km := newChordKeyMap()
+	EnableMode(km, "edit")
+	c := NewChords()
+
+	c, cmd := press(t, c, km, tea.KeyMsg{Type: tea.KeyCtrlX})
+	require.True(t, c.Pending())
+	assert.Equal(t, "ctrl+x", c.PendingKeys())
+	require.NotNil(t, cmd, "timeout tick")
+
+	// browse is in another mode and not offered
+	assert.Equal(t, []Continuation{
+		{Key: "ctrl+f", Help: "open"},
+		{Key: "ctrl+s", Help: "save"},
+	}, c.Continuations(km))
+	view := c.View(km)
+	assert.Contains(t, view, "ctrl+x …")
+	assert.Contains(t, view, "save")
+
+	c, cmd = press(t, c, km, tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.False(t, c.Pending())
+	msg := cmd()
+	assert.True(t, key.Matches(msg.(ChordMsg), km.Save))
+	assert.False(t, key.Matches(msg.(ChordMsg), km.Open))
+
+	// single keys that start no chord pass through
+	_, _, handled := c.Update(km, tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.False(t, handled)
+}
+
+func TestChordsLeaderTimeoutAndAbandon(t *testing.T) {
+	km := newChordKeyMap()
+	EnableMode(km, "edit")
+
+	// without a leader the leader bindings are inactive
+	_, _, handled := NewChords().Update(km, tea.KeyMsg{Type: tea.KeyCtrlA})
+	assert.False(t, handled)
+
+	c := NewChords(WithLeader("ctrl+a"), WithChordTimeout(time.Millisecond))
+	c, _ = press(t, c, km, tea.KeyMsg{Type: tea.KeyCtrlA})
+	assert.Equal(t, []Continuation{{Key: "s", Help: "+2 more", Prefix: true}}, c.Continuations(km))
+	c, cmd := press(t, c, km, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	assert.Equal(t, "ctrl+a s", c.PendingKeys())
+	assert.Len(t, c.Continuations(km), 2)
+
+	// a stale timeout is ignored, the current one abandons the sequence
+	c, _, _ = c.Update(km, ChordTimeoutMsg{id: c.id - 1})
+	require.True(t, c.Pending())
+	c, _, handled = c.Update(km, cmd())
+	assert.True(t, handled)
+	require.False(t, c.Pending())
+
+	// an unbound continuation and esc both end the sequence without reaching the host
+	c, _ = press(t, c, km, tea.KeyMsg{Type: tea.KeyCtrlX})
+	c, cmd = press(t, c, km, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	assert.Nil(t, cmd)
+	assert.False(t, c.Pending())
+	c, _ = press(t, c, km, tea.KeyMsg{Type: tea.KeyCtrlX})
+	c, _ = press(t, c, km, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, c.Pending())
+}
+
+func TestFindConflictsReportsShadowedChords(t *testing.T) {
+	km := &struct {
+		Cut  key.Binding `keymap-mode:"edit"`
+		Save key.Binding `keymap-mode:"edit"`
+	}{
+		Cut:  key.NewBinding(key.WithKeys("ctrl+x")),
+		Save: key.NewBinding(key.WithKeys("ctrl+x ctrl+s")),
+	}
+	assert.Equal(t, []Conflict{{Mode: "edit", Key: "ctrl+x", Bindings: []string{"Cut", "Save"}}}, FindConflicts(km))
+}
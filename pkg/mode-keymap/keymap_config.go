@@ -0,0 +1,299 @@
+package mode_keymap
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// BindingOverride replaces the keys, and optionally the help text, of one
+// binding. An empty Keys list unbinds it.
+type BindingOverride struct {
+	Keys []string `yaml:"keys"`
+	// Help replaces the description shown in the help view.
+	Help string `yaml:"help,omitempty"`
+}
+
+// UnmarshalYAML accepts a key list or a single key as shorthand for the keys
+// of an override:
+//
+//	submit: enter
+//	history-prev: [up, ctrl+p]
+//	toggle-help:
+//	  keys: [f1]
+//	  help: show help
+func (o *BindingOverride) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var k string
+		if err := node.Decode(&k); err != nil {
+			return err
+		}
+		o.Keys = nil
+		if k != "" {
+			o.Keys = []string{k}
+		}
+		return nil
+	case yaml.SequenceNode:
+		return node.Decode(&o.Keys)
+	}
+	type plain BindingOverride
+	return node.Decode((*plain)(o))
+}
+
+// Overrides maps binding names to their overrides. A name is the field path
+// of the binding in the keymap struct ("Submit", "Nested.Action"), matched
+// case-insensitively and ignoring "-", "_" and spaces, so "history-prev"
+// selects HistoryPrev.
+type Overrides map[string]BindingOverride
+
+// ParseOverrides decodes a YAML keymap document.
+func ParseOverrides(data []byte) (Overrides, error) {
+	var ret Overrides
+	if err := yaml.Unmarshal(data, &ret); err != nil {
+		return nil, errors.Wrap(err, "could not parse keymap")
+	}
+	return ret, nil
+}
+
+// LoadOverrides reads a YAML keymap file.
+func LoadOverrides(path string) (Overrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read keymap %s", path)
+	}
+	ret, err := ParseOverrides(data)
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	return ret, nil
+}
+
+func normalizeBindingName(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "", " ", "").Replace(name))
+}
+
+// lookup returns the override for the binding called name.
+func (o Overrides) lookup(name string) (BindingOverride, bool) {
+	want := normalizeBindingName(name)
+	for k, v := range o {
+		if normalizeBindingName(k) == want {
+			return v, true
+		}
+	}
+	return BindingOverride{}, false
+}
+
+// Apply sets the keys of the bindings in keymap, a pointer to a struct of
+// key.Bindings. Bindings that are not overridden keep their keys. Names that
+// match no binding are reported as an error and nothing is changed. The
+// returned conflicts are the keys an overridden binding now shares with
+// another binding of the same mode.
+func (o Overrides) Apply(keymap interface{}) ([]Conflict, error) {
+	known := map[string]bool{}
+	ForEachNamedKeyBinding(keymap, func(name string, _ *key.Binding, _ Modes) {
+		known[normalizeBindingName(name)] = true
+	})
+	var unknown []string
+	for name := range o {
+		if !known[normalizeBindingName(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.Errorf("unknown key bindings: %s", strings.Join(unknown, ", "))
+	}
+
+	ForEachNamedKeyBinding(keymap, func(name string, b *key.Binding, _ Modes) {
+		override, ok := o.lookup(name)
+		if !ok {
+			return
+		}
+		help := b.Help()
+		if override.Help != "" {
+			help.Desc = override.Help
+		}
+		help.Key = strings.Join(override.Keys, "/")
+		b.SetKeys(override.Keys...)
+		b.SetHelp(help.Key, help.Desc)
+		b.SetEnabled(len(override.Keys) > 0)
+	})
+
+	var ret []Conflict
+	for _, c := range FindConflicts(keymap) {
+		for _, name := range c.Bindings {
+			if _, ok := o.lookup(name); ok {
+				ret = append(ret, c)
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Conflict is a key bound to several bindings that are active in the same mode.
+type Conflict struct {
+	Mode     string
+	Key      string
+	Bindings []string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %s is bound to %s", c.Mode, c.Key, strings.Join(c.Bindings, ", "))
+}
+
+// FindConflicts lists the keys that trigger more than one binding within a
+// mode, including keys that are the start of a chord bound elsewhere (the
+// chord wins, so the shorter binding never fires). Bindings tagged "*" take
+// part in every mode; a keymap without mode tags is checked as the single
+// mode "*".
+func FindConflicts(keymap interface{}) []Conflict {
+	type entry struct {
+		name  string
+		keys  []string
+		modes Modes
+	}
+	var entries []entry
+	modeSet := map[string]struct{}{}
+	ForEachNamedKeyBinding(keymap, func(name string, b *key.Binding, modes Modes) {
+		entries = append(entries, entry{name: name, keys: b.Keys(), modes: modes})
+		for mode := range modes {
+			if mode != "*" {
+				modeSet[mode] = struct{}{}
+			}
+		}
+	})
+	modes := sortedModes(modeSet)
+	if len(modes) == 0 {
+		modes = []string{"*"}
+	}
+
+	var ret []Conflict
+	for _, mode := range modes {
+		byKey := map[string][]string{}
+		var keys []string
+		for _, e := range entries {
+			if !e.modes.Contains(mode) {
+				continue
+			}
+			for _, k := range e.keys {
+				if _, ok := byKey[k]; !ok {
+					keys = append(keys, k)
+				}
+				if !containsString(byKey[k], e.name) {
+					byKey[k] = append(byKey[k], e.name)
+				}
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			bindings := byKey[k]
+			// a key that starts a chord shadows the chord, or is shadowed by it
+			for _, other := range keys {
+				if other != k && hasPrefix(strings.Fields(other), strings.Fields(k)) {
+					for _, name := range byKey[other] {
+						if !containsString(bindings, name) {
+							bindings = append(bindings, name)
+						}
+					}
+				}
+			}
+			if len(bindings) > 1 {
+				ret = append(ret, Conflict{Mode: mode, Key: k, Bindings: bindings})
+			}
+		}
+	}
+	return ret
+}
+
+// BindingInfo describes one binding of an effective keymap.
+type BindingInfo struct {
+	Name  string
+	Modes []string
+	Keys  []string
+	Help  string
+	// Overridden is set when a keymap file changed the binding.
+	Overridden bool
+}
+
+// Describe lists the bindings of keymap in field order, marking the ones
+// changed by overrides (which may be nil).
+func Describe(keymap interface{}, overrides Overrides) []BindingInfo {
+	var ret []BindingInfo
+	ForEachNamedKeyBinding(keymap, func(name string, b *key.Binding, modes Modes) {
+		_, overridden := overrides.lookup(name)
+		modeSet := map[string]struct{}{}
+		for mode := range modes {
+			modeSet[mode] = struct{}{}
+		}
+		ret = append(ret, BindingInfo{
+			Name:       name,
+			Modes:      sortedModes(modeSet),
+			Keys:       b.Keys(),
+			Help:       b.Help().Desc,
+			Overridden: overridden,
+		})
+	})
+	return ret
+}
+
+// FormatKeyMap renders bindings as Markdown, one table per mode set in order
+// of first appearance. Overridden bindings are marked with "*".
+func FormatKeyMap(bindings []BindingInfo) string {
+	var groups []string
+	byGroup := map[string][]BindingInfo{}
+	for _, b := range bindings {
+		group := strings.Join(b.Modes, ", ")
+		if group == "*" {
+			group = "all modes"
+		}
+		if _, ok := byGroup[group]; !ok {
+			groups = append(groups, group)
+		}
+		byGroup[group] = append(byGroup[group], b)
+	}
+
+	var sb strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "### %s\n\n| Binding | Keys | Description |\n|---|---|---|\n", group)
+		for _, b := range byGroup[group] {
+			name := b.Name
+			if b.Overridden {
+				name += " *"
+			}
+			keys := "(unbound)"
+			if len(b.Keys) > 0 {
+				keys = "`" + strings.Join(b.Keys, "` `") + "`"
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s |\n", name, keys, b.Help)
+		}
+	}
+	return sb.String()
+}
+
+func sortedModes(set map[string]struct{}) []string {
+	ret := make([]string, 0, len(set))
+	for mode := range set {
+		ret = append(ret, mode)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
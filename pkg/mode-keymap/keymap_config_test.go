@@ -0,0 +1,112 @@
+package mode_keymap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPanelKeyMap struct {
+	Close key.Binding `keymap-mode:"panel"`
+}
+
+type testKeyMap struct {
+	Submit      key.Binding `keymap-mode:"input"`
+	HistoryPrev key.Binding `keymap-mode:"input"`
+	Select      key.Binding `keymap-mode:"browse"`
+	Help        key.Binding `keymap-mode:"*"`
+	Panel       testPanelKeyMap
+}
+
+func newTestKeyMap() testKeyMap {
+	return testKeyMap{
+		Submit:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit")),
+		HistoryPrev: key.NewBinding(key.WithKeys("up"), key.WithHelp("up", "history prev")),
+		Select:      key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+		Help:        key.NewBinding(key.WithKeys("ctrl+h"), key.WithHelp("ctrl+h", "help")),
+		Panel: testPanelKeyMap{
+			Close: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "close")),
+		},
+	}
+}
+
+func TestOverridesApplyKeysHelpAndUnbind(t *testing.T) {
+	overrides, err := ParseOverrides([]byte(`
+history-prev: [ctrl+p, up]
+submit:
+  keys: [ctrl+s]
+  help: run
+panel.close: []
+help: f1
+`))
+	require.NoError(t, err)
+
+	km := newTestKeyMap()
+	conflicts, err := overrides.Apply(&km)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	assert.Equal(t, []string{"ctrl+p", "up"}, km.HistoryPrev.Keys())
+	assert.Equal(t, "ctrl+p/up", km.HistoryPrev.Help().Key)
+	assert.Equal(t, "history prev", km.HistoryPrev.Help().Desc)
+	assert.Equal(t, "run", km.Submit.Help().Desc)
+	assert.Equal(t, []string{"f1"}, km.Help.Keys())
+	assert.False(t, km.Panel.Close.Enabled())
+
+	// unbound bindings stay disabled when their mode is enabled
+	EnableMode(&km, "panel")
+	assert.False(t, km.Panel.Close.Enabled())
+	assert.True(t, km.Help.Enabled())
+}
+
+func TestOverridesRejectUnknownBindings(t *testing.T) {
+	km := newTestKeyMap()
+	_, err := Overrides{"submit": {Keys: []string{"x"}}, "launch": {Keys: []string{"l"}}}.Apply(&km)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "launch")
+	assert.Equal(t, []string{"enter"}, km.Submit.Keys(), "nothing is applied on error")
+}
+
+func TestOverridesReportConflictsWithinMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("submit: up\nselect: ctrl+h\n"), 0o644))
+	overrides, err := LoadOverrides(path)
+	require.NoError(t, err)
+
+	km := newTestKeyMap()
+	conflicts, err := overrides.Apply(&km)
+	require.NoError(t, err)
+	require.Equal(t, []Conflict{
+		{Mode: "browse", Key: "ctrl+h", Bindings: []string{"Select", "Help"}},
+		{Mode: "input", Key: "up", Bindings: []string{"Submit", "HistoryPrev"}},
+	}, conflicts)
+	assert.Equal(t, "input: up is bound to Submit, HistoryPrev", conflicts[1].String())
+
+	// enter is shared by Submit and Select in different modes, which is fine
+	assert.Empty(t, FindConflicts(&testKeyMap{
+		Submit: key.NewBinding(key.WithKeys("enter")),
+		Select: key.NewBinding(key.WithKeys("enter")),
+	}))
+}
+
+func TestDescribeAndFormatKeyMap(t *testing.T) {
+	km := newTestKeyMap()
+	overrides := Overrides{"Help": {Keys: []string{"f1"}}}
+	_, err := overrides.Apply(&km)
+	require.NoError(t, err)
+
+	infos := Describe(&km, overrides)
+	require.Len(t, infos, 5)
+	assert.Equal(t, BindingInfo{Name: "Panel.Close", Modes: []string{"panel"}, Keys: []string{"esc"}, Help: "close"}, infos[4])
+	assert.True(t, infos[3].Overridden)
+
+	out := FormatKeyMap(infos)
+	assert.Contains(t, out, "### input\n")
+	assert.Contains(t, out, "### all modes\n")
+	assert.Contains(t, out, "| Help * | `f1` | help |")
+	assert.Contains(t, out, "| Submit | `enter` | submit |")
+}
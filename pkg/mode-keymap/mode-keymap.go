@@ -43,11 +43,13 @@ func (m Modes) Contains(mode string) bool {
 // to the callback function f.
 //
 // Nested structs are followed recursively, be it as pointer to structs or a
-// struct itself.
+// struct itself. The name passed to f is the field path from the root keymap,
+// with nested struct fields joined by "." and embedded structs flattened.
 func forEachKeyBinding(
 	keymap interface{},
-	f func(b *key.Binding, modes Modes),
+	f func(name string, b *key.Binding, modes Modes),
 	modes Modes,
+	prefix string,
 ) {
 	if keymap == nil {
 		return
@@ -76,8 +78,14 @@ func forEachKeyBinding(
 		field := v.Field(i)
 		kind := field.Kind()
 
+		structField := v.Type().Field(i)
+		path := prefix
+		if !structField.Anonymous {
+			path += structField.Name
+		}
+
 		modes_ := modes
-		modeTag := v.Type().Field(i).Tag.Get("keymap-mode")
+		modeTag := structField.Tag.Get("keymap-mode")
 		if modeTag != "" {
 			modes_ = NewModes(strings.Split(modeTag, ","))
 		}
@@ -90,33 +98,40 @@ func forEachKeyBinding(
 			pkgPath := type_.PkgPath()
 			if name == "Binding" && pkgPath == "github.com/charmbracelet/bubbles/key" {
 				if addr, ok := field.Addr().Interface().(*key.Binding); ok {
-					f(addr, modes_)
+					f(path, addr, modes_)
 				}
 				continue
 			}
 
 			// recurse into the struct
-			forEachKeyBinding(field.Addr().Interface(), f, modes_)
+			forEachKeyBinding(field.Addr().Interface(), f, modes_, nestedPrefix(path, structField))
 
 		case reflect.Ptr:
 			name := field.Type().Elem().Name()
 			pkg := field.Type().Elem().PkgPath()
 			if name == "Binding" && pkg == "github.com/charmbracelet/bubbles/key" {
 				// get the modes
-				if addr, ok := field.Interface().(*key.Binding); ok {
-					f(addr, modes_)
+				if addr, ok := field.Interface().(*key.Binding); ok && addr != nil {
+					f(path, addr, modes_)
 				}
 				continue
 			}
 
 			// recurse into the struct
-			forEachKeyBinding(field.Interface(), f, modes_)
+			forEachKeyBinding(field.Interface(), f, modes_, nestedPrefix(path, structField))
 
 		default:
 		}
 	}
 }
 
+func nestedPrefix(path string, field reflect.StructField) string {
+	if field.Anonymous {
+		return path
+	}
+	return path + "."
+}
+
 // ForEachKeyBinding calls the given function f on every key.Binding in the given
 // keymap, passing the binding and its associated modes. It recurses into nested
 // structs and pointers, extracting all key.Bindings.
@@ -126,16 +141,27 @@ func ForEachKeyBinding(keymap interface{}, f func(b *key.Binding, modes Modes))
 		panic("keymap must be a pointer to a struct")
 	}
 
-	forEachKeyBinding(keymap, func(b *key.Binding, modes Modes) {
+	forEachKeyBinding(keymap, func(_ string, b *key.Binding, modes Modes) {
 		f(b, modes)
-	}, NewModes([]string{"*"}))
+	}, NewModes([]string{"*"}), "")
+}
+
+// ForEachNamedKeyBinding is like ForEachKeyBinding but also passes the field
+// path of each binding, e.g. "Submit" or "Nested.Action".
+func ForEachNamedKeyBinding(keymap interface{}, f func(name string, b *key.Binding, modes Modes)) {
+	if reflect.TypeOf(keymap).Kind() != reflect.Ptr {
+		panic("keymap must be a pointer to a struct")
+	}
+
+	forEachKeyBinding(keymap, f, NewModes([]string{"*"}), "")
 }
 
 // EnableMode enables all key bindings in the given keymap that are tagged
-// with the provided mode.
+// with the provided mode. Bindings without keys (for example unbound by a
+// keymap file) stay disabled.
 func EnableMode(keymap interface{}, mode string) {
 	ForEachKeyBinding(keymap, func(b *key.Binding, modes Modes) {
-		if modes.Contains(mode) {
+		if modes.Contains(mode) && len(b.Keys()) > 0 {
 			b.SetEnabled(true)
 		} else {
 			b.SetEnabled(false)
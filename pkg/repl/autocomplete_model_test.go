@@ -471,3 +471,157 @@ func TestCompletionPopupStyleNoBorderRemovesFrame(t *testing.T) {
 	assert.Equal(t, 0, noBorder.GetHorizontalFrameSize())
 	assert.Equal(t, 0, noBorder.GetVerticalFrameSize())
 }
+
+func TestCompletionViewShowsDocumentationPane(t *testing.T) {
+	evaluator := &fakeCompleterEvaluator{
+		result: CompletionResult{
+			Show: true,
+			Suggestions: []autocomplete.Suggestion{
+				{Id: "1", Value: "concat", DisplayText: "concat", Kind: autocomplete.KindFunction,
+					Detail: "(a, b) -> string", Documentation: "Joins two strings."},
+				{Id: "2", Value: "const", DisplayText: "const", Kind: autocomplete.KindKeyword},
+			},
+			ReplaceFrom: 0,
+			ReplaceTo:   1,
+		},
+	}
+	m := newAutocompleteTestModel(t, evaluator)
+	_, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	drainModelCmds(m, cmd)
+	require.True(t, m.completion.visible)
+
+	view := m.View()
+	assert.Contains(t, view, "ƒ concat")
+	assert.Contains(t, view, "Joins two strings.")
+
+	// the keyword has no documentation, so the pane goes away with the selection
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	drainModelCmds(m, cmd)
+	view = m.View()
+	assert.Contains(t, view, "k const")
+	assert.NotContains(t, view, "Joins two strings.")
+}
+
+func TestCompletionClientFilterSkipsEvaluatorWhileTyping(t *testing.T) {
+	evaluator := &fakeCompleterEvaluator{
+		result: CompletionResult{
+			Show: true,
+			Suggestions: []autocomplete.Suggestion{
+				{Id: "1", Value: "const", DisplayText: "const"},
+				{Id: "2", Value: "console", DisplayText: "console"},
+				{Id: "3", Value: "count", DisplayText: "count"},
+			},
+			ReplaceFrom: 0,
+			ReplaceTo:   1,
+		},
+	}
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+	cfg := DefaultConfig()
+	cfg.Autocomplete = DefaultAutocompleteConfig()
+	cfg.Autocomplete.Debounce = time.Nanosecond
+	cfg.Autocomplete.RequestTimeout = 50 * time.Millisecond
+	cfg.Autocomplete.ClientFilter = true
+	m := NewModel(evaluator, cfg, bus.Publisher)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	drainModelCmds(m, cmd)
+	require.Len(t, evaluator.requests, 1)
+	require.Len(t, m.completion.lastResult.Suggestions, 3)
+
+	for _, r := range "ons" {
+		_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		drainModelCmds(m, cmd)
+	}
+	require.Len(t, evaluator.requests, 1, "typing within the token filters locally")
+	assert.True(t, m.completion.visible)
+	values := []string{}
+	for _, s := range m.completion.lastResult.Suggestions {
+		values = append(values, s.Value)
+	}
+	assert.Equal(t, []string{"const", "console"}, values)
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	drainModelCmds(m, cmd)
+	assert.Equal(t, "const", m.textInput.Value())
+}
+
+func TestSnippetCompletionTabsThroughPlaceholders(t *testing.T) {
+	evaluator := &fakeCompleterEvaluator{
+		result: CompletionResult{
+			Show: true,
+			Suggestions: []autocomplete.Suggestion{
+				{Id: "1", Value: "concat(${1:a}, ${2:b})$0", DisplayText: "concat", Snippet: true},
+			},
+			ReplaceFrom: 0,
+			ReplaceTo:   1,
+		},
+	}
+	m := newAutocompleteTestModel(t, evaluator)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	drainModelCmds(m, cmd)
+	require.True(t, m.completion.visible)
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	drainModelCmds(m, cmd)
+	require.Equal(t, "concat(a, b)", m.textInput.Value())
+	require.Equal(t, 8, m.textInput.Position())
+
+	// typing replaces the placeholder, tab moves on to the next one
+	typeRunes(m, "xs")
+	require.Equal(t, "concat(xs, b)", m.textInput.Value())
+	m.completion.visible = false
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	drainModelCmds(m, cmd)
+	require.Equal(t, 12, m.textInput.Position())
+
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeRunes(m, "ys")
+	require.Equal(t, "concat(xs, ys)", m.textInput.Value())
+
+	// shift+tab goes back; the final tab leaves the cursor after the call
+	m.completion.visible = false
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	require.Equal(t, 9, m.textInput.Position())
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, 14, m.textInput.Position())
+	require.False(t, m.completion.widget.SnippetActive())
+
+	// the input counts the cursor in runes, the snippet stops are byte offsets
+	evaluator = &fakeCompleterEvaluator{
+		result: CompletionResult{
+			Show: true,
+			Suggestions: []autocomplete.Suggestion{
+				{Id: "1", Value: "f(${1:café}, ${2:b})$0", DisplayText: "f", Snippet: true},
+			},
+			ReplaceFrom: 0,
+			ReplaceTo:   1,
+		},
+	}
+	m = newAutocompleteTestModel(t, evaluator)
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	drainModelCmds(m, cmd)
+	require.True(t, m.completion.visible)
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	drainModelCmds(m, cmd)
+	require.Equal(t, "f(café, b)", m.textInput.Value())
+	require.Equal(t, 6, m.textInput.Position())
+
+	m.completion.visible = false
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	drainModelCmds(m, cmd)
+	require.Equal(t, 9, m.textInput.Position())
+	typeRunes(m, "x")
+	require.Equal(t, "f(café, x)", m.textInput.Value())
+
+	m.completion.visible = false
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, 10, m.textInput.Position())
+	require.False(t, m.completion.widget.SnippetActive())
+}
@@ -0,0 +1,82 @@
+package repl
+
+import (
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+)
+
+// ChromaHighlighter is the default InputHighlighter, using the chroma lexer matching a file
+// extension and a chroma style, the same library glamour uses for markdown code blocks.
+type ChromaHighlighter struct {
+	lexer chroma.Lexer
+	style *chroma.Style
+
+	mu        sync.Mutex
+	lastInput string
+	lastSpans []HighlightSpan
+}
+
+var _ InputHighlighter = (*ChromaHighlighter)(nil)
+
+// NewChromaHighlighter returns a highlighter for files with the given extension (".js", ".py",
+// ...). It returns nil when chroma has no lexer for the extension, or only the plaintext one.
+// Unknown style names fall back to chroma's default style.
+func NewChromaHighlighter(fileExtension string, styleName string) *ChromaHighlighter {
+	lexer := lexers.Match("input" + fileExtension)
+	if lexer == nil || lexer.Config().Name == "plaintext" {
+		return nil
+	}
+	return &ChromaHighlighter{lexer: chroma.Coalesce(lexer), style: styles.Get(styleName)}
+}
+
+// HighlightInput implements InputHighlighter.
+func (h *ChromaHighlighter) HighlightInput(input string) []HighlightSpan {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if input == h.lastInput && h.lastSpans != nil {
+		return h.lastSpans
+	}
+	it, err := h.lexer.Tokenise(nil, input)
+	if err != nil {
+		return nil
+	}
+	spans := []HighlightSpan{}
+	pos := 0
+	for tok := it(); tok != chroma.EOF; tok = it() {
+		n := len([]rune(tok.Value))
+		if st, ok := h.tokenStyle(tok.Type); ok {
+			spans = append(spans, HighlightSpan{Span: autocomplete.Span{Start: pos, End: pos + n}, Style: st})
+		}
+		pos += n
+	}
+	h.lastInput, h.lastSpans = input, spans
+	return spans
+}
+
+func (h *ChromaHighlighter) tokenStyle(t chroma.TokenType) (lipgloss.Style, bool) {
+	entry := h.style.Get(t)
+	st := lipgloss.NewStyle()
+	set := false
+	if entry.Colour.IsSet() {
+		st = st.Foreground(lipgloss.Color(entry.Colour.String()))
+		set = true
+	}
+	if entry.Bold == chroma.Yes {
+		st = st.Bold(true)
+		set = true
+	}
+	if entry.Italic == chroma.Yes {
+		st = st.Italic(true)
+		set = true
+	}
+	if entry.Underline == chroma.Yes {
+		st = st.Underline(true)
+		set = true
+	}
+	return st, set
+}
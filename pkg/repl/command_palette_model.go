@@ -2,11 +2,13 @@ package repl
 
 import (
 	"context"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/go-go-golems/bobatea/pkg/commandpalette"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 	"github.com/rs/zerolog/log"
 )
 
@@ -31,7 +33,9 @@ func (m *Model) handleCommandPaletteInput(k tea.KeyMsg) (bool, tea.Cmd) {
 	}
 
 	if m.palette.ui.IsVisible() {
-		if key.Matches(k, m.keyMap.CommandPaletteClose) || key.Matches(k, m.keyMap.CommandPaletteOpen) {
+		// while arguments are collected the palette handles esc itself to step back
+		closeKey := key.Matches(k, m.keyMap.CommandPaletteClose) || key.Matches(k, m.keyMap.CommandPaletteOpen)
+		if closeKey && !m.palette.ui.CollectingArguments() {
 			m.palette.ui.Hide()
 			return true, nil
 		}
@@ -72,24 +76,46 @@ func (m *Model) listPaletteCommands(ctx context.Context) []commandpalette.Comman
 
 	ret := make([]commandpalette.Command, 0, len(paletteCommands))
 	for _, pc := range paletteCommands {
-		if pc.Name == "" || pc.Action == nil {
-			continue
-		}
-		if pc.Enabled != nil && !pc.Enabled(m) {
+		if pc.Name == "" || (pc.Action == nil && pc.Run == nil) {
 			continue
 		}
 		paletteCommand := pc
-		ret = append(ret, commandpalette.Command{
+		var enabled func() bool
+		if paletteCommand.Enabled != nil {
+			enabled = func() bool { return paletteCommand.Enabled(m) }
+		}
+		command := commandpalette.Command{
+			ID:          paletteCommand.ID,
 			Name:        paletteCommand.Name,
 			Description: paletteCommand.Description,
-			Action: func() tea.Cmd {
+			Category:    paletteCommand.Category,
+			Keywords:    paletteCommand.Keywords,
+			KeyHint:     paletteCommand.KeyHint,
+			Enabled:     enabled,
+			Params:      paletteCommand.Params,
+		}
+		if paletteCommand.Run != nil {
+			command.Run = func(args commandpalette.Args) tea.Cmd {
+				return paletteCommand.Run(m, args)
+			}
+		} else {
+			command.Action = func() tea.Cmd {
 				return paletteCommand.Action(m)
-			},
-		})
+			}
+		}
+		ret = append(ret, command)
 	}
 	return ret
 }
 
+// bindingHint returns the key shown for a palette command bound to b.
+func bindingHint(b key.Binding) string {
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key
+}
+
 func (m *Model) builtinPaletteCommands() []PaletteCommand {
 	commands := []PaletteCommand{
 		{
@@ -115,6 +141,8 @@ func (m *Model) builtinPaletteCommands() []PaletteCommand {
 			},
 			Action: func(m *Model) tea.Cmd {
 				m.history.Clear()
+				m.refreshInlineHistory()
+				m.clearInlineSuggestion()
 				return nil
 			},
 		},
@@ -124,6 +152,7 @@ func (m *Model) builtinPaletteCommands() []PaletteCommand {
 			Description: "Toggle key help visibility",
 			Category:    "help",
 			Keywords:    []string{"help", "keys"},
+			KeyHint:     bindingHint(m.keyMap.ToggleHelp),
 			Action: func(m *Model) tea.Cmd {
 				m.help.ShowAll = !m.help.ShowAll
 				return nil
@@ -135,6 +164,7 @@ func (m *Model) builtinPaletteCommands() []PaletteCommand {
 			Description: "Switch focus between input and timeline",
 			Category:    "repl",
 			Keywords:    []string{"focus", "timeline", "input"},
+			KeyHint:     bindingHint(m.keyMap.ToggleFocus),
 			Action: func(m *Model) tea.Cmd {
 				if m.focus == "input" {
 					m.focus = "timeline"
@@ -149,12 +179,77 @@ func (m *Model) builtinPaletteCommands() []PaletteCommand {
 				return nil
 			},
 		},
+		{
+			ID:          "repl.cancel-evaluation",
+			Name:        "Cancel Evaluation",
+			Description: "Interrupt the running evaluation and drop queued turns",
+			Category:    "repl",
+			Keywords:    []string{"interrupt", "stop", "abort"},
+			KeyHint:     bindingHint(m.keyMap.Quit),
+			Enabled: func(m *Model) bool {
+				return m.Running() || m.countTurns(TurnPending) > 0
+			},
+			Action: func(m *Model) tea.Cmd {
+				m.CancelEvaluation()
+				return nil
+			},
+		},
+		{
+			ID:          "repl.show-key-bindings",
+			Name:        "Show Key Bindings",
+			Description: "List the effective key bindings and their conflicts",
+			Category:    "help",
+			Keywords:    []string{"keymap", "shortcuts", "keys"},
+			Action: func(m *Model) tea.Cmd {
+				m.showKeyBindings()
+				return nil
+			},
+		},
+		{
+			ID:          "repl.show-turns",
+			Name:        "Show Turns",
+			Description: "List pending, running and finished evaluations",
+			Category:    "repl",
+			Keywords:    []string{"queue", "jobs", "running"},
+			KeyHint:     bindingHint(m.keyMap.TurnList),
+			Action: func(m *Model) tea.Cmd {
+				m.openTurnList()
+				return nil
+			},
+		},
+		{
+			ID:          "repl.load-script",
+			Name:        "Load Script",
+			Description: "Evaluate the contents of a file",
+			Category:    "repl",
+			Keywords:    []string{"source", "file", "run"},
+			Params: []PaletteParam{
+				{Name: "path", Prompt: "Script path", Kind: commandpalette.ParamPath},
+			},
+			Run: func(m *Model, args PaletteArgs) tea.Cmd {
+				return m.loadScript(args.String("path"))
+			},
+		},
+		{
+			ID:          "repl.switch-theme",
+			Name:        "Switch Theme",
+			Description: "Restyle the REPL and timeline with another color theme",
+			Category:    "repl",
+			Keywords:    []string{"theme", "colors", "dark", "light"},
+			Params: []PaletteParam{
+				{Name: "theme", Prompt: "Theme", Kind: commandpalette.ParamChoice, Choices: append(theme.Names(), theme.Auto)},
+			},
+			Run: func(m *Model, args PaletteArgs) tea.Cmd {
+				return m.switchTheme(args.String("theme"))
+			},
+		},
 		{
 			ID:          "repl.quit",
 			Name:        "Quit REPL",
 			Description: "Exit the REPL application",
 			Category:    "repl",
 			Keywords:    []string{"exit", "quit"},
+			KeyHint:     bindingHint(m.keyMap.Quit),
 			Action: func(m *Model) tea.Cmd {
 				m.cancelAppContext()
 				return tea.Quit
@@ -169,6 +264,7 @@ func (m *Model) builtinPaletteCommands() []PaletteCommand {
 			Description: "Open or close contextual help drawer",
 			Category:    "help",
 			Keywords:    []string{"drawer", "help"},
+			KeyHint:     bindingHint(m.keyMap.HelpDrawerToggle),
 			Action: func(m *Model) tea.Cmd {
 				return m.toggleHelpDrawer()
 			},
@@ -178,6 +274,25 @@ func (m *Model) builtinPaletteCommands() []PaletteCommand {
 	return commands
 }
 
+// loadScript submits the contents of path as one input, subject to the turn policy.
+func (m *Model) loadScript(path string) tea.Cmd {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.showHelpBarNotice(err.Error(), "error")
+		return nil
+	}
+	code := strings.TrimRight(string(data), "\n")
+	if strings.TrimSpace(code) == "" {
+		m.showHelpBarNotice(path+" is empty", "warning")
+		return nil
+	}
+	if ok, reason := m.acceptsInput(); !ok {
+		m.showHelpBarNotice(reason, "warning")
+		return nil
+	}
+	return m.submit(code)
+}
+
 func mergePaletteCommands(base, extra []PaletteCommand) []PaletteCommand {
 	ret := make([]PaletteCommand, 0, len(base)+len(extra))
 	seen := make(map[string]struct{}, len(base)+len(extra))
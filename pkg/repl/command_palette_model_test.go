@@ -2,6 +2,8 @@ package repl
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -273,3 +275,78 @@ func TestCommandPaletteSlashOpenDisabledFallsThrough(t *testing.T) {
 	assert.False(t, m.palette.ui.IsVisible())
 	assert.Equal(t, "/", m.textInput.Value())
 }
+
+func TestCommandPaletteKeepsCategoriesKeyHintsAndEnabled(t *testing.T) {
+	enabled := false
+	evaluator := &fakeCommandPaletteEvaluator{
+		commands: []PaletteCommand{
+			{
+				ID:       "custom.deploy",
+				Name:     "Deploy",
+				Category: "ops",
+				Keywords: []string{"ship"},
+				KeyHint:  "alt+d",
+				Enabled:  func(*Model) bool { return enabled },
+				Action:   func(*Model) tea.Cmd { return nil },
+			},
+		},
+	}
+	m := newCommandPaletteTestModel(t, evaluator, nil)
+
+	commands := m.listPaletteCommands(context.Background())
+	byID := map[string]int{}
+	for i, c := range commands {
+		byID[c.ID] = i
+	}
+	deploy := commands[byID["custom.deploy"]]
+	assert.Equal(t, "ops", deploy.Category)
+	assert.Equal(t, []string{"ship"}, deploy.Keywords)
+	assert.Equal(t, "ctrl+h", commands[byID["repl.toggle-help"]].KeyHint)
+
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyCtrlP})
+	for _, r := range "ship" {
+		_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	assert.Empty(t, m.palette.ui.FilteredCommands(), "disabled commands are hidden")
+
+	enabled = true
+	m.palette.ui.Hide()
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyCtrlP})
+	for _, r := range "ship" {
+		_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	selected, ok := m.palette.ui.SelectedCommand()
+	require.True(t, ok)
+	assert.Equal(t, "Deploy", selected.Name)
+	assert.Contains(t, m.palette.ui.View(), "alt+d")
+}
+
+func TestCommandPaletteLoadScriptCollectsPathArgument(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "init.js")
+	require.NoError(t, os.WriteFile(script, []byte("let x = 1\nx + 1\n"), 0o644))
+	m := newCommandPaletteTestModel(t, &fakeCommandPaletteEvaluator{}, nil)
+
+	typeKeys := func(s string) {
+		for _, r := range s {
+			_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyCtrlP})
+	typeKeys("load script")
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, m.palette.ui.CollectingArguments())
+
+	// esc leaves the argument step but keeps the palette open
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyEsc})
+	require.True(t, m.palette.ui.IsVisible())
+	require.False(t, m.palette.ui.CollectingArguments())
+
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyEnter})
+	typeKeys(script)
+	_, cmd := m.updateInput(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	assert.False(t, m.palette.ui.IsVisible())
+	turns := m.Turns()
+	require.Len(t, turns, 1)
+	assert.Equal(t, "let x = 1\nx + 1", turns[0].Input)
+}
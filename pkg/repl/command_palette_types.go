@@ -4,8 +4,15 @@ import (
 	"context"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/commandpalette"
 )
 
+// PaletteParam is an argument a palette command asks for before it runs.
+type PaletteParam = commandpalette.Param
+
+// PaletteArgs holds the parsed arguments passed to PaletteCommand.Run, keyed by parameter name.
+type PaletteArgs = commandpalette.Args
+
 // PaletteCommand describes a REPL command that can be executed from the command palette.
 type PaletteCommand struct {
 	ID          string
@@ -13,8 +20,15 @@ type PaletteCommand struct {
 	Description string
 	Category    string
 	Keywords    []string
-	Enabled     func(*Model) bool
-	Action      func(*Model) tea.Cmd
+	// KeyHint is the key bound to the command, shown in the palette row.
+	KeyHint string
+	// Enabled hides the command from the palette while it returns false.
+	Enabled func(*Model) bool
+	Action  func(*Model) tea.Cmd
+	// Params are collected by the palette before Run is called; commands with Params use Run
+	// instead of Action.
+	Params []PaletteParam
+	Run    func(*Model, PaletteArgs) tea.Cmd
 }
 
 // PaletteCommandProvider allows evaluators to contribute commands to the REPL command palette.
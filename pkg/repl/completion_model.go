@@ -3,8 +3,10 @@ package repl
 import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
 	"github.com/go-go-golems/bobatea/pkg/tui/widgets/suggest"
 	"time"
+	"unicode/utf8"
 )
 
 type completionModel struct {
@@ -32,6 +34,9 @@ type completionModel struct {
 	noBorder   bool
 	placement  CompletionOverlayPlacement
 	horizontal CompletionOverlayHorizontalGrow
+	docWidth   int
+	icons      map[autocomplete.SuggestionKind]string
+	filter     bool
 
 	lastResult  CompletionResult
 	lastError   error
@@ -50,7 +55,7 @@ func (a completionBufferAdapter) Value() string {
 }
 
 func (a completionBufferAdapter) CursorByte() int {
-	return a.model.textInput.Position()
+	return a.model.completionCursor()
 }
 
 func (a completionBufferAdapter) SetValue(value string) {
@@ -58,25 +63,47 @@ func (a completionBufferAdapter) SetValue(value string) {
 }
 
 func (a completionBufferAdapter) SetCursorByte(cursor int) {
-	a.model.textInput.SetCursor(cursor)
+	value := a.model.textInput.Value()
+	cursor = max(0, min(cursor, len(value)))
+	a.model.textInput.SetCursor(utf8.RuneCountInString(value[:cursor]))
+}
+
+// completionCursor returns the cursor as the byte offset the completion widget works
+// with; the input counts runes.
+func (m *Model) completionCursor() int {
+	return runeToByteOffset(m.textInput.Value(), m.textInput.Position())
+}
+
+// runeToByteOffset converts a cursor position in runes into a byte offset in s.
+func runeToByteOffset(s string, pos int) int {
+	for i := range s {
+		if pos == 0 {
+			return i
+		}
+		pos--
+	}
+	return len(s)
 }
 
 func (m *Model) ensureCompletionWidget() {
 	if m.completion.widget == nil {
 		m.completion.widget = suggest.New(m.completion.provider, suggest.Config{
-			Debounce:       m.completion.debounce,
-			RequestTimeout: m.completion.reqTimeout,
-			MaxVisible:     m.completion.maxVisible,
-			PageSize:       m.completion.pageSize,
-			MaxWidth:       m.completion.maxWidth,
-			MaxHeight:      m.completion.maxHeight,
-			MinWidth:       m.completion.minWidth,
-			Margin:         m.completion.margin,
-			OffsetX:        m.completion.offsetX,
-			OffsetY:        m.completion.offsetY,
-			NoBorder:       m.completion.noBorder,
-			Placement:      suggest.Placement(m.completion.placement),
-			HorizontalGrow: suggest.HorizontalGrow(m.completion.horizontal),
+			Debounce:           m.completion.debounce,
+			RequestTimeout:     m.completion.reqTimeout,
+			MaxVisible:         m.completion.maxVisible,
+			PageSize:           m.completion.pageSize,
+			MaxWidth:           m.completion.maxWidth,
+			MaxHeight:          m.completion.maxHeight,
+			MinWidth:           m.completion.minWidth,
+			Margin:             m.completion.margin,
+			OffsetX:            m.completion.offsetX,
+			OffsetY:            m.completion.offsetY,
+			NoBorder:           m.completion.noBorder,
+			Placement:          suggest.Placement(m.completion.placement),
+			HorizontalGrow:     suggest.HorizontalGrow(m.completion.horizontal),
+			Icons:              m.completion.icons,
+			DocumentationWidth: m.completion.docWidth,
+			ClientFilter:       m.completion.filter,
 		})
 	}
 	m.syncCompletionWidgetFromLegacy()
@@ -104,6 +131,8 @@ func (m *Model) syncCompletionWidgetFromLegacy() {
 	w.SetNoBorder(m.completion.noBorder)
 	w.SetPlacement(suggest.Placement(m.completion.placement))
 	w.SetHorizontalGrow(suggest.HorizontalGrow(m.completion.horizontal))
+	w.SetDocumentationWidth(m.completion.docWidth)
+	w.SetClientFilter(m.completion.filter)
 	w.SetLastResult(m.completion.lastResult)
 	w.SetRequestSeq(m.completion.reqSeq)
 	w.SetRequestTimeout(m.completion.reqTimeout)
@@ -144,7 +173,7 @@ func (m *Model) scheduleDebouncedCompletionIfNeeded(prevValue string, prevCursor
 	if m.completion.widget == nil {
 		return nil
 	}
-	cmd := m.completion.widget.OnBufferChanged(prevValue, prevCursor, m.textInput.Value(), m.textInput.Position())
+	cmd := m.completion.widget.OnBufferChanged(prevValue, runeToByteOffset(prevValue, prevCursor), m.textInput.Value(), m.completionCursor())
 	m.syncCompletionLegacyFromWidget()
 	return cmd
 }
@@ -154,7 +183,7 @@ func (m *Model) handleDebouncedCompletion(msg completionDebounceMsg) tea.Cmd {
 	if m.completion.widget == nil {
 		return nil
 	}
-	cmd := m.completion.widget.HandleDebounce(m.appContext(), msg, m.textInput.Value(), m.textInput.Position())
+	cmd := m.completion.widget.HandleDebounce(m.appContext(), msg, m.textInput.Value(), m.completionCursor())
 	m.syncCompletionLegacyFromWidget()
 	return cmd
 }
@@ -167,7 +196,7 @@ func (m *Model) triggerCompletionFromShortcut(k tea.KeyMsg) tea.Cmd {
 	if !key.Matches(k, m.keyMap.CompletionTrigger) {
 		return nil
 	}
-	cmd := m.completion.widget.TriggerShortcut(m.appContext(), m.textInput.Value(), m.textInput.Position(), k.String())
+	cmd := m.completion.widget.TriggerShortcut(m.appContext(), m.textInput.Value(), m.completionCursor(), k.String())
 	m.syncCompletionLegacyFromWidget()
 	return cmd
 }
@@ -221,6 +250,44 @@ func (m *Model) handleCompletionNavigation(k tea.KeyMsg) (bool, tea.Cmd) {
 	return false, nil
 }
 
+// handleSnippetInput drives an accepted snippet completion: tab/shift+tab move between its
+// placeholders, esc leaves it, and typing on an untouched placeholder replaces its text.
+func (m *Model) handleSnippetInput(k tea.KeyMsg, prevValue string, prevCursor int) (bool, tea.Cmd) {
+	w := m.completion.widget
+	if w == nil || !w.SnippetActive() {
+		return false, nil
+	}
+	buffer := completionBufferAdapter{model: m}
+	switch {
+	case key.Matches(k, m.keyMap.SnippetNext):
+		return w.NextSnippetStop(buffer), nil
+	case key.Matches(k, m.keyMap.SnippetPrev):
+		return w.PrevSnippetStop(buffer), nil
+	case k.Type == tea.KeyEsc:
+		w.EndSnippet()
+		return true, nil
+	case k.Type == tea.KeyRunes, k.Type == tea.KeySpace:
+		// clear the placeholder and let the input insert the key
+		w.ClearPlaceholder(buffer)
+	case k.Type == tea.KeyBackspace, k.Type == tea.KeyDelete:
+		if w.ClearPlaceholder(buffer) {
+			return true, tea.Batch(
+				m.scheduleDebouncedCompletionIfNeeded(prevValue, prevCursor),
+				m.scheduleInlineSuggestion(prevValue, prevCursor),
+				m.scheduleDebouncedHelpBarIfNeeded(prevValue, prevCursor),
+				m.scheduleDebouncedHelpDrawerIfNeeded(prevValue, prevCursor),
+			)
+		}
+	}
+	return false, nil
+}
+
+func (m *Model) endSnippet() {
+	if m.completion.widget != nil {
+		m.completion.widget.EndSnippet()
+	}
+}
+
 func (m *Model) ensureCompletionSelectionVisible() {
 	m.ensureCompletionWidget()
 	if m.completion.widget == nil {
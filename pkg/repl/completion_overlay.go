@@ -11,14 +11,13 @@ func (m *Model) computeCompletionOverlayLayout(header, timelineView string) (com
 		return completionOverlayLayout{}, false
 	}
 	m.syncCompletionWidgetFromLegacy()
-	layout, ok := m.completion.widget.ComputeOverlayLayout(
+	// anchor at the cursor, which may sit on any row of a multiline input
+	cursorRow, cursorCol := m.textInput.CursorAnchor()
+	layout, ok := m.completion.widget.ComputeOverlayLayoutAt(
 		m.width,
 		m.height,
-		lipgloss.Height(header),
-		lipgloss.Height(timelineView),
-		m.textInput.Prompt,
-		m.textInput.Value(),
-		m.textInput.Position(),
+		lipgloss.Height(header)+1+lipgloss.Height(timelineView)+cursorRow,
+		cursorCol,
 		m.completionPopupStyle(),
 	)
 	m.syncCompletionLegacyFromWidget()
@@ -31,15 +30,41 @@ func (m *Model) renderCompletionPopup(layout completionOverlayLayout) string {
 		return ""
 	}
 	m.syncCompletionWidgetFromLegacy()
-	ret := m.completion.widget.RenderPopup(suggest.Styles{
-		Item:     m.styles.CompletionItem,
-		Selected: m.styles.CompletionSelected,
-		Popup:    m.styles.CompletionPopup,
-	}, layout)
+	ret := m.completion.widget.RenderPopup(m.completionStyles(), layout)
 	m.syncCompletionLegacyFromWidget()
 	return ret
 }
 
+// renderCompletionDoc lays out and renders the documentation pane of the selected suggestion
+// next to the rendered popup.
+func (m *Model) renderCompletionDoc(layout completionOverlayLayout, popup string) (completionDocLayout, string, bool) {
+	m.ensureCompletionWidget()
+	if m.completion.widget == nil {
+		return completionDocLayout{}, "", false
+	}
+	m.syncCompletionWidgetFromLegacy()
+	defer m.syncCompletionLegacyFromWidget()
+	styles := m.completionStyles()
+	docLayout, ok := m.completion.widget.ComputeDocumentationLayout(m.width, m.height, layout, popup, styles)
+	if !ok {
+		return completionDocLayout{}, "", false
+	}
+	view := m.completion.widget.RenderDocumentation(styles, docLayout)
+	return docLayout, view, view != ""
+}
+
+func (m *Model) completionStyles() suggest.Styles {
+	return suggest.Styles{
+		Item:          m.styles.CompletionItem,
+		Selected:      m.styles.CompletionSelected,
+		Popup:         m.styles.CompletionPopup,
+		Match:         m.styles.CompletionMatch,
+		Icon:          m.styles.CompletionIcon,
+		Detail:        m.styles.CompletionDetail,
+		Documentation: m.styles.CompletionDoc,
+	}
+}
+
 func (m *Model) completionPopupStyle() lipgloss.Style {
 	m.ensureCompletionWidget()
 	if m.completion.widget == nil {
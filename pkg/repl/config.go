@@ -1,6 +1,12 @@
 package repl
 
-import "time"
+import (
+	"time"
+
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
+	"github.com/go-go-golems/bobatea/pkg/tui/inputhistory"
+)
 
 type CompletionOverlayPlacement string
 
@@ -56,6 +62,14 @@ type AutocompleteConfig struct {
 	// OverlayHorizontalGrow controls horizontal growth direction from anchor.
 	// Supported values: right, left.
 	OverlayHorizontalGrow CompletionOverlayHorizontalGrow
+	// ClientFilter fuzzy-filters the last completion result locally while typing within the
+	// same token, instead of asking the evaluator again after every debounce.
+	ClientFilter bool
+	// OverlayDocWidth is the width of the documentation pane shown beside the popup for the
+	// selected suggestion; a negative value hides it.
+	OverlayDocWidth int
+	// Icons overrides the icons shown for suggestion kinds; nil uses suggest.DefaultIcons.
+	Icons map[autocomplete.SuggestionKind]string
 }
 
 // HelpBarConfig controls contextual help bar request and rendering behavior.
@@ -124,6 +138,98 @@ const (
 	CommandPaletteOverlayPlacementRight  CommandPaletteOverlayPlacement = "right"
 )
 
+type MultilineSubmitPolicy string
+
+const (
+	// MultilineSubmitEnter submits on Enter regardless of the buffer content.
+	MultilineSubmitEnter MultilineSubmitPolicy = "enter"
+	// MultilineSubmitWhenComplete submits on Enter only when the buffer looks complete
+	// (balanced brackets and quotes); otherwise Enter inserts a newline.
+	MultilineSubmitWhenComplete MultilineSubmitPolicy = "when-complete"
+)
+
+// MultilineConfig controls the textarea-based input used when the evaluator supports multiline.
+type MultilineConfig struct {
+	// SubmitPolicy decides whether Enter submits or inserts a newline.
+	// Supported values: enter, when-complete.
+	SubmitPolicy MultilineSubmitPolicy
+	// NewlineKeys always insert a newline.
+	NewlineKeys []string
+	// ToggleKeys switch between single-line and multiline input.
+	ToggleKeys []string
+	// MaxHeight limits the visible input rows; the input scrolls beyond that.
+	MaxHeight int
+	// ContinuationPrompt is shown in front of the second and following lines.
+	ContinuationPrompt string
+	// CompletenessTimeout bounds the InputCompletenessChecker call made when Enter is pressed.
+	CompletenessTimeout time.Duration
+}
+
+// HighlightConfig controls syntax highlighting of the input buffer.
+type HighlightConfig struct {
+	// Enabled turns on the chroma-based default highlighter for evaluators that do not
+	// implement InputHighlighter. The lexer is picked from Evaluator.GetFileExtension().
+	Enabled bool
+	// Style is the chroma style name (default: monokai).
+	Style string
+}
+
+// InlineSuggestConfig controls the inline (ghost text) suggestion shown after the cursor.
+type InlineSuggestConfig struct {
+	// Enabled shows inline suggestions from the evaluator when it implements InlineSuggester,
+	// otherwise from input history.
+	Enabled bool
+	// Debounce delays the provider request after each input change.
+	Debounce time.Duration
+	// RequestTimeout bounds each provider request.
+	RequestTimeout time.Duration
+	// AcceptKeys insert the whole suggestion.
+	AcceptKeys []string
+	// AcceptWordKeys insert the suggestion up to the end of its next word.
+	AcceptWordKeys []string
+}
+
+// TurnPolicy decides what happens when input is submitted while an evaluation is running.
+type TurnPolicy string
+
+const (
+	// TurnPolicyQueue runs submitted turns one after another.
+	TurnPolicyQueue TurnPolicy = "queue"
+	// TurnPolicyReject refuses new input until the running turn finishes.
+	TurnPolicyReject TurnPolicy = "reject"
+	// TurnPolicyConcurrent starts every turn immediately.
+	TurnPolicyConcurrent TurnPolicy = "concurrent"
+)
+
+// TurnsConfig controls how evaluations are scheduled and the turn list overlay.
+type TurnsConfig struct {
+	// Policy is one of queue, reject, concurrent.
+	Policy TurnPolicy
+	// MaxQueued limits pending turns in queue mode; further input is rejected.
+	MaxQueued int
+	// ListKeys toggle the turn list overlay.
+	ListKeys []string
+	// MaxListed limits the turns kept for the turn list (pending and running turns are always kept).
+	MaxListed int
+}
+
+// ChordsConfig controls multi-key bindings such as "ctrl+x ctrl+e" or "leader t" in the key map.
+type ChordsConfig struct {
+	// Leader replaces "leader" in chord keys, e.g. "ctrl+a"; leader chords are inactive when empty.
+	Leader string
+	// Timeout abandons a pending sequence when no further key is pressed.
+	Timeout time.Duration
+}
+
+// ThemeConfig selects the palette the REPL, its overlays and the timeline are styled with.
+type ThemeConfig struct {
+	// Name is a registered theme (see theme.Names) or "auto" to detect one from the
+	// terminal; empty keeps the REPL's own styles.
+	Name string
+	// Files are YAML themes registered before Name is resolved.
+	Files []string
+}
+
 // CommandPaletteConfig controls REPL command palette behavior.
 type CommandPaletteConfig struct {
 	// Enabled toggles command palette integration.
@@ -170,6 +276,7 @@ func DefaultAutocompleteConfig() AutocompleteConfig {
 		OverlayNoBorder:       false,
 		OverlayPlacement:      CompletionOverlayPlacementAuto,
 		OverlayHorizontalGrow: CompletionOverlayHorizontalGrowRight,
+		OverlayDocWidth:       40,
 	}
 }
 
@@ -200,6 +307,54 @@ func DefaultHelpDrawerConfig() HelpDrawerConfig {
 	}
 }
 
+// DefaultMultilineConfig returns default multiline input settings.
+func DefaultMultilineConfig() MultilineConfig {
+	return MultilineConfig{
+		SubmitPolicy:        MultilineSubmitWhenComplete,
+		NewlineKeys:         []string{"alt+enter", "ctrl+j"},
+		ToggleKeys:          []string{"alt+m"},
+		MaxHeight:           8,
+		ContinuationPrompt:  "… ",
+		CompletenessTimeout: 250 * time.Millisecond,
+	}
+}
+
+// DefaultHighlightConfig returns default input highlighting settings.
+func DefaultHighlightConfig() HighlightConfig {
+	return HighlightConfig{
+		Enabled: true,
+		Style:   "monokai",
+	}
+}
+
+// DefaultInlineSuggestConfig returns default inline suggestion settings.
+func DefaultInlineSuggestConfig() InlineSuggestConfig {
+	return InlineSuggestConfig{
+		Enabled:        true,
+		Debounce:       80 * time.Millisecond,
+		RequestTimeout: 300 * time.Millisecond,
+		AcceptKeys:     []string{"right", "end"},
+		AcceptWordKeys: []string{"ctrl+f"},
+	}
+}
+
+// DefaultChordsConfig returns default chord settings.
+func DefaultChordsConfig() ChordsConfig {
+	return ChordsConfig{
+		Timeout: time.Second,
+	}
+}
+
+// DefaultTurnsConfig returns default turn scheduling settings.
+func DefaultTurnsConfig() TurnsConfig {
+	return TurnsConfig{
+		Policy:    TurnPolicyQueue,
+		MaxQueued: 16,
+		ListKeys:  []string{"alt+t"},
+		MaxListed: 50,
+	}
+}
+
 // DefaultCommandPaletteConfig returns default command palette settings.
 func DefaultCommandPaletteConfig() CommandPaletteConfig {
 	return CommandPaletteConfig{
@@ -218,14 +373,21 @@ func DefaultCommandPaletteConfig() CommandPaletteConfig {
 
 // Config holds REPL shell configuration.
 type Config struct {
-	Title                string
-	Prompt               string
-	Placeholder          string
-	Width                int
+	Title       string
+	Prompt      string
+	Placeholder string
+	Width       int
+	// StartMultiline starts in multiline input mode when the evaluator supports multiline.
 	StartMultiline       bool
 	EnableExternalEditor bool
 	EnableHistory        bool
 	MaxHistorySize       int
+	// PersistHistory saves input history to disk so it survives restarts. The file is
+	// HistoryFile, or a per-evaluator file keyed by Evaluator.GetName() when empty.
+	PersistHistory bool
+	HistoryFile    string
+	// HistoryDedup controls how repeated inputs are recorded (default: consecutive).
+	HistoryDedup inputhistory.DedupPolicy
 	// Autocomplete controls completion scheduling, shortcuts, and popup behavior.
 	Autocomplete AutocompleteConfig
 	// HelpBar controls contextual in-line help updates while typing.
@@ -234,6 +396,24 @@ type Config struct {
 	HelpDrawer HelpDrawerConfig
 	// CommandPalette controls command discovery/dispatch overlay behavior.
 	CommandPalette CommandPaletteConfig
+	// Multiline controls submit semantics and sizing of the multiline input.
+	Multiline MultilineConfig
+	// Turns controls what happens to input submitted while an evaluation runs.
+	Turns TurnsConfig
+	// Highlight controls syntax highlighting of the input line.
+	Highlight HighlightConfig
+	// InlineSuggest controls the dimmed suggestion rendered after the cursor.
+	InlineSuggest InlineSuggestConfig
+	// KeyMapFile is a YAML file of key binding overrides, keyed by KeyMap field name
+	// (e.g. "history-search: ctrl+s"). It is applied on top of the bindings derived from
+	// the settings above.
+	KeyMapFile string
+	// KeyBindings are overrides applied after KeyMapFile.
+	KeyBindings mode_keymap.Overrides
+	// Chords controls key sequences bound with KeyMapFile or KeyBindings.
+	Chords ChordsConfig
+	// Theme selects the color theme.
+	Theme ThemeConfig
 }
 
 // DefaultConfig returns a sensible default configuration.
@@ -251,5 +431,10 @@ func DefaultConfig() Config {
 		HelpBar:              DefaultHelpBarConfig(),
 		HelpDrawer:           DefaultHelpDrawerConfig(),
 		CommandPalette:       DefaultCommandPaletteConfig(),
+		Multiline:            DefaultMultilineConfig(),
+		Turns:                DefaultTurnsConfig(),
+		Highlight:            DefaultHighlightConfig(),
+		InlineSuggest:        DefaultInlineSuggestConfig(),
+		Chords:               DefaultChordsConfig(),
 	}
 }
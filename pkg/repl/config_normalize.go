@@ -110,6 +110,9 @@ func normalizeAutocompleteConfig(cfg AutocompleteConfig) AutocompleteConfig {
 		!cfg.OverlayNoBorder &&
 		cfg.OverlayPlacement == "" &&
 		cfg.OverlayHorizontalGrow == "" &&
+		!cfg.ClientFilter &&
+		cfg.OverlayDocWidth == 0 &&
+		cfg.Icons == nil &&
 		!cfg.Enabled {
 		return DefaultAutocompleteConfig()
 	}
@@ -164,6 +167,11 @@ func normalizeAutocompleteConfig(cfg AutocompleteConfig) AutocompleteConfig {
 	if cfg.OverlayHorizontalGrow != "" {
 		merged.OverlayHorizontalGrow = cfg.OverlayHorizontalGrow
 	}
+	if cfg.OverlayDocWidth != 0 {
+		merged.OverlayDocWidth = cfg.OverlayDocWidth
+	}
+	merged.Icons = cfg.Icons
+	merged.ClientFilter = cfg.ClientFilter
 	merged.OverlayPlacement = normalizeOverlayPlacement(merged.OverlayPlacement)
 	merged.OverlayHorizontalGrow = normalizeOverlayHorizontalGrow(merged.OverlayHorizontalGrow)
 	return merged
@@ -261,3 +269,75 @@ func normalizeCommandPaletteOverlayPlacement(v CommandPaletteOverlayPlacement) C
 		return CommandPaletteOverlayPlacementCenter
 	}
 }
+
+func normalizeMultilineConfig(cfg MultilineConfig) MultilineConfig {
+	merged := DefaultMultilineConfig()
+	switch cfg.SubmitPolicy {
+	case MultilineSubmitEnter, MultilineSubmitWhenComplete:
+		merged.SubmitPolicy = cfg.SubmitPolicy
+	}
+	if len(cfg.NewlineKeys) > 0 {
+		merged.NewlineKeys = cfg.NewlineKeys
+	}
+	if len(cfg.ToggleKeys) > 0 {
+		merged.ToggleKeys = cfg.ToggleKeys
+	}
+	if cfg.MaxHeight > 0 {
+		merged.MaxHeight = cfg.MaxHeight
+	}
+	if cfg.ContinuationPrompt != "" {
+		merged.ContinuationPrompt = cfg.ContinuationPrompt
+	}
+	if cfg.CompletenessTimeout > 0 {
+		merged.CompletenessTimeout = cfg.CompletenessTimeout
+	}
+	return merged
+}
+
+func normalizeTurnsConfig(cfg TurnsConfig) TurnsConfig {
+	merged := DefaultTurnsConfig()
+	switch cfg.Policy {
+	case TurnPolicyQueue, TurnPolicyReject, TurnPolicyConcurrent:
+		merged.Policy = cfg.Policy
+	}
+	if cfg.MaxQueued > 0 {
+		merged.MaxQueued = cfg.MaxQueued
+	}
+	if len(cfg.ListKeys) > 0 {
+		merged.ListKeys = cfg.ListKeys
+	}
+	if cfg.MaxListed > 0 {
+		merged.MaxListed = cfg.MaxListed
+	}
+	return merged
+}
+
+func normalizeChordsConfig(cfg ChordsConfig) ChordsConfig {
+	merged := DefaultChordsConfig()
+	merged.Leader = cfg.Leader
+	if cfg.Timeout > 0 {
+		merged.Timeout = cfg.Timeout
+	}
+	return merged
+}
+
+func normalizeInlineSuggestConfig(cfg InlineSuggestConfig) InlineSuggestConfig {
+	if cfg.Debounce == 0 && cfg.RequestTimeout == 0 && len(cfg.AcceptKeys) == 0 && len(cfg.AcceptWordKeys) == 0 && !cfg.Enabled {
+		return DefaultInlineSuggestConfig()
+	}
+	merged := DefaultInlineSuggestConfig()
+	merged.Enabled = cfg.Enabled
+	if cfg.Debounce > 0 {
+		merged.Debounce = cfg.Debounce
+	}
+	if cfg.RequestTimeout > 0 {
+		merged.RequestTimeout = cfg.RequestTimeout
+	}
+	if len(cfg.AcceptKeys) > 0 {
+		merged.AcceptKeys = cfg.AcceptKeys
+	}
+	if len(cfg.AcceptWordKeys) > 0 {
+		merged.AcceptWordKeys = cfg.AcceptWordKeys
+	}
+	return merged
+}
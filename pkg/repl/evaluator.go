@@ -12,13 +12,14 @@ const (
 	EventStderr         EventKind = "repl_stderr"          // props: append|string or text, is_error=true
 	EventLog            EventKind = "repl_log"             // props: level, message, metadata(optional), fields(optional)
 	EventStructuredLog  EventKind = "repl_structured_log"  // props: level, message(optional), data|metadata|fields
-	EventToolCalls      EventKind = "repl_tool_calls"
-	EventProgress       EventKind = "repl_progress"
-	EventPerf           EventKind = "repl_perf"
-	EventTable          EventKind = "repl_table"
-	EventDiff           EventKind = "repl_diff"
-	EventShellCmd       EventKind = "repl_shell_cmd"
-	EventInspector      EventKind = "repl_inspector"
+	EventToolCalls      EventKind = "repl_tool_calls"      // props: calls([]any), summary(optional)
+	EventProgress       EventKind = "repl_progress"        // props: id(optional), label, percent|current+total, unit, eta_ms, done, error
+	EventPerf           EventKind = "repl_perf"            // props: label, total_ms, phases([{name, ms}] or map name->ms)
+	EventTable          EventKind = "repl_table"           // props: columns, rows([][]any or []map), align(optional), title
+	EventDiff           EventKind = "repl_diff"            // props: diff (unified text), title|path
+	EventShellCmd       EventKind = "repl_shell_cmd"       // props: id(optional), command, cwd, output|append, stderr, exit_code, duration_ms
+	EventInspector      EventKind = "repl_inspector"       // props: data|json
+	EventInterrupted    EventKind = "repl_interrupted"     // emitted by the REPL when a turn is cancelled; props: text, elapsed_ms
 )
 
 // Event carries a semantic payload for the UI.
@@ -124,6 +124,23 @@ func (m *Model) hideHelpBar() {
 	}
 }
 
+// showHelpBarNotice shows a REPL-originated message in the help bar until the next provider
+// result or input change replaces it.
+func (m *Model) showHelpBarNotice(text, severity string) {
+	m.ensureHelpBarWidget()
+	if m.helpBar.widget == nil {
+		return
+	}
+	visibilityChanged := m.helpBar.widget.HandleResult(contextbar.ResultMsg{
+		RequestID: m.helpBar.widget.RequestSeq(),
+		Payload:   HelpBarPayload{Show: true, Text: text, Kind: "notice", Severity: severity, Ephemeral: true},
+	})
+	m.syncHelpBarLegacyState()
+	if visibilityChanged {
+		m.applyLayoutAndRefresh()
+	}
+}
+
 func (m *Model) helpBarCmd(req HelpBarRequest) tea.Cmd {
 	m.ensureHelpBarWidget()
 	if m.helpBar.widget == nil {
@@ -117,7 +117,8 @@ func (m *Model) handleDebouncedHelpDrawer(msg helpDrawerDebounceMsg) tea.Cmd {
 
 func (m *Model) handleHelpDrawerShortcuts(k tea.KeyMsg) (bool, tea.Cmd) {
 	m.ensureHelpDrawerWidget()
-	if m.helpDrawer.widget == nil || m.helpDrawer.provider == nil {
+	// without a provider the drawer only shows REPL documents such as the key bindings
+	if m.helpDrawer.widget == nil || (m.helpDrawer.provider == nil && !m.helpDrawer.visible) {
 		return false, nil
 	}
 
@@ -130,7 +131,7 @@ func (m *Model) handleHelpDrawerShortcuts(k tea.KeyMsg) (bool, tea.Cmd) {
 		}
 		m.closeHelpDrawer()
 		return true, nil
-	case m.helpDrawer.visible && key.Matches(k, m.keyMap.HelpDrawerRefresh):
+	case m.helpDrawer.visible && m.helpDrawer.provider != nil && key.Matches(k, m.keyMap.HelpDrawerRefresh):
 		return true, m.requestHelpDrawerNow(HelpDrawerTriggerManualRefresh)
 	case m.helpDrawer.visible && key.Matches(k, m.keyMap.HelpDrawerPin):
 		m.helpDrawer.widget.TogglePin()
@@ -33,6 +33,9 @@ func (m *Model) renderHelpDrawerPanel(layout helpDrawerOverlayLayout) string {
 		FooterRenderer: func(s string) string {
 			return m.styles.HelpText.Render(s)
 		},
+		PanelStyle:    optionalStyle(m.styles.HelpDrawer),
+		TitleStyle:    optionalStyle(m.styles.HelpDrawerTitle),
+		SubtitleStyle: optionalStyle(m.styles.HelpDrawerSubtitle),
 	})
 }
 
@@ -0,0 +1,71 @@
+package repl
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/eventbus"
+	"github.com/go-go-golems/bobatea/pkg/tui/inputhistory"
+	"github.com/stretchr/testify/require"
+)
+
+func newHistorySearchTestModel(t *testing.T, historyFile string) *Model {
+	t.Helper()
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+
+	cfg := DefaultConfig()
+	cfg.Autocomplete.Enabled = false
+	cfg.PersistHistory = true
+	cfg.HistoryFile = historyFile
+	return NewModel(NewExampleEvaluator(), cfg, bus.Publisher)
+}
+
+func typeRunes(m *Model, s string) {
+	for _, r := range s {
+		_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestHistorySearchAcceptsMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, inputhistory.NewFileStore(path).Rewrite([]string{"echo one", "1 + 2", "echo two"}))
+
+	m := newHistorySearchTestModel(t, path)
+	typeRunes(m, "draft")
+
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	require.True(t, m.histSearch.active)
+	typeRunes(m, "echo")
+	require.Len(t, m.histSearch.matches, 2)
+	require.Equal(t, "echo two", m.histSearch.matches[0].Input)
+
+	// ctrl+r again steps to the older match
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, m.histSearch.active)
+	require.Equal(t, "echo one", m.textInput.Value())
+}
+
+func TestHistorySearchCancelRestoresInput(t *testing.T) {
+	m := newHistorySearchTestModel(t, filepath.Join(t.TempDir(), "history.jsonl"))
+	typeRunes(m, "draft")
+
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	typeRunes(m, "zzz")
+	require.Empty(t, m.histSearch.matches)
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, m.histSearch.active)
+	require.Equal(t, "draft", m.textInput.Value())
+}
+
+func TestHistoryPersistsSubmittedInputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	m := newHistorySearchTestModel(t, path)
+	typeRunes(m, "1 + 1")
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	next := newHistorySearchTestModel(t, path)
+	require.Equal(t, []string{"1 + 1"}, next.history.GetAll())
+}
@@ -0,0 +1,17 @@
+package repl
+
+import "context"
+
+// InlineSuggestion is a single continuation of the input, rendered dimmed after the cursor.
+type InlineSuggestion struct {
+	// Text is inserted at the cursor when the suggestion is accepted; empty means no suggestion.
+	Text string
+}
+
+// InlineSuggester provides fish-style inline suggestions. It is asked after each input change,
+// with the same debouncing and stale-response handling as InputCompleter. Evaluators implementing
+// it take precedence over the default, which continues the input with the newest matching
+// history entry.
+type InlineSuggester interface {
+	SuggestInline(ctx context.Context, req CompletionRequest) (InlineSuggestion, error)
+}
@@ -0,0 +1,37 @@
+package repl
+
+import "context"
+
+// InputCompleteness is an evaluator's verdict on whether the input can be submitted.
+type InputCompleteness string
+
+const (
+	// InputComplete means the input is ready to be evaluated.
+	InputComplete InputCompleteness = "complete"
+	// InputIncomplete means the input continues on the next line (open block, unbalanced bracket, ...).
+	InputIncomplete InputCompleteness = "incomplete"
+	// InputInvalid means the input cannot become valid by adding lines; it is submitted so the
+	// evaluator can report the error.
+	InputInvalid InputCompleteness = "invalid"
+)
+
+// InputCompletenessRequest captures the input at the time Enter was pressed.
+type InputCompletenessRequest struct {
+	Input      string
+	CursorByte int
+}
+
+// InputCompletenessResult is returned by InputCompletenessChecker.
+type InputCompletenessResult struct {
+	// Status is empty when the checker has no opinion; the REPL then uses its heuristic
+	Status InputCompleteness
+	// Indent is inserted after the newline when Status is InputIncomplete
+	Indent string
+}
+
+// InputCompletenessChecker is implemented by evaluators that know their language's grammar.
+// The REPL consults it when Enter is pressed and the multiline submit policy is "when-complete";
+// evaluators without it fall back to a bracket/quote balance heuristic.
+type InputCompletenessChecker interface {
+	CheckInputCompleteness(ctx context.Context, req InputCompletenessRequest) (InputCompletenessResult, error)
+}
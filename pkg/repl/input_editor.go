@@ -0,0 +1,382 @@
+package repl
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/textarea"
+	"github.com/mattn/go-runewidth"
+)
+
+// inputEditor is the REPL input line. It wraps bubbles/textinput for single-line input and
+// pkg/textarea for multiline input, behind the small API the model needs. Cursor positions are
+// rune offsets into Value(), with newlines counted as one rune.
+type inputEditor struct {
+	multiline bool
+	line      textinput.Model
+	area      textarea.Model
+
+	prompt    string
+	maxHeight int
+
+	// highlighter styles the value when set
+	highlighter InputHighlighter
+	// ghost is an inline suggestion rendered after the cursor in single-line mode
+	ghost      string
+	ghostStyle lipgloss.Style
+}
+
+func newInputEditor(prompt, placeholder string, width int, cfg MultilineConfig) inputEditor {
+	ti := textinput.New()
+	ti.Prompt = prompt
+	ti.Placeholder = placeholder
+	ti.Width = width
+
+	ta := textarea.New()
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.EndOfBufferCharacter = ' '
+	ta.Placeholder = placeholder
+	ta.FocusedStyle.CursorLine = ta.FocusedStyle.Text
+	ta.KeyMap.InsertNewline = key.NewBinding(key.WithKeys(cfg.NewlineKeys...))
+	promptWidth := max(runewidth.StringWidth(prompt), runewidth.StringWidth(cfg.ContinuationPrompt))
+	continuation := cfg.ContinuationPrompt
+	ta.SetPromptFunc(promptWidth, func(lineIdx int) string {
+		if lineIdx == 0 {
+			return prompt
+		}
+		return continuation
+	})
+	ta.SetWidth(width + promptWidth)
+	ta.SetHeight(1)
+
+	return inputEditor{line: ti, area: ta, prompt: prompt, maxHeight: max(1, cfg.MaxHeight)}
+}
+
+// SetMultiline switches between the single-line and multiline editor, keeping the value.
+// Newlines are flattened to spaces when switching to single-line.
+func (e *inputEditor) SetMultiline(on bool) tea.Cmd {
+	if on == e.multiline {
+		return nil
+	}
+	value, pos := e.Value(), e.Position()
+	focused := e.Focused()
+	e.Blur()
+	e.multiline = on
+	if !on {
+		value = strings.ReplaceAll(value, "\n", " ")
+	}
+	e.SetValue(value)
+	e.SetCursor(pos)
+	if focused {
+		return e.Focus()
+	}
+	return nil
+}
+
+func (e inputEditor) Multiline() bool { return e.multiline }
+
+func (e inputEditor) Value() string {
+	if e.multiline {
+		return e.area.Value()
+	}
+	return e.line.Value()
+}
+
+func (e *inputEditor) SetValue(s string) {
+	if e.multiline {
+		e.area.SetValue(s)
+		e.fitHeight()
+		return
+	}
+	e.line.SetValue(s)
+}
+
+// Position returns the cursor as a rune offset into Value().
+func (e inputEditor) Position() int {
+	if !e.multiline {
+		return e.line.Position()
+	}
+	row, col := e.area.Line(), e.area.Column()
+	pos := 0
+	for i, l := range strings.Split(e.area.Value(), "\n") {
+		if i == row {
+			return pos + col
+		}
+		pos += len([]rune(l)) + 1
+	}
+	return pos
+}
+
+// SetCursor moves the cursor to a rune offset into Value().
+func (e *inputEditor) SetCursor(pos int) {
+	if !e.multiline {
+		e.line.SetCursor(pos)
+		return
+	}
+	lines := strings.Split(e.area.Value(), "\n")
+	for i, l := range lines {
+		n := len([]rune(l))
+		if pos <= n || i == len(lines)-1 {
+			e.area.SetCursorPosition(i, pos)
+			return
+		}
+		pos -= n + 1
+	}
+}
+
+// CursorEnd moves the cursor to the end of the whole value.
+func (e *inputEditor) CursorEnd() {
+	if !e.multiline {
+		e.line.CursorEnd()
+		return
+	}
+	e.area.SetCursorPosition(e.area.LineCount()-1, len([]rune(e.area.Value())))
+}
+
+// CursorRow returns the logical line of the cursor and the number of lines.
+func (e inputEditor) CursorRow() (int, int) {
+	if !e.multiline {
+		return 0, 1
+	}
+	return e.area.Line(), e.area.LineCount()
+}
+
+// InsertNewline inserts a line break at the cursor (multiline only).
+func (e *inputEditor) InsertNewline() {
+	if !e.multiline {
+		return
+	}
+	e.area.InsertRune('\n')
+	e.fitHeight()
+}
+
+// InsertString inserts s at the cursor (multiline only).
+func (e *inputEditor) InsertString(s string) {
+	if !e.multiline || s == "" {
+		return
+	}
+	e.area.InsertString(s)
+	e.fitHeight()
+}
+
+func (e *inputEditor) Reset() {
+	e.line.Reset()
+	e.area.Reset()
+	e.fitHeight()
+}
+
+func (e *inputEditor) Focus() tea.Cmd {
+	if e.multiline {
+		return e.area.Focus()
+	}
+	return e.line.Focus()
+}
+
+func (e *inputEditor) Blur() {
+	e.line.Blur()
+	e.area.Blur()
+}
+
+func (e inputEditor) Focused() bool {
+	if e.multiline {
+		return e.area.Focused()
+	}
+	return e.line.Focused()
+}
+
+func (e inputEditor) Prompt() string { return e.prompt }
+
+func (e *inputEditor) SetWidth(w int) {
+	e.line.Width = w
+	e.area.SetWidth(w + e.area.PromptWidth())
+	e.fitHeight()
+}
+
+// Height returns the number of rows View renders.
+func (e inputEditor) Height() int {
+	if !e.multiline {
+		return 1
+	}
+	return e.area.Height()
+}
+
+// CursorAnchor returns the cursor's cell position relative to the top-left of View, used to
+// anchor popups next to the cursor.
+func (e inputEditor) CursorAnchor() (int, int) {
+	if !e.multiline {
+		runes := []rune(e.line.Value())
+		pos := clampInt(e.line.Position(), 0, len(runes))
+		return 0, runewidth.StringWidth(e.prompt + string(runes[:pos]))
+	}
+	return e.area.CursorVisualRow(), e.area.PromptWidth() + e.area.LineInfo().CharOffset
+}
+
+func (e inputEditor) Update(msg tea.Msg) (inputEditor, tea.Cmd) {
+	var cmd tea.Cmd
+	if e.multiline {
+		e.area, cmd = e.area.Update(msg)
+		e.fitHeight()
+		return e, cmd
+	}
+	e.line, cmd = e.line.Update(msg)
+	return e, cmd
+}
+
+func (e inputEditor) View() string {
+	if e.multiline {
+		if e.highlighter != nil {
+			e.area.SetHighlighter(e.lineHighlighter())
+		}
+		return e.area.View()
+	}
+	if v, ok := e.highlightedLineView(); ok {
+		return v
+	}
+	return e.line.View()
+}
+
+// lineHighlighter splits the highlighter's spans over the value into per-line textarea highlights.
+func (e inputEditor) lineHighlighter() func(row int, line []rune) []textarea.Highlight {
+	value := e.area.Value()
+	spans := e.highlighter.HighlightInput(value)
+	starts := []int{0}
+	for i, r := range []rune(value) {
+		if r == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return func(row int, line []rune) []textarea.Highlight {
+		if row >= len(starts) {
+			return nil
+		}
+		start, end := starts[row], starts[row]+len(line)
+		var ret []textarea.Highlight
+		for _, sp := range spans {
+			if sp.End <= start || sp.Start >= end {
+				continue
+			}
+			ret = append(ret, textarea.Highlight{Start: max(sp.Start, start) - start, End: min(sp.End, end) - start, Style: sp.Style})
+		}
+		return ret
+	}
+}
+
+// highlightedLineView renders the single-line input with highlighting and the inline suggestion.
+// It mirrors textinput's view for values that fit the width and defers to it (unhighlighted) for
+// placeholders and horizontally scrolled values.
+func (e inputEditor) highlightedLineView() (string, bool) {
+	value := []rune(e.line.Value())
+	valWidth := runewidth.StringWidth(string(value))
+	if (e.highlighter == nil && e.ghost == "") || len(value) == 0 || (e.line.Width > 0 && valWidth >= e.line.Width) {
+		return "", false
+	}
+	var spans []HighlightSpan
+	if e.highlighter != nil {
+		spans = e.highlighter.HighlightInput(string(value))
+	}
+	pos := clampInt(e.line.Position(), 0, len(value))
+	text := e.line.TextStyle.Inline(true)
+	ghost := ""
+	if pos == len(value) {
+		ghost = e.ghost
+		if e.line.Width > 0 {
+			ghost = runewidth.Truncate(ghost, e.line.Width-valWidth, "")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(e.line.PromptStyle.Render(e.line.Prompt))
+	b.WriteString(renderHighlighted(text, value[:pos], 0, spans))
+	c := e.line.Cursor
+	switch {
+	case pos < len(value):
+		c.SetChar(string(value[pos]))
+		b.WriteString(c.View())
+		b.WriteString(renderHighlighted(text, value[pos+1:], pos+1, spans))
+	case ghost != "":
+		// the cursor sits on the first rune of the suggestion
+		first, size := utf8.DecodeRuneInString(ghost)
+		c.SetChar(string(first))
+		b.WriteString(c.View())
+		b.WriteString(e.ghostStyle.Inline(true).Render(ghost[size:]))
+	default:
+		c.SetChar(" ")
+		b.WriteString(c.View())
+	}
+	if e.line.Width > 0 {
+		padding := max(0, e.line.Width-valWidth-runewidth.StringWidth(ghost))
+		if pos < len(value) || ghost != "" {
+			padding++
+		}
+		b.WriteString(text.Render(strings.Repeat(" ", padding)))
+	}
+	return b.String(), true
+}
+
+// renderHighlighted renders runes found at rune offset off of the value, styling the parts
+// covered by spans on top of base.
+func renderHighlighted(base lipgloss.Style, runes []rune, off int, spans []HighlightSpan) string {
+	var b strings.Builder
+	i := 0
+	for i < len(runes) {
+		st, end := base, len(runes)
+		for _, sp := range spans {
+			switch {
+			case off+i >= sp.Start && off+i < sp.End:
+				st = sp.Style.Inherit(base)
+				end = min(end, sp.End-off)
+			case sp.Start > off+i:
+				end = min(end, sp.Start-off)
+			}
+		}
+		b.WriteString(st.Render(string(runes[i:end])))
+		i = end
+	}
+	return b.String()
+}
+
+// fitHeight grows the textarea with its content, up to maxHeight rows.
+func (e *inputEditor) fitHeight() {
+	e.area.SetHeight(clampInt(e.area.VisualLineCount(), 1, e.maxHeight))
+}
+
+// inputLooksBalanced is the language-agnostic completeness heuristic: brackets and quotes are
+// closed and the input does not end with a line-continuation backslash.
+func inputLooksBalanced(s string) bool {
+	var stack []rune
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch r {
+		case '"', '\'', '`':
+			quote = r
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return quote == 0 && len(stack) == 0 && !escaped
+}
@@ -0,0 +1,183 @@
+package repl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/go-go-golems/bobatea/pkg/eventbus"
+	"github.com/stretchr/testify/require"
+)
+
+func newMultilineTestModel(t *testing.T, policy MultilineSubmitPolicy) *Model {
+	t.Helper()
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+
+	cfg := DefaultConfig()
+	cfg.Autocomplete.Enabled = false
+	cfg.StartMultiline = true
+	cfg.Multiline.SubmitPolicy = policy
+	m := NewModel(NewExampleEvaluator(), cfg, bus.Publisher)
+	_, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	return m
+}
+
+func TestInputEditorCursorRoundTrip(t *testing.T) {
+	e := newInputEditor("> ", "", 40, DefaultMultilineConfig())
+	e.SetMultiline(true)
+	e.SetValue("ab\ncdé\nf")
+
+	for pos := 0; pos <= len([]rune(e.Value())); pos++ {
+		e.SetCursor(pos)
+		require.Equal(t, pos, e.Position(), "pos %d", pos)
+	}
+
+	e.SetCursor(4)
+	row, count := e.CursorRow()
+	require.Equal(t, 1, row)
+	require.Equal(t, 3, count)
+	require.Equal(t, 3, e.Height())
+
+	// switching back to single-line keeps the text on one line
+	e.SetMultiline(false)
+	require.Equal(t, "ab cdé f", e.Value())
+	require.Equal(t, 4, e.Position())
+}
+
+func TestInputLooksBalanced(t *testing.T) {
+	require.True(t, inputLooksBalanced("f(1, [2])"))
+	require.True(t, inputLooksBalanced(`"(" + ')'`))
+	require.False(t, inputLooksBalanced("function f() {"))
+	require.False(t, inputLooksBalanced(`"unterminated`))
+	require.False(t, inputLooksBalanced("a + \\"))
+}
+
+func TestMultilineEnterContinuesIncompleteInput(t *testing.T) {
+	m := newMultilineTestModel(t, MultilineSubmitWhenComplete)
+	typeRunes(m, "if (x) {")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Nil(t, cmd)
+	require.Equal(t, "if (x) {\n", m.textInput.Value())
+	require.Equal(t, 2, m.textInput.Height())
+
+	typeRunes(m, "}")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	require.Equal(t, "", m.textInput.Value())
+	require.Equal(t, 1, m.textInput.Height())
+}
+
+func TestMultilineNewlineKeyAndHistoryEdges(t *testing.T) {
+	m := newMultilineTestModel(t, MultilineSubmitEnter)
+	typeRunes(m, "one")
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	typeRunes(m, "two")
+	require.Equal(t, "one\ntwo", m.textInput.Value())
+
+	// up on the second line moves the cursor instead of recalling history
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	require.Equal(t, "one\ntwo", m.textInput.Value())
+	row, _ := m.textInput.CursorRow()
+	require.Equal(t, 0, row)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	require.Equal(t, "", m.textInput.Value())
+}
+
+type fakeCompletenessEvaluator struct {
+	*ExampleEvaluator
+	requests []InputCompletenessRequest
+}
+
+func (f *fakeCompletenessEvaluator) CheckInputCompleteness(_ context.Context, req InputCompletenessRequest) (InputCompletenessResult, error) {
+	f.requests = append(f.requests, req)
+	switch {
+	case strings.HasSuffix(req.Input, ":"):
+		return InputCompletenessResult{Status: InputIncomplete, Indent: "    "}, nil
+	case strings.HasPrefix(req.Input, "("):
+		return InputCompletenessResult{Status: InputInvalid}, nil
+	}
+	return InputCompletenessResult{Status: InputComplete}, nil
+}
+
+func TestSubmitConsultsInputCompletenessChecker(t *testing.T) {
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+	cfg := DefaultConfig()
+	cfg.Autocomplete.Enabled = false
+	evaluator := &fakeCompletenessEvaluator{ExampleEvaluator: NewExampleEvaluator()}
+	m := NewModel(evaluator, cfg, bus.Publisher)
+
+	// the check runs in a command; Enter leaves the input alone until its result arrives
+	typeRunes(m, "if x:")
+	_, check := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, check)
+	require.Equal(t, "if x:", m.textInput.Value())
+	require.Empty(t, evaluator.requests)
+
+	// an open block switches a single-line input to multiline and indents the next line
+	_, _ = m.Update(check())
+	require.True(t, m.textInput.Multiline())
+	require.Equal(t, "if x:\n    ", m.textInput.Value())
+	require.Len(t, evaluator.requests, 1)
+	require.Equal(t, 5, evaluator.requests[0].CursorByte)
+
+	// the checker overrides the heuristic: invalid input is submitted despite the open paren
+	m.textInput.Reset()
+	typeRunes(m, "(1")
+	_, check = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd := m.Update(check())
+	require.NotNil(t, cmd)
+	require.Equal(t, "", m.textInput.Value())
+
+	// a result for input that was edited since is dropped
+	typeRunes(m, "x:")
+	_, check = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	typeRunes(m, "y")
+	_, cmd = m.Update(check())
+	require.Nil(t, cmd)
+	require.Equal(t, "x:y", m.textInput.Value())
+}
+
+type fixedHighlighter []HighlightSpan
+
+func (f fixedHighlighter) HighlightInput(string) []HighlightSpan { return f }
+
+func TestChromaHighlighterMatchesExtension(t *testing.T) {
+	require.Nil(t, NewChromaHighlighter(".txt", "monokai"))
+
+	h := NewChromaHighlighter(".js", "monokai")
+	require.NotNil(t, h)
+	spans := h.HighlightInput("const x = 1")
+	require.NotEmpty(t, spans)
+	require.Equal(t, 0, spans[0].Start)
+	require.Equal(t, 5, spans[0].End, "keyword span")
+}
+
+func TestInputEditorSplitsHighlightsPerLine(t *testing.T) {
+	e := newInputEditor("> ", "", 40, DefaultMultilineConfig())
+	e.highlighter = fixedHighlighter{{Span: autocomplete.Span{Start: 1, End: 4}, Style: lipgloss.NewStyle().Bold(true)}}
+	e.SetMultiline(true)
+	e.SetValue("ab\ncd")
+
+	hl := e.lineHighlighter()
+	first := hl(0, []rune("ab"))
+	require.Len(t, first, 1)
+	require.Equal(t, [2]int{1, 2}, [2]int{first[0].Start, first[0].End})
+	second := hl(1, []rune("cd"))
+	require.Len(t, second, 1)
+	require.Equal(t, [2]int{0, 1}, [2]int{second[0].Start, second[0].End})
+	require.Contains(t, e.View(), "cd")
+
+	// the single-line view keeps every rune of the value
+	e.SetMultiline(false)
+	e.SetValue("abc")
+	view, ok := e.highlightedLineView()
+	require.True(t, ok)
+	require.Contains(t, view, "abc")
+}
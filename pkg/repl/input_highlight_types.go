@@ -0,0 +1,19 @@
+package repl
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+)
+
+// HighlightSpan styles the runes [Start, End) of the input buffer. Newlines count as one rune.
+type HighlightSpan struct {
+	autocomplete.Span
+	Style lipgloss.Style
+}
+
+// InputHighlighter styles the input buffer as it is typed. It is called on every render, so
+// implementations should be fast and cache by input. Evaluators implementing it take precedence
+// over the chroma-based default configured by Config.Highlight.
+type InputHighlighter interface {
+	HighlightInput(input string) []HighlightSpan
+}
@@ -16,6 +16,12 @@ type KeyMap struct {
 	Submit      key.Binding `keymap-mode:"input"`
 	HistoryPrev key.Binding `keymap-mode:"input"`
 	HistoryNext key.Binding `keymap-mode:"input"`
+	// HistorySearch opens reverse incremental search; pressed again it steps to older matches.
+	HistorySearch key.Binding `keymap-mode:"input"`
+	// InsertNewline and ToggleMultiline are only enabled for evaluators that support multiline.
+	InsertNewline   key.Binding `keymap-mode:"input"`
+	ToggleMultiline key.Binding `keymap-mode:"input"`
+	TurnList        key.Binding `keymap-mode:"input"`
 
 	CompletionTrigger   key.Binding `keymap-mode:"input"`
 	CompletionAccept    key.Binding `keymap-mode:"input"`
@@ -30,12 +36,25 @@ type KeyMap struct {
 	HelpDrawerPin       key.Binding `keymap-mode:"input"`
 	CommandPaletteOpen  key.Binding `keymap-mode:"input"`
 	CommandPaletteClose key.Binding `keymap-mode:"input"`
+	// SnippetNext and SnippetPrev jump between the placeholders of an accepted snippet completion.
+	SnippetNext key.Binding `keymap-mode:"input"`
+	SnippetPrev key.Binding `keymap-mode:"input"`
+	// InlineAccept and InlineAcceptWord take the whole inline suggestion or its next word; they
+	// only act while a suggestion is shown, so the keys keep moving the cursor otherwise.
+	InlineAccept     key.Binding `keymap-mode:"input"`
+	InlineAcceptWord key.Binding `keymap-mode:"input"`
 
 	TimelinePrev      key.Binding `keymap-mode:"timeline"`
 	TimelineNext      key.Binding `keymap-mode:"timeline"`
 	TimelineEnterExit key.Binding `keymap-mode:"timeline"`
 	CopyCode          key.Binding `keymap-mode:"timeline"`
 	CopyText          key.Binding `keymap-mode:"timeline"`
+	// TimelineSearch opens the search bar; TimelineSearchNext, TimelineSearchPrev and
+	// TimelineSearchClear only act while a search is active.
+	TimelineSearch      key.Binding `keymap-mode:"timeline"`
+	TimelineSearchNext  key.Binding `keymap-mode:"timeline"`
+	TimelineSearchPrev  key.Binding `keymap-mode:"timeline"`
+	TimelineSearchClear key.Binding `keymap-mode:"timeline"`
 }
 
 // NewKeyMap returns REPL key bindings derived from config.
@@ -53,9 +72,13 @@ func NewKeyMap(
 		),
 		ToggleFocus: binding([]string{focusToggleKey}, "toggle focus"),
 
-		Submit:      binding([]string{"enter"}, "submit"),
-		HistoryPrev: binding([]string{"up"}, "history prev"),
-		HistoryNext: binding([]string{"down"}, "history next"),
+		Submit:          binding([]string{"enter"}, "submit"),
+		HistoryPrev:     binding([]string{"up"}, "history prev"),
+		HistoryNext:     binding([]string{"down"}, "history next"),
+		HistorySearch:   binding([]string{"ctrl+r"}, "search history"),
+		InsertNewline:   binding(DefaultMultilineConfig().NewlineKeys, "newline"),
+		ToggleMultiline: binding(DefaultMultilineConfig().ToggleKeys, "toggle multiline"),
+		TurnList:        binding(DefaultTurnsConfig().ListKeys, "turns"),
 
 		CompletionTrigger:   binding(autocompleteCfg.TriggerKeys, "trigger completion"),
 		CompletionAccept:    binding(autocompleteCfg.AcceptKeys, "accept completion"),
@@ -70,12 +93,21 @@ func NewKeyMap(
 		HelpDrawerPin:       binding(helpDrawerCfg.PinShortcuts, "pin drawer"),
 		CommandPaletteOpen:  binding(commandPaletteCfg.OpenKeys, "open palette"),
 		CommandPaletteClose: binding(commandPaletteCfg.CloseKeys, "close palette"),
+		SnippetNext:         binding([]string{"tab"}, "next placeholder"),
+		SnippetPrev:         binding([]string{"shift+tab"}, "prev placeholder"),
+		InlineAccept:        binding(DefaultInlineSuggestConfig().AcceptKeys, "accept suggestion"),
+		InlineAcceptWord:    binding(DefaultInlineSuggestConfig().AcceptWordKeys, "accept word"),
 
 		TimelinePrev:      binding([]string{"up"}, "select prev"),
 		TimelineNext:      binding([]string{"down"}, "select next"),
 		TimelineEnterExit: binding([]string{"enter"}, "enter/exit item"),
 		CopyCode:          binding([]string{"c"}, "copy code"),
 		CopyText:          binding([]string{"y"}, "copy text"),
+
+		TimelineSearch:      binding([]string{"/"}, "search timeline"),
+		TimelineSearchNext:  binding([]string{"n"}, "next match"),
+		TimelineSearchPrev:  binding([]string{"N"}, "prev match"),
+		TimelineSearchClear: binding([]string{"esc"}, "clear search"),
 	}
 
 	if len(autocompleteCfg.TriggerKeys) == 0 {
@@ -156,6 +188,10 @@ func (k KeyMap) fullHelpBindings() []key.Binding {
 		k.Submit,
 		k.HistoryPrev,
 		k.HistoryNext,
+		k.HistorySearch,
+		k.InsertNewline,
+		k.ToggleMultiline,
+		k.TurnList,
 		k.CompletionTrigger,
 		k.CompletionAccept,
 		k.CompletionCancel,
@@ -163,6 +199,10 @@ func (k KeyMap) fullHelpBindings() []key.Binding {
 		k.CompletionNext,
 		k.CompletionPageUp,
 		k.CompletionPageDown,
+		k.SnippetNext,
+		k.SnippetPrev,
+		k.InlineAccept,
+		k.InlineAcceptWord,
 		k.HelpDrawerToggle,
 		k.HelpDrawerClose,
 		k.HelpDrawerRefresh,
@@ -174,5 +214,9 @@ func (k KeyMap) fullHelpBindings() []key.Binding {
 		k.TimelineEnterExit,
 		k.CopyCode,
 		k.CopyText,
+		k.TimelineSearch,
+		k.TimelineSearchNext,
+		k.TimelineSearchPrev,
+		k.TimelineSearchClear,
 	}
 }
@@ -9,10 +9,12 @@ import (
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/go-go-golems/bobatea/pkg/commandpalette"
+	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
 	renderers "github.com/go-go-golems/bobatea/pkg/timeline/renderers"
 	"github.com/rs/zerolog/log"
@@ -25,8 +27,12 @@ type Model struct {
 	styles    Styles
 
 	// input & history
-	history   *History
-	textInput textinput.Model
+	history      *History
+	textInput    inputEditor
+	multiline    MultilineConfig
+	completeness InputCompletenessChecker
+	// completenessSeq tags the pending completeness check, see inputCompletenessMsg
+	completenessSeq int
 
 	// layout
 	width, height  int
@@ -46,14 +52,31 @@ type Model struct {
 	appCtx  context.Context
 	appStop context.CancelFunc
 
+	// submitted turns, oldest first, and the header spinner shown while one runs
+	turns         []*replTurn
+	turnsCfg      TurnsConfig
+	turnList      turnListModel
+	spinner       spinner.Model
+	spinnerActive bool
+
 	// refresh scheduling
 	refreshPending   bool
 	refreshScheduled bool
 
 	completion completionModel
+	inline     inlineSuggestModel
 	helpBar    helpBarModel
 	helpDrawer helpDrawerModel
+
+	// keyOverrides and keyConflicts come from Config.KeyMapFile and Config.KeyBindings
+	keyOverrides mode_keymap.Overrides
+	keyConflicts []mode_keymap.Conflict
+	chords       mode_keymap.Chords
+	// replayingChord is set while a completed chord is dispatched as a key press
+	replayingChord bool
+
 	palette    commandPaletteModel
+	histSearch historySearchModel
 }
 
 // NewModel constructs a new REPL shell with timeline transcript.
@@ -61,11 +84,23 @@ func NewModel(evaluator Evaluator, config Config, pub message.Publisher) *Model
 	if config.Prompt == "" {
 		config.Prompt = evaluator.GetPrompt()
 	}
-	ti := textinput.New()
-	ti.Prompt = config.Prompt
-	ti.Placeholder = config.Placeholder
+	multilineCfg := normalizeMultilineConfig(config.Multiline)
+	ti := newInputEditor(config.Prompt, config.Placeholder, max(10, config.Width-10), multilineCfg)
+	if config.StartMultiline && evaluator.SupportsMultiline() {
+		ti.SetMultiline(true)
+	}
+	if h, ok := evaluator.(InputHighlighter); ok {
+		ti.highlighter = h
+	} else if config.Highlight.Enabled {
+		style := config.Highlight.Style
+		if style == "" {
+			style = DefaultHighlightConfig().Style
+		}
+		if h := NewChromaHighlighter(evaluator.GetFileExtension(), style); h != nil {
+			ti.highlighter = h
+		}
+	}
 	ti.Focus()
-	ti.Width = max(10, config.Width-10)
 
 	reg := timeline.NewRegistry()
 	// Register base widgets
@@ -74,6 +109,12 @@ func NewModel(evaluator Evaluator, config Config, pub message.Publisher) *Model
 	reg.RegisterModelFactory(renderers.StructuredDataFactory{})
 	reg.RegisterModelFactory(renderers.LogEventFactory{})
 	reg.RegisterModelFactory(renderers.StructuredLogEventFactory{})
+	reg.RegisterModelFactory(renderers.TableFactory{})
+	reg.RegisterModelFactory(renderers.DiffFactory{})
+	reg.RegisterModelFactory(renderers.ProgressFactory{})
+	reg.RegisterModelFactory(renderers.PerfFactory{})
+	reg.RegisterModelFactory(renderers.ShellCmdFactory{})
+	reg.RegisterModelFactory(renderers.ToolCallsPanelFactory{})
 
 	sh := timeline.NewShell(reg)
 
@@ -82,6 +123,7 @@ func NewModel(evaluator Evaluator, config Config, pub message.Publisher) *Model
 		completer = c
 	}
 	var helpBarProvider HelpBarProvider
+	completeness, _ := evaluator.(InputCompletenessChecker)
 	if p, ok := evaluator.(HelpBarProvider); ok {
 		helpBarProvider = p
 	}
@@ -113,18 +155,21 @@ func NewModel(evaluator Evaluator, config Config, pub message.Publisher) *Model
 	}
 
 	ret := &Model{
-		evaluator: evaluator,
-		config:    config,
-		styles:    DefaultStyles(),
-		history:   NewHistory(config.MaxHistorySize),
-		textInput: ti,
-		width:     config.Width,
-		reg:       reg,
-		sh:        sh,
-		focus:     "input",
-		help:      help.New(),
-		keyMap:    NewKeyMap(autocompleteCfg, helpDrawerCfg, commandPaletteCfg, focusToggleKey),
-		pub:       pub,
+		evaluator:    evaluator,
+		config:       config,
+		styles:       DefaultStyles(),
+		history:      NewHistory(config.MaxHistorySize),
+		textInput:    ti,
+		multiline:    multilineCfg,
+		turnsCfg:     normalizeTurnsConfig(config.Turns),
+		completeness: completeness,
+		width:        config.Width,
+		reg:          reg,
+		sh:           sh,
+		focus:        "input",
+		help:         help.New(),
+		keyMap:       NewKeyMap(autocompleteCfg, helpDrawerCfg, commandPaletteCfg, focusToggleKey),
+		pub:          pub,
 		completion: completionModel{
 			provider:   completer,
 			debounce:   autocompleteCfg.Debounce,
@@ -140,6 +185,9 @@ func NewModel(evaluator Evaluator, config Config, pub message.Publisher) *Model
 			noBorder:   autocompleteCfg.OverlayNoBorder,
 			placement:  autocompleteCfg.OverlayPlacement,
 			horizontal: autocompleteCfg.OverlayHorizontalGrow,
+			docWidth:   autocompleteCfg.OverlayDocWidth,
+			icons:      autocompleteCfg.Icons,
+			filter:     autocompleteCfg.ClientFilter,
 		},
 		helpBar: newHelpBarModel(helpBarProvider, helpBarCfg),
 		helpDrawer: helpDrawerModel{
@@ -173,7 +221,19 @@ func NewModel(evaluator Evaluator, config Config, pub message.Publisher) *Model
 		ret.keyMap.HelpDrawerClose.SetEnabled(false)
 		ret.keyMap.HelpDrawerRefresh.SetEnabled(false)
 	}
+	ret.history.SetDedupPolicy(config.HistoryDedup)
+	if config.EnableHistory && config.PersistHistory {
+		ret.attachHistoryFile()
+	}
+	ret.keyMap.InsertNewline = binding(multilineCfg.NewlineKeys, "newline")
+	ret.keyMap.ToggleMultiline = binding(multilineCfg.ToggleKeys, "toggle multiline")
+	ret.keyMap.TurnList = binding(ret.turnsCfg.ListKeys, "turns")
+	ret.setupInlineSuggest(normalizeInlineSuggestConfig(config.InlineSuggest))
+	ret.applyKeyMapOverrides(config.KeyMapFile, config.KeyBindings)
+	chordsCfg := normalizeChordsConfig(config.Chords)
+	ret.chords = mode_keymap.NewChords(mode_keymap.WithLeader(chordsCfg.Leader), mode_keymap.WithChordTimeout(chordsCfg.Timeout))
 	ret.help.Width = max(0, ret.width)
+	ret.applyThemeConfig(config.Theme)
 	ret.updateKeyBindings()
 	return ret
 }
@@ -208,10 +268,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case tea.KeyMsg:
+		if handled, cmd := m.handleChordKey(v); handled {
+			return m, cmd
+		}
 		switch {
 		case key.Matches(v, m.keyMap.Quit):
-			m.cancelAppContext()
-			return m, tea.Quit
+			return m, m.handleQuitKey()
 		case key.Matches(v, m.keyMap.ToggleHelp):
 			m.help.ShowAll = !m.help.ShowAll
 			m.applyLayoutAndRefresh()
@@ -225,6 +287,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateTimeline(v)
 		}
 
+	case mode_keymap.ChordTimeoutMsg:
+		m.chords, _, _ = m.chords.Update(&m.keyMap, v)
+		return m, nil
+	case mode_keymap.ChordMsg:
+		return m.dispatchChord(v)
+
+	case theme.ChangedMsg:
+		m.SetTheme(v.Theme)
+		return m, nil
+
+	case commandpalette.PickerMsg:
+		var cmd tea.Cmd
+		m.palette.ui, cmd = m.palette.ui.Update(v)
+		return m, cmd
+
 	case timeline.UIEntityCreated:
 		m.ctrl().OnCreated(v)
 		m.refreshPending = true
@@ -253,6 +330,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handleDebouncedCompletion(v)
 	case completionResultMsg:
 		return m, m.handleCompletionResult(v)
+	case inlineDebounceMsg:
+		return m, m.handleDebouncedInline(v)
+	case inlineResultMsg:
+		return m, m.handleInlineResult(v)
 	case helpBarDebounceMsg:
 		return m, m.handleDebouncedHelpBar(v)
 	case helpBarResultMsg:
@@ -262,6 +343,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case helpDrawerResultMsg:
 		return m, m.handleHelpDrawerResult(v)
 
+	case inputCompletenessMsg:
+		return m, m.handleInputCompleteness(v)
+
+	case evaluationFinishedMsg:
+		return m, m.handleEvaluationFinished(v)
+	case spinner.TickMsg:
+		return m, m.handleSpinnerTick(v)
+	case remoteEvaluateMsg:
+		return m, m.submit(v.code)
+
+	case remoteInsertInputMsg:
+		m.textInput.SetValue(v.text)
+		m.textInput.CursorEnd()
+		return m, nil
+
 	case cursor.BlinkMsg:
 		return m, nil
 	default:
@@ -282,11 +378,19 @@ func (m *Model) View() string {
 	}
 
 	header := m.styles.Title.Render(" " + title + " ")
+	if indicator := m.runningIndicator(); indicator != "" {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", indicator)
+	}
 	timelineView := m.sh.View()
 
-	inputView := m.textInput.View()
+	input := m.textInput
+	input.ghost, input.ghostStyle = m.inlineGhost(), m.inlineGhostStyle()
+	inputView := input.View()
 	if m.focus == "timeline" {
 		inputView = m.styles.HelpText.Render(inputView)
+		if m.sh.SearchActive() {
+			inputView = m.sh.SearchView()
+		}
 	}
 
 	helpView := m.renderHelp()
@@ -308,6 +412,8 @@ func (m *Model) View() string {
 
 	completionLayout, completionOK := m.computeCompletionOverlayLayout(header, timelineView)
 	completionPopup := ""
+	var docLayout completionDocLayout
+	docView, docOK := "", false
 	if completionOK {
 		completionPopup = m.renderCompletionPopup(completionLayout)
 		if completionPopup == "" {
@@ -316,6 +422,7 @@ func (m *Model) View() string {
 		} else {
 			m.completion.visibleRows = completionLayout.VisibleRows
 			m.ensureCompletionSelectionVisible()
+			docLayout, docView, docOK = m.renderCompletionDoc(completionLayout, completionPopup)
 		}
 	} else {
 		m.completion.visibleRows = 0
@@ -331,8 +438,11 @@ func (m *Model) View() string {
 	}
 
 	paletteLayout, paletteOK := m.computeCommandPaletteOverlayLayout()
+	searchLayout, searchOK := m.computeHistorySearchOverlayLayout(header, timelineView)
+	turnsLayout, turnsOK := m.computeTurnListOverlayLayout(header, timelineView)
+	whichKeyLayout, whichKeyOK := m.computeWhichKeyOverlayLayout(header, timelineView)
 
-	if !completionOK && !drawerOK && !paletteOK {
+	if !completionOK && !drawerOK && !paletteOK && !searchOK && !turnsOK && !whichKeyOK {
 		return base
 	}
 
@@ -348,6 +458,26 @@ func (m *Model) View() string {
 		layers = append(layers,
 			lipglossv2.NewLayer(completionPopup).X(completionLayout.PopupX).Y(completionLayout.PopupY).Z(20).ID("completion-overlay"),
 		)
+		if docOK {
+			layers = append(layers,
+				lipglossv2.NewLayer(docView).X(docLayout.X).Y(docLayout.Y).Z(21).ID("completion-doc-overlay"),
+			)
+		}
+	}
+	if turnsOK {
+		layers = append(layers,
+			lipglossv2.NewLayer(turnsLayout.View).X(turnsLayout.PanelX).Y(turnsLayout.PanelY).Z(22).ID("turn-list-overlay"),
+		)
+	}
+	if whichKeyOK {
+		layers = append(layers,
+			lipglossv2.NewLayer(whichKeyLayout.View).X(whichKeyLayout.PanelX).Y(whichKeyLayout.PanelY).Z(23).ID("which-key-overlay"),
+		)
+	}
+	if searchOK {
+		layers = append(layers,
+			lipglossv2.NewLayer(searchLayout.View).X(searchLayout.PanelX).Y(searchLayout.PanelY).Z(25).ID("history-search-overlay"),
+		)
 	}
 	if paletteOK {
 		layers = append(layers,
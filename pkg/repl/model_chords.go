@@ -0,0 +1,50 @@
+package repl
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
+)
+
+// handleChordKey feeds k to the chord recognizer. Chords are off while the palette or history
+// search take text input, and while a completed chord is replayed.
+func (m *Model) handleChordKey(k tea.KeyMsg) (bool, tea.Cmd) {
+	if m.replayingChord || m.palette.ui.IsVisible() || m.histSearch.active || m.sh.SearchInputActive() {
+		if m.chords.Pending() {
+			m.chords = m.chords.Reset()
+		}
+		return false, nil
+	}
+	var cmd tea.Cmd
+	var handled bool
+	m.chords, cmd, handled = m.chords.Update(&m.keyMap, k)
+	return handled, cmd
+}
+
+// dispatchChord runs the binding of a completed chord. The REPL's handlers match bindings with
+// key.Matches, so the chord is replayed as a key press whose String() is the chord's key; a
+// replayed key that reaches the input or the timeline is dropped instead of being typed.
+func (m *Model) dispatchChord(msg mode_keymap.ChordMsg) (tea.Model, tea.Cmd) {
+	m.replayingChord = true
+	defer func() { m.replayingChord = false }()
+	return m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(msg.Keys)})
+}
+
+type whichKeyOverlayLayout struct {
+	PanelX int
+	PanelY int
+	View   string
+}
+
+// computeWhichKeyOverlayLayout places the pending chord's continuations at the right edge, above
+// the input line.
+func (m *Model) computeWhichKeyOverlayLayout(header, timelineView string) (whichKeyOverlayLayout, bool) {
+	if !m.chords.Pending() || m.width <= 0 || m.height <= 0 {
+		return whichKeyOverlayLayout{}, false
+	}
+	view := m.chords.View(&m.keyMap)
+	inputY := lipgloss.Height(header) + 1 + lipgloss.Height(timelineView)
+	panelY := clampInt(inputY-lipgloss.Height(view), 0, max(0, m.height-lipgloss.Height(view)))
+	panelX := max(0, m.width-lipgloss.Width(view))
+	return whichKeyOverlayLayout{PanelX: panelX, PanelY: panelY, View: view}, true
+}
@@ -0,0 +1,168 @@
+package repl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/tui/inputhistory"
+	"github.com/rs/zerolog/log"
+)
+
+const historySearchMaxVisible = 8
+
+// historySearchModel is the state of the ctrl+r reverse incremental search overlay.
+type historySearchModel struct {
+	active   bool
+	query    string
+	matches  []inputhistory.SearchMatch
+	selected int
+	// original input restored when the search is cancelled
+	original       string
+	originalCursor int
+}
+
+func (m *Model) attachHistoryFile() {
+	path := m.config.HistoryFile
+	if path == "" {
+		p, err := inputhistory.DefaultFilePath(m.evaluator.GetName())
+		if err != nil {
+			log.Warn().Err(err).Msg("could not resolve history file")
+			return
+		}
+		path = p
+	}
+	if err := m.history.Attach(inputhistory.NewFileStore(path)); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("could not load history file")
+	}
+}
+
+func (m *Model) openHistorySearch() {
+	m.histSearch = historySearchModel{
+		active:         true,
+		original:       m.textInput.Value(),
+		originalCursor: m.textInput.Position(),
+	}
+	m.completion.visible = false
+	m.hideHelpBar()
+	m.refreshHistorySearch()
+}
+
+func (m *Model) refreshHistorySearch() {
+	m.histSearch.matches = m.history.Search(m.histSearch.query, -1, 0)
+	m.histSearch.selected = 0
+}
+
+func (m *Model) closeHistorySearch(accept bool) {
+	if accept && m.histSearch.selected < len(m.histSearch.matches) {
+		m.textInput.SetValue(m.histSearch.matches[m.histSearch.selected].Input)
+		m.textInput.CursorEnd()
+	} else {
+		m.textInput.SetValue(m.histSearch.original)
+		m.textInput.SetCursor(m.histSearch.originalCursor)
+	}
+	m.histSearch = historySearchModel{}
+	m.history.ResetNavigation()
+}
+
+func (m *Model) handleHistorySearchInput(k tea.KeyMsg) (bool, tea.Cmd) {
+	if !m.histSearch.active {
+		if !key.Matches(k, m.keyMap.HistorySearch) {
+			return false, nil
+		}
+		// the drawer owns its refresh shortcut while visible
+		if m.helpDrawer.visible && key.Matches(k, m.keyMap.HelpDrawerRefresh) {
+			return false, nil
+		}
+		m.openHistorySearch()
+		return true, nil
+	}
+
+	switch {
+	case key.Matches(k, m.keyMap.HistorySearch), k.Type == tea.KeyDown, k.Type == tea.KeyCtrlN:
+		if m.histSearch.selected < len(m.histSearch.matches)-1 {
+			m.histSearch.selected++
+		}
+	case k.Type == tea.KeyUp, k.Type == tea.KeyCtrlP:
+		if m.histSearch.selected > 0 {
+			m.histSearch.selected--
+		}
+	case k.Type == tea.KeyEnter, k.Type == tea.KeyTab:
+		m.closeHistorySearch(true)
+	case k.Type == tea.KeyEsc, k.Type == tea.KeyCtrlG:
+		m.closeHistorySearch(false)
+	case k.Type == tea.KeyBackspace:
+		if r := []rune(m.histSearch.query); len(r) > 0 {
+			m.histSearch.query = string(r[:len(r)-1])
+			m.refreshHistorySearch()
+		}
+	case k.Type == tea.KeyRunes, k.Type == tea.KeySpace:
+		m.histSearch.query += string(k.Runes)
+		m.refreshHistorySearch()
+	}
+	return true, nil
+}
+
+// renderHistorySearchPanel renders the search overlay: the query line and the matching entries,
+// newest first, with the matched text highlighted.
+func (m *Model) renderHistorySearchPanel(width int) string {
+	s := m.histSearch
+	innerWidth := max(10, width-m.styles.CompletionPopup.GetHorizontalFrameSize())
+
+	start := 0
+	if s.selected >= historySearchMaxVisible {
+		start = s.selected - historySearchMaxVisible + 1
+	}
+	end := min(len(s.matches), start+historySearchMaxVisible)
+
+	lines := make([]string, 0, end-start+1)
+	for i := start; i < end; i++ {
+		mt := s.matches[i]
+		pre, hit, post := splitMatch(mt)
+		itemStyle := m.styles.CompletionItem
+		prefix := "  "
+		if i == s.selected {
+			itemStyle = m.styles.CompletionSelected
+			prefix = "> "
+		}
+		rendered := itemStyle.Render(prefix+flattenNewlines(pre)) +
+			m.styles.HistoryMatch.Render(flattenNewlines(hit)) +
+			itemStyle.Render(flattenNewlines(post))
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(innerWidth).Render(rendered))
+	}
+	if len(s.matches) == 0 {
+		lines = append(lines, m.styles.HelpText.Render("  no matches"))
+	}
+
+	count := fmt.Sprintf("%d/%d", min(s.selected+1, len(s.matches)), len(s.matches))
+	queryLine := m.styles.Prompt.Render("reverse-i-search: ") + s.query + "  " + m.styles.HelpText.Render(count)
+	lines = append(lines, queryLine)
+	return m.styles.CompletionPopup.Width(innerWidth).Render(strings.Join(lines, "\n"))
+}
+
+func splitMatch(mt inputhistory.SearchMatch) (string, string, string) {
+	return mt.Input[:mt.Start], mt.Input[mt.Start:mt.End], mt.Input[mt.End:]
+}
+
+func flattenNewlines(s string) string {
+	return strings.ReplaceAll(s, "\n", "⏎")
+}
+
+type historySearchOverlayLayout struct {
+	PanelX int
+	PanelY int
+	View   string
+}
+
+// computeHistorySearchOverlayLayout anchors the panel right above the input line.
+func (m *Model) computeHistorySearchOverlayLayout(header, timelineView string) (historySearchOverlayLayout, bool) {
+	if !m.histSearch.active || m.width <= 0 || m.height <= 0 {
+		return historySearchOverlayLayout{}, false
+	}
+	view := m.renderHistorySearchPanel(min(m.width, 80))
+	inputY := lipgloss.Height(header) + 1 + lipgloss.Height(timelineView)
+	panelY := clampInt(inputY-lipgloss.Height(view), 0, max(0, m.height-lipgloss.Height(view)))
+	return historySearchOverlayLayout{PanelX: 0, PanelY: panelY, View: view}, true
+}
@@ -0,0 +1,240 @@
+package repl
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/go-go-golems/bobatea/pkg/tui/widgets/suggest"
+)
+
+// inlineSuggestModel is the state of the inline suggestion. Requests go through a suggest.Widget
+// of their own so they share the completion popup's sequencing and stale-drop logic.
+type inlineSuggestModel struct {
+	widget *suggest.Widget
+	// history is the default provider; nil when the evaluator suggests itself
+	history *historyInlineSuggester
+
+	// ghost continues anchor, the input it was computed (or trimmed) for
+	ghost  string
+	anchor string
+}
+
+// inlineDebounceMsg and inlineResultMsg wrap the inline widget's messages so they are not
+// mistaken for the completion popup's.
+type inlineDebounceMsg suggest.DebounceMsg
+type inlineResultMsg suggest.ResultMsg
+
+// inlineSuggestProvider adapts an InlineSuggester to the suggest.Provider the widget drives.
+type inlineSuggestProvider struct {
+	suggester InlineSuggester
+}
+
+func (p inlineSuggestProvider) CompleteInput(ctx context.Context, req suggest.Request) (suggest.Result, error) {
+	s, err := p.suggester.SuggestInline(ctx, req)
+	if err != nil {
+		return suggest.Result{}, err
+	}
+	return suggest.Result{
+		Suggestions: []autocomplete.Suggestion{{Value: s.Text, DisplayText: s.Text}},
+		ReplaceFrom: req.CursorByte,
+		ReplaceTo:   req.CursorByte,
+		Show:        s.Text != "",
+	}, nil
+}
+
+// historyInlineSuggester continues the input with the newest single-line history entry it
+// prefixes. Requests run off the UI goroutine, so it works on a snapshot of the history.
+type historyInlineSuggester struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (h *historyInlineSuggester) setEntries(entries []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = entries
+}
+
+func (h *historyInlineSuggester) SuggestInline(_ context.Context, req CompletionRequest) (InlineSuggestion, error) {
+	input := req.Input
+	if strings.TrimSpace(input) == "" || req.CursorByte != utf8.RuneCountInString(input) {
+		return InlineSuggestion{}, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		e := h.entries[i]
+		if len(e) > len(input) && strings.HasPrefix(e, input) && !strings.Contains(e, "\n") {
+			return InlineSuggestion{Text: e[len(input):]}, nil
+		}
+	}
+	return InlineSuggestion{}, nil
+}
+
+func (m *Model) setupInlineSuggest(cfg InlineSuggestConfig) {
+	m.keyMap.InlineAccept = binding(cfg.AcceptKeys, "accept suggestion")
+	m.keyMap.InlineAcceptWord = binding(cfg.AcceptWordKeys, "accept word")
+
+	var suggester InlineSuggester
+	if s, ok := m.evaluator.(InlineSuggester); ok {
+		suggester = s
+	} else if m.config.EnableHistory {
+		m.inline.history = &historyInlineSuggester{}
+		m.refreshInlineHistory()
+		suggester = m.inline.history
+	}
+	if !cfg.Enabled || suggester == nil {
+		m.inline.history = nil
+		m.keyMap.InlineAccept.SetEnabled(false)
+		m.keyMap.InlineAcceptWord.SetEnabled(false)
+		return
+	}
+	m.inline.widget = suggest.New(inlineSuggestProvider{suggester: suggester}, suggest.Config{
+		Debounce:       cfg.Debounce,
+		RequestTimeout: cfg.RequestTimeout,
+	})
+}
+
+// refreshInlineHistory hands the current input history to the default inline provider.
+func (m *Model) refreshInlineHistory() {
+	if m.inline.history != nil {
+		m.inline.history.setEntries(m.history.GetAll())
+	}
+}
+
+// scheduleInlineSuggestion keeps the shown suggestion while the user types along it and asks the
+// provider again after the debounce.
+func (m *Model) scheduleInlineSuggestion(prevValue string, prevCursor int) tea.Cmd {
+	w := m.inline.widget
+	if w == nil {
+		return nil
+	}
+	value := m.textInput.Value()
+	if value != m.inline.anchor {
+		full := m.inline.anchor + m.inline.ghost
+		if m.inline.ghost != "" && strings.HasPrefix(value, m.inline.anchor) && strings.HasPrefix(full, value) {
+			m.inline.ghost = full[len(value):]
+		} else {
+			m.inline.ghost = ""
+		}
+		m.inline.anchor = value
+	}
+	return wrapInlineCmd(w.OnBufferChanged(prevValue, prevCursor, value, m.textInput.Position()))
+}
+
+func (m *Model) handleDebouncedInline(msg inlineDebounceMsg) tea.Cmd {
+	if m.inline.widget == nil {
+		return nil
+	}
+	return wrapInlineCmd(m.inline.widget.HandleDebounce(m.appContext(), suggest.DebounceMsg(msg), m.textInput.Value(), m.textInput.Position()))
+}
+
+func (m *Model) handleInlineResult(msg inlineResultMsg) tea.Cmd {
+	w := m.inline.widget
+	if w == nil || msg.RequestID != w.RequestSeq() {
+		return nil
+	}
+	w.HandleResult(suggest.ResultMsg(msg))
+	m.inline.anchor = m.textInput.Value()
+	m.inline.ghost = ""
+	if w.Visible() {
+		m.inline.ghost = w.LastResult().Suggestions[0].Value
+	}
+	return nil
+}
+
+// clearInlineSuggestion drops the shown suggestion and any request still in flight.
+func (m *Model) clearInlineSuggestion() {
+	if w := m.inline.widget; w != nil {
+		w.SetRequestSeq(w.RequestSeq() + 1)
+		w.Hide()
+	}
+	m.inline.ghost = ""
+	m.inline.anchor = ""
+}
+
+// inlineGhost returns the suggestion to render after the cursor. It is only shown on a
+// single-line input with the cursor at its end, and gives way to the completion popup.
+func (m *Model) inlineGhost() string {
+	if m.inline.ghost == "" || m.focus != "input" || m.completion.visible || m.textInput.Multiline() {
+		return ""
+	}
+	value := m.textInput.Value()
+	if value != m.inline.anchor || m.textInput.Position() != utf8.RuneCountInString(value) {
+		return ""
+	}
+	return m.inline.ghost
+}
+
+func (m *Model) inlineGhostStyle() lipgloss.Style {
+	style := m.styles.InlineSuggestion
+	if style.GetForeground() == (lipgloss.NoColor{}) && !style.GetFaint() {
+		style = style.Faint(true)
+	}
+	return style
+}
+
+// handleInlineAccept inserts the shown suggestion, or its next word, at the end of the input.
+func (m *Model) handleInlineAccept(k tea.KeyMsg, prevValue string, prevCursor int) (bool, tea.Cmd) {
+	ghost := m.inlineGhost()
+	if ghost == "" {
+		return false, nil
+	}
+	var text string
+	switch {
+	case key.Matches(k, m.keyMap.InlineAccept):
+		text = ghost
+	case key.Matches(k, m.keyMap.InlineAcceptWord):
+		text = nextInlineWord(ghost)
+	default:
+		return false, nil
+	}
+	m.textInput.SetValue(m.textInput.Value() + text)
+	m.textInput.CursorEnd()
+	return true, tea.Batch(
+		m.scheduleDebouncedCompletionIfNeeded(prevValue, prevCursor),
+		m.scheduleInlineSuggestion(prevValue, prevCursor),
+		m.scheduleDebouncedHelpBarIfNeeded(prevValue, prevCursor),
+		m.scheduleDebouncedHelpDrawerIfNeeded(prevValue, prevCursor),
+	)
+}
+
+// nextInlineWord returns the prefix of s up to the end of its first word, including the
+// separators in front of it.
+func nextInlineWord(s string) string {
+	isWord := func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) }
+	inWord := false
+	for i, r := range s {
+		switch {
+		case isWord(r):
+			inWord = true
+		case inWord:
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// wrapInlineCmd retags the inline widget's messages.
+func wrapInlineCmd(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch v := cmd().(type) {
+		case suggest.DebounceMsg:
+			return inlineDebounceMsg(v)
+		case suggest.ResultMsg:
+			return inlineResultMsg(v)
+		default:
+			return v
+		}
+	}
+}
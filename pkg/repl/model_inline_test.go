@@ -0,0 +1,110 @@
+package repl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/eventbus"
+	"github.com/stretchr/testify/require"
+)
+
+func newInlineTestModel(t *testing.T, evaluator Evaluator) *Model {
+	t.Helper()
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+
+	cfg := DefaultConfig()
+	cfg.Autocomplete.Enabled = false
+	cfg.Highlight.Enabled = false
+	cfg.InlineSuggest.Debounce = time.Nanosecond
+	m := NewModel(evaluator, cfg, bus.Publisher)
+	_, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	return m
+}
+
+// typeInline types s and runs the inline suggestion request the last key scheduled.
+func typeInline(m *Model, s string) {
+	typeRunes(m, s)
+	fetchInline(m)
+}
+
+func fetchInline(m *Model) {
+	drainModelCmds(m, m.handleDebouncedInline(inlineDebounceMsg{RequestID: m.inline.widget.RequestSeq()}))
+}
+
+func TestHistoryInlineSuggesterPrefersNewestEntry(t *testing.T) {
+	h := &historyInlineSuggester{}
+	h.setEntries([]string{"print(1)", "print(2)", "print(3)\nx", "pr"})
+
+	s, err := h.SuggestInline(context.Background(), CompletionRequest{Input: "pr", CursorByte: 2})
+	require.NoError(t, err)
+	require.Equal(t, "int(2)", s.Text)
+
+	s, _ = h.SuggestInline(context.Background(), CompletionRequest{Input: "pr", CursorByte: 1})
+	require.Empty(t, s.Text, "cursor must be at the end")
+	s, _ = h.SuggestInline(context.Background(), CompletionRequest{Input: "  ", CursorByte: 2})
+	require.Empty(t, s.Text)
+}
+
+func TestInlineSuggestionFromHistoryIsAccepted(t *testing.T) {
+	m := newInlineTestModel(t, NewExampleEvaluator())
+	m.history.Add("console.log(value)", "", false)
+	m.refreshInlineHistory()
+
+	typeInline(m, "con")
+	require.Equal(t, "sole.log(value)", m.inlineGhost())
+	require.Contains(t, m.View(), "ole.log(value)")
+
+	// typing along the suggestion keeps the rest of it
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	require.Equal(t, "ole.log(value)", m.inlineGhost())
+
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	require.Equal(t, "console", m.textInput.Value())
+	require.Equal(t, ".log(value)", m.inlineGhost())
+
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	fetchInline(m)
+	require.Equal(t, "console.log(value)", m.textInput.Value())
+	require.Empty(t, m.inlineGhost())
+
+	// without a suggestion right moves the cursor as usual
+	m.textInput.SetCursor(3)
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	require.Equal(t, 4, m.textInput.Position())
+	require.Equal(t, "console.log(value)", m.textInput.Value())
+}
+
+func TestInlineSuggestionDroppedOnDivergingInput(t *testing.T) {
+	m := newInlineTestModel(t, NewExampleEvaluator())
+	m.history.Add("echo hello", "", false)
+	m.refreshInlineHistory()
+
+	typeInline(m, "ec")
+	require.Equal(t, "ho hello", m.inlineGhost())
+
+	typeRunes(m, "x")
+	require.Empty(t, m.inlineGhost())
+	fetchInline(m)
+	require.Empty(t, m.inlineGhost())
+}
+
+type fakeInlineEvaluator struct {
+	*ExampleEvaluator
+}
+
+func (f *fakeInlineEvaluator) SuggestInline(context.Context, CompletionRequest) (InlineSuggestion, error) {
+	return InlineSuggestion{Text: "()"}, nil
+}
+
+func TestInlineSuggesterEvaluatorWinsOverHistory(t *testing.T) {
+	m := newInlineTestModel(t, &fakeInlineEvaluator{ExampleEvaluator: NewExampleEvaluator()})
+	require.Nil(t, m.inline.history)
+
+	typeInline(m, "f")
+	require.Equal(t, "()", m.inlineGhost())
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	require.Equal(t, "f()", m.textInput.Value())
+}
@@ -1,12 +1,14 @@
 package repl
 
 import (
+	"context"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
 
@@ -15,6 +17,14 @@ func (m *Model) updateInput(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 	prevValue := m.textInput.Value()
 	prevCursor := m.textInput.Position()
 
+	if handled, cmd := m.handleHistorySearchInput(k); handled {
+		return m, cmd
+	}
+
+	if handled, cmd := m.handleTurnListInput(k); handled {
+		return m, cmd
+	}
+
 	if handled, cmd := m.handleCommandPaletteInput(k); handled {
 		return m, cmd
 	}
@@ -27,6 +37,14 @@ func (m *Model) updateInput(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	if handled, cmd := m.handleSnippetInput(k, prevValue, prevCursor); handled {
+		return m, cmd
+	}
+
+	if handled, cmd := m.handleInlineAccept(k, prevValue, prevCursor); handled {
+		return m, cmd
+	}
+
 	if cmd := m.triggerCompletionFromShortcut(k); cmd != nil {
 		return m, cmd
 	}
@@ -39,19 +57,28 @@ func (m *Model) updateInput(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.updateKeyBindings()
 		m.applyLayoutAndRefresh()
 		return m, nil
+	case key.Matches(k, m.keyMap.ToggleMultiline):
+		cmd := m.textInput.SetMultiline(!m.textInput.Multiline())
+		m.applyLayoutAndRefresh()
+		return m, cmd
+	case key.Matches(k, m.keyMap.InsertNewline):
+		var cmd tea.Cmd
+		if !m.textInput.Multiline() {
+			cmd = m.textInput.SetMultiline(true)
+		}
+		m.textInput.InsertNewline()
+		m.applyLayoutAndRefresh()
+		return m, tea.Batch(cmd, m.scheduleDebouncedHelpBarIfNeeded(prevValue, prevCursor))
 	case key.Matches(k, m.keyMap.Submit):
 		input := m.textInput.Value()
 		if strings.TrimSpace(input) == "" {
 			return m, nil
 		}
-		m.textInput.Reset()
-		m.hideHelpBar()
-		if m.config.EnableHistory {
-			m.history.Add(input, "", false)
-			m.history.ResetNavigation()
+		if m.usesCompletenessChecker() {
+			return m, m.checkInputCompletenessCmd(input)
 		}
-		return m, m.submit(input)
-	case key.Matches(k, m.keyMap.HistoryPrev):
+		return m, m.submitOrContinue(input, InputCompletenessResult{})
+	case key.Matches(k, m.keyMap.HistoryPrev) && m.cursorOnFirstRow():
 		if m.config.EnableHistory {
 			if entry := m.history.NavigateUp(); entry != "" {
 				m.textInput.SetValue(entry)
@@ -59,35 +86,158 @@ func (m *Model) updateInput(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(
 			m.scheduleDebouncedCompletionIfNeeded(prevValue, prevCursor),
+			m.scheduleInlineSuggestion(prevValue, prevCursor),
 			m.scheduleDebouncedHelpBarIfNeeded(prevValue, prevCursor),
 			m.scheduleDebouncedHelpDrawerIfNeeded(prevValue, prevCursor),
 		)
-	case key.Matches(k, m.keyMap.HistoryNext):
+	case key.Matches(k, m.keyMap.HistoryNext) && m.cursorOnLastRow():
 		if m.config.EnableHistory {
 			entry := m.history.NavigateDown()
 			m.textInput.SetValue(entry)
 		}
 		return m, tea.Batch(
 			m.scheduleDebouncedCompletionIfNeeded(prevValue, prevCursor),
+			m.scheduleInlineSuggestion(prevValue, prevCursor),
 			m.scheduleDebouncedHelpBarIfNeeded(prevValue, prevCursor),
 			m.scheduleDebouncedHelpDrawerIfNeeded(prevValue, prevCursor),
 		)
 	}
+	if m.replayingChord {
+		return m, nil
+	}
 	var cmd tea.Cmd
+	prevHeight := m.textInput.Height()
 	m.textInput, cmd = m.textInput.Update(k)
+	if m.textInput.Height() != prevHeight {
+		m.applyLayoutAndRefresh()
+	}
 	return m, tea.Batch(
 		cmd,
 		m.scheduleDebouncedCompletionIfNeeded(prevValue, prevCursor),
+		m.scheduleInlineSuggestion(prevValue, prevCursor),
 		m.scheduleDebouncedHelpBarIfNeeded(prevValue, prevCursor),
 		m.scheduleDebouncedHelpDrawerIfNeeded(prevValue, prevCursor),
 	)
 }
 
+// inputCompletenessMsg carries the InputCompletenessChecker result for the input Enter was
+// pressed on. It is dropped when the input changed or Enter was pressed again in the meantime.
+type inputCompletenessMsg struct {
+	seq   int
+	input string
+	res   InputCompletenessResult
+	err   error
+}
+
+// usesCompletenessChecker reports whether Enter asks the evaluator if the input is complete.
+func (m *Model) usesCompletenessChecker() bool {
+	return m.completeness != nil &&
+		m.multiline.SubmitPolicy != MultilineSubmitEnter &&
+		m.evaluator.SupportsMultiline()
+}
+
+// checkInputCompletenessCmd runs the InputCompletenessChecker off the UI loop, since a remote
+// evaluator answers over RPC.
+func (m *Model) checkInputCompletenessCmd(input string) tea.Cmd {
+	m.completenessSeq++
+	seq, checker := m.completenessSeq, m.completeness
+	ctx, timeout := m.appContext(), m.multiline.CompletenessTimeout
+	req := InputCompletenessRequest{Input: input, CursorByte: m.textInput.Position()}
+	return func() (msg tea.Msg) {
+		out := inputCompletenessMsg{seq: seq, input: input}
+		defer func() {
+			if r := recover(); r != nil {
+				out.err = errors.Errorf("input completeness checker panic: %v", r)
+				msg = out
+			}
+		}()
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		out.res, out.err = checker.CheckInputCompleteness(ctx, req)
+		return out
+	}
+}
+
+func (m *Model) handleInputCompleteness(msg inputCompletenessMsg) tea.Cmd {
+	if msg.seq != m.completenessSeq || msg.input != m.textInput.Value() {
+		return nil
+	}
+	if msg.err != nil {
+		log.Debug().Err(msg.err).Msg("input completeness check failed, using heuristic")
+		msg.res = InputCompletenessResult{}
+	}
+	return m.submitOrContinue(msg.input, msg.res)
+}
+
+// submitOrContinue submits input or continues it on a new line. A result from the evaluator's
+// InputCompletenessChecker decides; without one (empty Status) the balance heuristic applies, but
+// only to input that is already multiline.
+func (m *Model) submitOrContinue(input string, res InputCompletenessResult) tea.Cmd {
+	if indent, ok := m.continueInput(input, res); ok {
+		cmd := m.textInput.SetMultiline(true)
+		m.textInput.InsertNewline()
+		m.textInput.InsertString(indent)
+		m.applyLayoutAndRefresh()
+		return cmd
+	}
+	if ok, reason := m.acceptsInput(); !ok {
+		m.showHelpBarNotice(reason, "warning")
+		return nil
+	}
+	m.textInput.Reset()
+	m.endSnippet()
+	m.clearInlineSuggestion()
+	m.applyLayoutAndRefresh()
+	m.hideHelpBar()
+	if m.config.EnableHistory {
+		m.history.Add(input, "", false)
+		m.history.ResetNavigation()
+		m.refreshInlineHistory()
+	}
+	return m.submit(input)
+}
+
+// continueInput decides whether Enter should continue the input on a new line instead of
+// submitting it, and with which indentation.
+func (m *Model) continueInput(input string, res InputCompletenessResult) (string, bool) {
+	if m.multiline.SubmitPolicy == MultilineSubmitEnter || !m.evaluator.SupportsMultiline() {
+		return "", false
+	}
+	if res.Status != "" {
+		return res.Indent, res.Status == InputIncomplete
+	}
+	if !m.textInput.Multiline() {
+		return "", false
+	}
+	return "", !inputLooksBalanced(input)
+}
+
+// cursorOnFirstRow and cursorOnLastRow let up/down move between lines of a multiline input and
+// only navigate history at its edges.
+func (m *Model) cursorOnFirstRow() bool {
+	row, _ := m.textInput.CursorRow()
+	return row == 0
+}
+
+func (m *Model) cursorOnLastRow() bool {
+	row, count := m.textInput.CursorRow()
+	return row == count-1
+}
+
 func (m *Model) updateTimeline(k tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.sh.SearchInputActive() {
+		return m, m.sh.UpdateSearch(k)
+	}
+	if handled, cmd := m.updateTimelineSearch(k); handled {
+		return m, cmd
+	}
 	switch {
 	case key.Matches(k, m.keyMap.ToggleFocus):
 		m.focus = "input"
 		m.textInput.Focus()
+		if m.sh.SearchActive() {
+			m.sh.ClearSearch()
+		}
 		m.sh.SetSelectionVisible(false)
 		m.updateKeyBindings()
 		m.applyLayoutAndRefresh()
@@ -110,6 +260,9 @@ func (m *Model) updateTimeline(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(k, m.keyMap.CopyText):
 		return m, m.sh.SendToSelected(timeline.EntityCopyTextMsg{})
 	}
+	if m.replayingChord {
+		return m, nil
+	}
 	// route keys to shell/controller (e.g., Tab cycles inside entity)
 	cmd := m.sh.HandleMsg(k)
 	return m, cmd
@@ -117,4 +270,12 @@ func (m *Model) updateTimeline(k tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m *Model) updateKeyBindings() {
 	mode_keymap.EnableMode(&m.keyMap, m.focus)
+	// EnableMode only knows about focus; turn off bindings for features that are unavailable
+	if !m.config.EnableHistory {
+		m.keyMap.HistorySearch.SetEnabled(false)
+	}
+	if !m.evaluator.SupportsMultiline() {
+		m.keyMap.InsertNewline.SetEnabled(false)
+		m.keyMap.ToggleMultiline.SetEnabled(false)
+	}
 }
@@ -0,0 +1,75 @@
+package repl
+
+import (
+	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
+	"github.com/rs/zerolog/log"
+)
+
+// applyKeyMapOverrides applies the keymap file and then the configured overrides. A file that
+// cannot be loaded or names unknown bindings is skipped with a warning, as are conflicts
+// the overrides introduce; they are listed again in the key bindings view.
+func (m *Model) applyKeyMapOverrides(path string, bindings mode_keymap.Overrides) {
+	m.keyOverrides = mode_keymap.Overrides{}
+	m.keyConflicts = nil
+
+	apply := func(source string, overrides mode_keymap.Overrides) {
+		conflicts, err := overrides.Apply(&m.keyMap)
+		if err != nil {
+			log.Warn().Err(err).Str("source", source).Msg("ignoring key bindings")
+			return
+		}
+		for name, o := range overrides {
+			m.keyOverrides[name] = o
+		}
+		for _, c := range conflicts {
+			log.Warn().Str("source", source).Msg("key binding conflict: " + c.String())
+		}
+	}
+	if path != "" {
+		overrides, err := mode_keymap.LoadOverrides(path)
+		if err != nil {
+			log.Warn().Err(err).Msg("could not load keymap file")
+		} else {
+			apply(path, overrides)
+		}
+	}
+	if len(bindings) > 0 {
+		apply("config", bindings)
+	}
+	if len(m.keyOverrides) > 0 {
+		m.keyConflicts = mode_keymap.FindConflicts(&m.keyMap)
+	}
+}
+
+// EffectiveKeyMap describes the key bindings in use, including overrides from Config.KeyMapFile
+// and Config.KeyBindings.
+func (m *Model) EffectiveKeyMap() []mode_keymap.BindingInfo {
+	return mode_keymap.Describe(&m.keyMap, m.keyOverrides)
+}
+
+// showKeyBindings opens the help drawer on the effective key map. The drawer is pinned so typing
+// does not replace it with contextual help.
+func (m *Model) showKeyBindings() {
+	m.ensureHelpDrawerWidget()
+	if m.helpDrawer.widget == nil {
+		return
+	}
+	doc := HelpDrawerDocument{
+		Show:     true,
+		Title:    "Key Bindings",
+		Markdown: mode_keymap.FormatKeyMap(m.EffectiveKeyMap()),
+	}
+	if len(m.keyOverrides) > 0 {
+		doc.Subtitle = "* overridden"
+	}
+	for _, c := range m.keyConflicts {
+		doc.Diagnostics = append(doc.Diagnostics, "conflict: "+c.String())
+	}
+	m.helpDrawer.widget.SetDocument(doc)
+	m.helpDrawer.widget.SetLoading(false)
+	m.helpDrawer.widget.SetErr(nil)
+	m.helpDrawer.widget.SetVisible(true)
+	m.helpDrawer.widget.SetPinned(true)
+	m.syncHelpDrawerLegacyFromWidget()
+	m.completion.visible = false
+}
@@ -0,0 +1,85 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyMapFileOverridesBindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("history-search: ctrl+s\nturn-list: up\n"), 0o644))
+
+	m := newCommandPaletteTestModel(t, &fakeCommandPaletteEvaluator{}, func(cfg *Config) {
+		cfg.KeyMapFile = path
+		cfg.KeyBindings = mode_keymap.Overrides{"copy-code": {Keys: []string{"C"}}}
+	})
+	assert.Equal(t, []string{"ctrl+s"}, m.keyMap.HistorySearch.Keys())
+	assert.Equal(t, "ctrl+s", m.keyMap.HistorySearch.Help().Key)
+	assert.Equal(t, []string{"C"}, m.keyMap.CopyCode.Keys())
+
+	overridden := map[string]bool{}
+	for _, b := range m.EffectiveKeyMap() {
+		if b.Overridden {
+			overridden[b.Name] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"HistorySearch": true, "TurnList": true, "CopyCode": true}, overridden)
+	require.NotEmpty(t, m.keyConflicts, "up is also history prev")
+
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyCtrlS})
+	assert.True(t, m.histSearch.active)
+
+	m.showKeyBindings()
+	require.True(t, m.helpDrawer.visible)
+	assert.Equal(t, "Key Bindings", m.helpDrawer.doc.Title)
+	assert.Contains(t, m.helpDrawer.doc.Markdown, "| HistorySearch * | `ctrl+s` |")
+	assert.Contains(t, m.helpDrawer.doc.Diagnostics, "conflict: input: up is bound to HistoryPrev, TurnList, CompletionPrev")
+}
+
+func TestKeyMapFileWithUnknownBindingIsIgnored(t *testing.T) {
+	m := newCommandPaletteTestModel(t, &fakeCommandPaletteEvaluator{}, func(cfg *Config) {
+		cfg.KeyBindings = mode_keymap.Overrides{"submit": {Keys: []string{"ctrl+j"}}, "launch": {Keys: []string{"l"}}}
+	})
+	assert.Equal(t, []string{"enter"}, m.keyMap.Submit.Keys())
+	assert.Empty(t, m.keyOverrides)
+
+	// the key bindings view closes with the drawer close key even without a help provider
+	m.showKeyBindings()
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.helpDrawer.visible)
+}
+
+func TestChordBindingsDispatchAndShowContinuations(t *testing.T) {
+	m := newCommandPaletteTestModel(t, &fakeCommandPaletteEvaluator{}, func(cfg *Config) {
+		cfg.KeyBindings = mode_keymap.Overrides{
+			"turn-list":      {Keys: []string{"ctrl+x t"}, Help: "turns"},
+			"history-search": {Keys: []string{"leader r"}},
+		}
+		cfg.Chords.Leader = "ctrl+a"
+	})
+	_, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	require.NotNil(t, cmd, "chord timeout")
+	require.True(t, m.chords.Pending())
+	assert.Contains(t, m.View(), "ctrl+x …")
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	require.NotNil(t, cmd)
+	_, _ = m.Update(cmd())
+	assert.True(t, m.turnList.visible)
+	assert.Equal(t, "", m.textInput.Value(), "the chord is not typed into the input")
+	m.turnList.visible = false
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	require.NotNil(t, cmd)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	_, _ = m.Update(cmd())
+	assert.True(t, m.histSearch.active)
+}
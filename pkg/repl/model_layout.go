@@ -10,7 +10,7 @@ func (m *Model) applyLayoutFromState() bool {
 	}
 
 	m.help.Width = max(0, m.width)
-	m.textInput.Width = max(10, m.width-10)
+	m.textInput.SetWidth(max(10, m.width-10))
 	m.palette.ui.SetSize(m.width, max(0, m.height))
 
 	if m.height <= 0 {
@@ -22,7 +22,7 @@ func (m *Model) applyLayoutFromState() bool {
 	if helpBarView := m.renderHelpBar(); helpBarView != "" {
 		helpBarHeight = lipgloss.Height(helpBarView)
 	}
-	timelineHeight := max(0, m.height-helpHeight-helpBarHeight-4)
+	timelineHeight := max(0, m.height-helpHeight-helpBarHeight-3-m.textInput.Height())
 	changed := m.timelineWidth != m.width || m.timelineHeight != timelineHeight
 	if !changed {
 		return false
@@ -33,4 +33,6 @@ type helpDrawerResultMsg = contextpanel.ResultMsg
 
 type completionOverlayLayout = suggest.OverlayLayout
 
+type completionDocLayout = suggest.DocumentationLayout
+
 type helpDrawerOverlayLayout = contextpanel.OverlayLayout
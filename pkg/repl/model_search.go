@@ -0,0 +1,30 @@
+package repl
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateTimelineSearch handles the search keys of the timeline. n, N and esc are left to the
+// entities unless a search is active, and an entered entity keeps all its keys.
+func (m *Model) updateTimelineSearch(k tea.KeyMsg) (bool, tea.Cmd) {
+	if m.sh.IsEntering() {
+		return false, nil
+	}
+	switch {
+	case key.Matches(k, m.keyMap.TimelineSearch):
+		return true, m.sh.StartSearch()
+	case !m.sh.SearchActive():
+		return false, nil
+	case key.Matches(k, m.keyMap.TimelineSearchNext):
+		m.sh.SearchNext()
+		return true, nil
+	case key.Matches(k, m.keyMap.TimelineSearchPrev):
+		m.sh.SearchPrev()
+		return true, nil
+	case key.Matches(k, m.keyMap.TimelineSearchClear):
+		m.sh.ClearSearch()
+		return true, nil
+	}
+	return false, nil
+}
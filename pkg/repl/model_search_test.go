@@ -0,0 +1,44 @@
+package repl
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimelineSearchNavigatesMatches(t *testing.T) {
+	m := newCommandPaletteTestModel(t, &fakeCommandPaletteEvaluator{}, nil)
+	for i, text := range []string{"x := 1", "fmt.Println(x)", "x + 1", "done"} {
+		m.ctrl().OnCreated(timeline.UIEntityCreated{
+			ID:       timeline.EntityID{LocalID: fmt.Sprint(i), Kind: "text"},
+			Renderer: timeline.RendererDescriptor{Kind: "text"},
+			Props:    map[string]any{"text": text},
+		})
+	}
+	m.focus = "timeline"
+	m.updateKeyBindings()
+	m.sh.Select(0)
+
+	key := func(s string) { _, _ = m.updateTimeline(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}) }
+	key("/")
+	require.True(t, m.sh.SearchInputActive())
+	key("x")
+	require.Equal(t, "1/3", m.sh.SearchStatus())
+	_, _ = m.updateTimeline(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Contains(t, m.View(), "/x  1/3")
+
+	key("n")
+	require.Equal(t, "2/3", m.sh.SearchStatus())
+	require.Equal(t, 1, m.sh.SelectedIndex())
+	key("N")
+	key("N")
+	require.Equal(t, "3/3", m.sh.SearchStatus())
+	require.Equal(t, 2, m.sh.SelectedIndex())
+
+	_, _ = m.updateTimeline(tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, m.sh.SearchActive())
+	require.NotContains(t, m.View(), "/x")
+}
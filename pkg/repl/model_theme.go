@@ -0,0 +1,61 @@
+package repl
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/commandpalette"
+	mode_keymap "github.com/go-go-golems/bobatea/pkg/mode-keymap"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+	"github.com/rs/zerolog/log"
+)
+
+// SetStyles replaces the REPL styles. Use SetTheme to restyle the palette, the which-key
+// popup and the timeline as well.
+func (m *Model) SetStyles(styles Styles) {
+	m.styles = styles
+}
+
+// SetTheme makes t the active theme and restyles the REPL, its overlays and the timeline
+// renderers with it. The REPL also applies themes announced by theme.ChangedMsg, so
+// theme.Use switches the theme of every component at runtime.
+func (m *Model) SetTheme(t theme.Theme) {
+	theme.Set(t)
+	m.styles = StylesFromTheme(t)
+	m.help.Styles = theme.HelpStyles(t)
+	m.palette.ui = m.palette.ui.WithStyles(commandpalette.StylesFromTheme(t))
+	m.chords = m.chords.WithStyles(mode_keymap.WhichKeyStylesFromTheme(t))
+	m.ctrl().SetTheme(t.Name)
+	m.sh.RefreshView(false)
+}
+
+// applyThemeConfig registers the theme files of cfg and applies the configured theme.
+func (m *Model) applyThemeConfig(cfg ThemeConfig) {
+	for _, path := range cfg.Files {
+		t, err := theme.Load(path)
+		if err != nil {
+			log.Warn().Err(err).Str("component", "repl").Msg("could not load theme")
+			continue
+		}
+		theme.Register(t)
+	}
+	if strings.TrimSpace(cfg.Name) == "" {
+		return
+	}
+	t, err := theme.Resolve(cfg.Name)
+	if err != nil {
+		log.Warn().Err(err).Str("component", "repl").Msg("could not apply theme")
+		return
+	}
+	m.SetTheme(t)
+}
+
+// switchTheme is the palette action of "Switch Theme".
+func (m *Model) switchTheme(name string) tea.Cmd {
+	t, err := theme.Resolve(name)
+	if err != nil {
+		m.showHelpBarNotice(err.Error(), "error")
+		return nil
+	}
+	return theme.Use(t)
+}
@@ -0,0 +1,52 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+	"github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeConfigLoadsFilesAndRestylesOnSwitch(t *testing.T) {
+	t.Cleanup(theme.Reset)
+	path := filepath.Join(t.TempDir(), "midnight.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: midnight\nextends: dark\ncolors:\n  accent: \"#7aa2f7\"\n"), 0o644))
+
+	m := newCommandPaletteTestModel(t, &fakeCommandPaletteEvaluator{}, func(cfg *Config) {
+		cfg.Theme = ThemeConfig{Name: "midnight", Files: []string{path}}
+	})
+	require.Equal(t, lipgloss.Color("#7aa2f7"), m.styles.Prompt.GetForeground())
+	current, ok := theme.Current()
+	require.True(t, ok)
+	require.Equal(t, "midnight", current.Name)
+
+	typeKeys := func(s string) {
+		for _, r := range s {
+			_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyCtrlP})
+	typeKeys("switch theme")
+	_, _ = m.updateInput(tea.KeyMsg{Type: tea.KeyEnter})
+	require.True(t, m.palette.ui.CollectingArguments())
+	typeKeys("solarized-light")
+	_, cmd := m.updateInput(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+
+	solarized, _ := theme.Get(theme.SolarizedLight)
+	current, _ = theme.Current()
+	require.Equal(t, solarized, current)
+	_, _ = m.Update(theme.ChangedMsg{Theme: solarized})
+	require.Equal(t, solarized.Palette.Error, m.styles.Error.GetForeground())
+	require.Equal(t, solarized.Palette.Border, chatstyle.Current().UnselectedMessage.GetBorderTopForeground())
+
+	// unknown names leave the theme alone
+	require.Nil(t, m.switchTheme("nope"))
+	current, _ = theme.Current()
+	require.Equal(t, theme.SolarizedLight, current.Name)
+}
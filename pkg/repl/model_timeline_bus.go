@@ -11,23 +11,45 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/go-go-golems/bobatea/pkg/eventbus"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
 
-// submit runs evaluation and streams events to m.events
-func (m *Model) submit(code string) tea.Cmd {
-	return func() tea.Msg {
-		turnID := newTurnID(m.turnSeq)
-		m.turnSeq++
+// startTurn runs the turn's evaluation and streams events to the bus. The turn gets its own
+// context so it can be cancelled; an interrupted turn ends with an EventInterrupted.
+func (m *Model) startTurn(t *replTurn) tea.Cmd {
+	turnID, code := t.ID, t.Input
+	ctx, cancel := context.WithCancel(m.appContext())
+	started := timeNow()
+	t.Status = TurnRunning
+	t.Started = started
+	t.cancel = cancel
+
+	evaluate := func() tea.Msg {
+		defer cancel()
 		// Create input entity directly on UI bus to guarantee ordering and avoid extra newlines
 		_ = m.publishUIEntityCreated(turnID, timeline.EntityID{TurnID: turnID, LocalID: "input", Kind: "text"}, timeline.RendererDescriptor{Kind: "text"}, map[string]any{"text": code})
 		// Optionally still publish the semantic input event to repl.events? We skip to avoid duplicate UI entities.
-		_ = m.evaluator.EvaluateStream(context.Background(), code, func(e Event) {
+		err := m.evaluator.EvaluateStream(ctx, code, func(e Event) {
 			log.Trace().Str("turn_id", turnID).Interface("event", e).Msg("publishing repl event")
 			_ = m.publishReplEvent(turnID, e)
 		})
-		return nil
+		interrupted := errors.Is(ctx.Err(), context.Canceled)
+		if interrupted {
+			elapsed := timeNow().Sub(started)
+			_ = m.publishReplEvent(turnID, Event{Kind: EventInterrupted, Props: map[string]any{
+				"text":       "interrupted after " + formatElapsed(elapsed),
+				"elapsed_ms": elapsed.Milliseconds(),
+			}})
+		}
+		return evaluationFinishedMsg{turnID: turnID, err: err, interrupted: interrupted}
+	}
+	if m.spinnerActive {
+		return evaluate
 	}
+	m.spinnerActive = true
+	m.spinner = newRunningSpinner()
+	return tea.Batch(evaluate, m.spinner.Tick)
 }
 
 func (m *Model) publishReplEvent(turnID string, e Event) error {
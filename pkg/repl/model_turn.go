@@ -0,0 +1,269 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TurnStatus is the lifecycle state of a submitted input.
+type TurnStatus string
+
+const (
+	TurnPending   TurnStatus = "pending"
+	TurnRunning   TurnStatus = "running"
+	TurnDone      TurnStatus = "done"
+	TurnFailed    TurnStatus = "failed"
+	TurnCancelled TurnStatus = "cancelled"
+)
+
+// TurnInfo is a snapshot of a submitted input, as listed by Model.Turns.
+type TurnInfo struct {
+	ID       string
+	Input    string
+	Status   TurnStatus
+	Started  time.Time
+	Finished time.Time
+	Err      error
+}
+
+// IsFinished reports whether the turn reached a terminal status.
+func (t TurnInfo) IsFinished() bool {
+	return t.Status == TurnDone || t.Status == TurnFailed || t.Status == TurnCancelled
+}
+
+// replTurn is a turn tracked by the model; cancel is set while it runs.
+type replTurn struct {
+	TurnInfo
+	cancel context.CancelFunc
+	// cancelRequested is set once the user asked to interrupt the running turn
+	cancelRequested bool
+}
+
+// evaluationFinishedMsg is returned by the evaluation command once EvaluateStream returns.
+type evaluationFinishedMsg struct {
+	turnID      string
+	err         error
+	interrupted bool
+}
+
+// remoteEvaluateMsg submits code produced by a remote palette command.
+type remoteEvaluateMsg struct {
+	code string
+}
+
+func newRunningSpinner() spinner.Model {
+	return spinner.New(spinner.WithSpinner(spinner.MiniDot))
+}
+
+// Turns returns the tracked turns, oldest first.
+func (m *Model) Turns() []TurnInfo {
+	ret := make([]TurnInfo, 0, len(m.turns))
+	for _, t := range m.turns {
+		ret = append(ret, t.TurnInfo)
+	}
+	return ret
+}
+
+// Running reports whether an evaluation is in flight.
+func (m *Model) Running() bool { return m.countTurns(TurnRunning) > 0 }
+
+func (m *Model) countTurns(status TurnStatus) int {
+	n := 0
+	for _, t := range m.turns {
+		if t.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *Model) findTurn(id string) *replTurn {
+	for _, t := range m.turns {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// acceptsInput reports whether the turn policy lets new input be submitted right now, and why not.
+func (m *Model) acceptsInput() (bool, string) {
+	switch m.turnsCfg.Policy {
+	case TurnPolicyReject:
+		if m.Running() {
+			return false, "an evaluation is running; press ctrl+c to cancel it"
+		}
+	case TurnPolicyQueue:
+		if m.countTurns(TurnPending) >= m.turnsCfg.MaxQueued {
+			return false, fmt.Sprintf("%d turns already queued", m.turnsCfg.MaxQueued)
+		}
+	}
+	return true, ""
+}
+
+// submit records a turn for code and starts it when the turn policy allows; otherwise it waits in
+// the queue until the running turn finishes.
+func (m *Model) submit(code string) tea.Cmd {
+	t := &replTurn{TurnInfo: TurnInfo{ID: newTurnID(m.turnSeq), Input: code, Status: TurnPending}}
+	m.turnSeq++
+	m.turns = append(m.turns, t)
+	m.pruneTurns()
+	if m.turnsCfg.Policy == TurnPolicyConcurrent || !m.Running() {
+		return m.startTurn(t)
+	}
+	return nil
+}
+
+// startNextTurn starts the oldest pending turn once nothing is running.
+func (m *Model) startNextTurn() tea.Cmd {
+	if m.Running() {
+		return nil
+	}
+	for _, t := range m.turns {
+		if t.Status == TurnPending {
+			return m.startTurn(t)
+		}
+	}
+	return nil
+}
+
+// pruneTurns drops the oldest finished turns beyond MaxListed.
+func (m *Model) pruneTurns() {
+	excess := len(m.turns) - m.turnsCfg.MaxListed
+	if excess <= 0 {
+		return
+	}
+	kept := m.turns[:0]
+	for _, t := range m.turns {
+		if excess > 0 && t.IsFinished() {
+			excess--
+			continue
+		}
+		kept = append(kept, t)
+	}
+	m.turns = kept
+	m.turnList.selected = clampInt(m.turnList.selected, 0, max(0, len(m.turns)-1))
+}
+
+// CancelEvaluation interrupts every running evaluation and drops queued turns. It returns false
+// when there was nothing left to cancel.
+func (m *Model) CancelEvaluation() bool {
+	cancelled := false
+	for _, t := range m.turns {
+		if m.cancelTurn(t) {
+			cancelled = true
+		}
+	}
+	return cancelled
+}
+
+// CancelTurn cancels one turn: a pending turn is removed from the queue, a running one is
+// interrupted. It returns false for unknown or finished turns.
+func (m *Model) CancelTurn(id string) bool {
+	t := m.findTurn(id)
+	if t == nil {
+		return false
+	}
+	return m.cancelTurn(t)
+}
+
+func (m *Model) cancelTurn(t *replTurn) bool {
+	switch t.Status {
+	case TurnPending:
+		t.Status = TurnCancelled
+		t.Finished = timeNow()
+		return true
+	case TurnRunning:
+		if t.cancelRequested {
+			return false
+		}
+		t.cancelRequested = true
+		t.cancel()
+		return true
+	}
+	return false
+}
+
+// handleQuitKey implements ctrl+c: the first press interrupts running and queued evaluations,
+// a second press (or a press while idle) quits.
+func (m *Model) handleQuitKey() tea.Cmd {
+	if m.CancelEvaluation() {
+		return nil
+	}
+	m.cancelAppContext()
+	return tea.Quit
+}
+
+func (m *Model) handleEvaluationFinished(v evaluationFinishedMsg) tea.Cmd {
+	t := m.findTurn(v.turnID)
+	if t == nil || t.Status != TurnRunning {
+		return m.startNextTurn()
+	}
+	t.Finished = timeNow()
+	t.Err = v.err
+	t.cancel = nil
+	switch {
+	case v.interrupted:
+		t.Status = TurnCancelled
+	case v.err != nil:
+		t.Status = TurnFailed
+	default:
+		t.Status = TurnDone
+	}
+	return m.startNextTurn()
+}
+
+func (m *Model) handleSpinnerTick(v spinner.TickMsg) tea.Cmd {
+	if !m.Running() {
+		// let the tick loop die; startTurn restarts it
+		m.spinnerActive = false
+		return nil
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(v)
+	return cmd
+}
+
+// runningIndicator renders the spinner, elapsed time and queue length shown next to the title.
+func (m *Model) runningIndicator() string {
+	var oldest *replTurn
+	running, cancelling := 0, 0
+	for _, t := range m.turns {
+		if t.Status != TurnRunning {
+			continue
+		}
+		running++
+		if t.cancelRequested {
+			cancelling++
+		}
+		if oldest == nil {
+			oldest = t
+		}
+	}
+	if oldest == nil {
+		return ""
+	}
+	label := "running"
+	if cancelling == running {
+		label = "cancelling"
+	}
+	if running > 1 {
+		label = fmt.Sprintf("%d %s", running, label)
+	}
+	s := fmt.Sprintf("%s %s %s", m.spinner.View(), label, formatElapsed(timeNow().Sub(oldest.Started)))
+	if pending := m.countTurns(TurnPending); pending > 0 {
+		s += fmt.Sprintf(" · %d queued", pending)
+	}
+	return m.styles.HelpText.Render(s)
+}
+
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Truncate(100*time.Millisecond).Seconds())
+	}
+	return d.Truncate(time.Second).String()
+}
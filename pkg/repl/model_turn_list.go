@@ -0,0 +1,134 @@
+package repl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const turnListMaxVisible = 8
+
+// turnListModel is the state of the turn list overlay.
+type turnListModel struct {
+	visible  bool
+	selected int
+}
+
+func (m *Model) openTurnList() {
+	m.turnList = turnListModel{visible: true, selected: max(0, len(m.turns)-1)}
+	m.completion.visible = false
+}
+
+// handleTurnListInput owns navigation and cancel keys while the list is visible; other keys go
+// on to the input so typing ahead keeps working.
+func (m *Model) handleTurnListInput(k tea.KeyMsg) (bool, tea.Cmd) {
+	if !m.turnList.visible {
+		if key.Matches(k, m.keyMap.TurnList) {
+			m.openTurnList()
+			return true, nil
+		}
+		return false, nil
+	}
+
+	switch {
+	case key.Matches(k, m.keyMap.TurnList), k.Type == tea.KeyEsc:
+		m.turnList.visible = false
+	case k.Type == tea.KeyUp, k.Type == tea.KeyCtrlP:
+		if m.turnList.selected > 0 {
+			m.turnList.selected--
+		}
+	case k.Type == tea.KeyDown, k.Type == tea.KeyCtrlN:
+		if m.turnList.selected < len(m.turns)-1 {
+			m.turnList.selected++
+		}
+	case k.Type == tea.KeyDelete, k.Type == tea.KeyCtrlX:
+		if m.turnList.selected < len(m.turns) {
+			m.cancelTurn(m.turns[m.turnList.selected])
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *Model) turnStatusIcon(t *replTurn) string {
+	switch t.Status {
+	case TurnPending:
+		return m.styles.HelpText.Render("○")
+	case TurnRunning:
+		return m.spinner.View()
+	case TurnDone:
+		return m.styles.Info.Render("✓")
+	case TurnFailed:
+		return m.styles.Error.Render("✗")
+	default:
+		return m.styles.HelpText.Render("⏹")
+	}
+}
+
+func (m *Model) turnStatusLabel(t *replTurn) string {
+	switch t.Status {
+	case TurnRunning:
+		label := "running " + formatElapsed(timeNow().Sub(t.Started))
+		if t.cancelRequested {
+			label = "cancelling"
+		}
+		return label
+	case TurnDone, TurnFailed, TurnCancelled:
+		if !t.Started.IsZero() {
+			return fmt.Sprintf("%s %s", t.Status, formatElapsed(t.Finished.Sub(t.Started)))
+		}
+	}
+	return string(t.Status)
+}
+
+// renderTurnListPanel renders the turn list, oldest first, with the selection highlighted.
+func (m *Model) renderTurnListPanel(width int) string {
+	innerWidth := max(10, width-m.styles.CompletionPopup.GetHorizontalFrameSize())
+
+	start := 0
+	if m.turnList.selected >= turnListMaxVisible {
+		start = m.turnList.selected - turnListMaxVisible + 1
+	}
+	end := min(len(m.turns), start+turnListMaxVisible)
+
+	lines := make([]string, 0, end-start+1)
+	for i := start; i < end; i++ {
+		t := m.turns[i]
+		itemStyle := m.styles.CompletionItem
+		prefix := "  "
+		if i == m.turnList.selected {
+			itemStyle = m.styles.CompletionSelected
+			prefix = "> "
+		}
+		input := strings.SplitN(strings.TrimSpace(t.Input), "\n", 2)[0]
+		text := fmt.Sprintf("%-16s %s", m.turnStatusLabel(t), input)
+		rendered := itemStyle.Render(prefix) + m.turnStatusIcon(t) + itemStyle.Render(" "+text)
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(innerWidth).Render(rendered))
+	}
+	if len(m.turns) == 0 {
+		lines = append(lines, m.styles.HelpText.Render("  no turns yet"))
+	}
+	lines = append(lines, m.styles.HelpText.Render("↑/↓ select • del/ctrl+x cancel • esc close"))
+	return m.styles.CompletionPopup.Width(innerWidth).Render(strings.Join(lines, "\n"))
+}
+
+type turnListOverlayLayout struct {
+	PanelX int
+	PanelY int
+	View   string
+}
+
+// computeTurnListOverlayLayout anchors the panel right above the input line.
+func (m *Model) computeTurnListOverlayLayout(header, timelineView string) (turnListOverlayLayout, bool) {
+	if !m.turnList.visible || m.width <= 0 || m.height <= 0 {
+		return turnListOverlayLayout{}, false
+	}
+	view := m.renderTurnListPanel(min(m.width, 80))
+	inputY := lipgloss.Height(header) + 1 + lipgloss.Height(timelineView)
+	panelY := clampInt(inputY-lipgloss.Height(view), 0, max(0, m.height-lipgloss.Height(view)))
+	return turnListOverlayLayout{PanelX: 0, PanelY: panelY, View: view}, true
+}
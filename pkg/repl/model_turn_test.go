@@ -0,0 +1,169 @@
+package repl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/eventbus"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingEvaluator runs until its context is cancelled.
+type blockingEvaluator struct {
+	*ExampleEvaluator
+	started chan string
+}
+
+func newBlockingEvaluator() *blockingEvaluator {
+	return &blockingEvaluator{ExampleEvaluator: NewExampleEvaluator(), started: make(chan string, 8)}
+}
+
+func (b *blockingEvaluator) EvaluateStream(ctx context.Context, code string, _ func(Event)) error {
+	b.started <- code
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newTurnTestModel(t *testing.T, evaluator Evaluator, policy TurnPolicy) *Model {
+	t.Helper()
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+	cfg := DefaultConfig()
+	cfg.Autocomplete.Enabled = false
+	cfg.Turns.Policy = policy
+	return NewModel(evaluator, cfg, bus.Publisher)
+}
+
+// submitAndRun types input, presses enter and runs the evaluation command in the background.
+// It returns a channel receiving the evaluation's result message, or nil when nothing started.
+func submitAndRun(t *testing.T, m *Model, input string) chan tea.Msg {
+	t.Helper()
+	typeRunes(m, input)
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return runEvaluation(cmd)
+}
+
+func runEvaluation(cmd tea.Cmd) chan tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	done := make(chan tea.Msg, 1)
+	go func() {
+		msg := cmd()
+		// the first command of a batch is the evaluation, the second the spinner tick
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			msg = batch[0]()
+		}
+		done <- msg
+	}()
+	return done
+}
+
+func receive(t *testing.T, ch chan tea.Msg) tea.Msg {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("evaluation did not finish")
+	}
+	return nil
+}
+
+func TestCtrlCCancelsRunningEvaluationThenQuits(t *testing.T) {
+	evaluator := newBlockingEvaluator()
+	m := newTurnTestModel(t, evaluator, TurnPolicyQueue)
+
+	done := submitAndRun(t, m, "sleep")
+	require.NotNil(t, done)
+	require.True(t, m.Running())
+	require.Contains(t, m.runningIndicator(), "running")
+	<-evaluator.started
+
+	// first ctrl+c interrupts the turn instead of quitting
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	require.Nil(t, cmd)
+	require.Contains(t, m.runningIndicator(), "cancelling")
+
+	finished := receive(t, done)
+	require.True(t, finished.(evaluationFinishedMsg).interrupted)
+	_, _ = m.Update(finished)
+	require.False(t, m.Running())
+
+	// the next ctrl+c quits
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	require.NotNil(t, cmd)
+	require.IsType(t, tea.QuitMsg{}, cmd())
+	require.Equal(t, TurnCancelled, m.Turns()[0].Status)
+}
+
+func TestQueuedTurnsRunInOrderAndCanBeCancelled(t *testing.T) {
+	evaluator := newBlockingEvaluator()
+	m := newTurnTestModel(t, evaluator, TurnPolicyQueue)
+
+	first := submitAndRun(t, m, "one")
+	require.Nil(t, submitAndRun(t, m, "two"))
+	require.Nil(t, submitAndRun(t, m, "three"))
+	require.Equal(t, "one", <-evaluator.started)
+	require.Contains(t, m.runningIndicator(), "2 queued")
+
+	// cancel "two" from the turn list; the selection starts on the newest turn
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	require.True(t, m.turnList.visible)
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	require.Equal(t, []TurnStatus{TurnRunning, TurnCancelled, TurnPending}, turnStatuses(m))
+	require.Contains(t, m.renderTurnListPanel(80), "three")
+
+	// finishing "one" starts "three"
+	require.True(t, m.CancelTurn(m.Turns()[0].ID))
+	_, cmd := m.Update(receive(t, first))
+	third := runEvaluation(cmd)
+	require.Equal(t, "three", <-evaluator.started)
+	require.Equal(t, []TurnStatus{TurnCancelled, TurnCancelled, TurnRunning}, turnStatuses(m))
+
+	m.CancelEvaluation()
+	_, _ = m.Update(receive(t, third))
+	require.False(t, m.Running())
+}
+
+func TestRejectPolicyKeepsInputWhileRunning(t *testing.T) {
+	evaluator := newBlockingEvaluator()
+	m := newTurnTestModel(t, evaluator, TurnPolicyReject)
+
+	done := submitAndRun(t, m, "one")
+	<-evaluator.started
+	require.Nil(t, submitAndRun(t, m, "two"))
+	require.Equal(t, "two", m.textInput.Value())
+	require.Len(t, m.Turns(), 1)
+	require.True(t, m.helpBar.visible)
+
+	m.CancelEvaluation()
+	_, _ = m.Update(receive(t, done))
+}
+
+func TestConcurrentPolicyStartsTurnsImmediately(t *testing.T) {
+	evaluator := newBlockingEvaluator()
+	m := newTurnTestModel(t, evaluator, TurnPolicyConcurrent)
+
+	first := submitAndRun(t, m, "one")
+	second := submitAndRun(t, m, "two")
+	require.NotNil(t, second)
+	require.ElementsMatch(t, []string{"one", "two"}, []string{<-evaluator.started, <-evaluator.started})
+	require.Contains(t, m.runningIndicator(), "2 running")
+
+	m.CancelEvaluation()
+	_, _ = m.Update(receive(t, first))
+	_, _ = m.Update(receive(t, second))
+	require.False(t, m.Running())
+}
+
+func turnStatuses(m *Model) []TurnStatus {
+	var ret []TurnStatus
+	for _, t := range m.Turns() {
+		ret = append(ret, t.Status)
+	}
+	return ret
+}
@@ -0,0 +1,296 @@
+package repl
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// RemoteEvaluatorConfig describes the subprocess that implements the remote evaluator protocol.
+type RemoteEvaluatorConfig struct {
+	Command string
+	Args    []string
+	Dir     string
+	// Env is appended to the current environment
+	Env []string
+	// Stderr receives the subprocess' stderr; it is discarded when nil
+	Stderr io.Writer
+	// InitTimeout bounds the initialize handshake (default 10s)
+	InitTimeout time.Duration
+	// ShutdownTimeout is how long Close waits for the process before killing it (default 2s)
+	ShutdownTimeout time.Duration
+}
+
+// RemoteEvaluator is an Evaluator backed by a process speaking newline-delimited JSON-RPC 2.0
+// on stdin/stdout. It also implements InputCompleter, HelpBarProvider, HelpDrawerProvider,
+// InputCompletenessChecker and PaletteCommandProvider; methods the remote did not announce in its capabilities return empty
+// results without a round trip.
+type RemoteEvaluator struct {
+	client *rpcClient
+	info   RemoteInitializeResult
+	closer io.Closer
+
+	cmd             *exec.Cmd
+	shutdownTimeout time.Duration
+}
+
+var (
+	_ Evaluator                = (*RemoteEvaluator)(nil)
+	_ InputCompleter           = (*RemoteEvaluator)(nil)
+	_ HelpBarProvider          = (*RemoteEvaluator)(nil)
+	_ HelpDrawerProvider       = (*RemoteEvaluator)(nil)
+	_ PaletteCommandProvider   = (*RemoteEvaluator)(nil)
+	_ InputCompletenessChecker = (*RemoteEvaluator)(nil)
+)
+
+// NewRemoteEvaluator starts cfg.Command and performs the initialize handshake.
+func NewRemoteEvaluator(ctx context.Context, cfg RemoteEvaluatorConfig) (*RemoteEvaluator, error) {
+	if cfg.Command == "" {
+		return nil, errors.New("remote evaluator: command is required")
+	}
+	cmd := exec.Command(cfg.Command, cfg.Args...)
+	cmd.Dir = cfg.Dir
+	if len(cfg.Env) > 0 {
+		cmd.Env = append(cmd.Environ(), cfg.Env...)
+	}
+	cmd.Stderr = cfg.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "remote evaluator stdin")
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "remote evaluator stdout")
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "start remote evaluator %s", cfg.Command)
+	}
+
+	e, err := newRemoteEvaluator(ctx, stdout, stdin, cfg.InitTimeout)
+	if err != nil {
+		_ = stdin.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, err
+	}
+	e.cmd = cmd
+	e.shutdownTimeout = cfg.ShutdownTimeout
+	if e.shutdownTimeout <= 0 {
+		e.shutdownTimeout = 2 * time.Second
+	}
+	return e, nil
+}
+
+// NewRemoteEvaluatorFromConn runs the protocol over an existing connection, e.g. a socket.
+// Close closes w.
+func NewRemoteEvaluatorFromConn(ctx context.Context, r io.Reader, w io.WriteCloser) (*RemoteEvaluator, error) {
+	return newRemoteEvaluator(ctx, r, w, 0)
+}
+
+func newRemoteEvaluator(ctx context.Context, r io.Reader, w io.WriteCloser, initTimeout time.Duration) (*RemoteEvaluator, error) {
+	if initTimeout <= 0 {
+		initTimeout = 10 * time.Second
+	}
+	e := &RemoteEvaluator{client: newRPCClient(r, w), closer: w}
+	ictx, cancel := context.WithTimeout(ctx, initTimeout)
+	defer cancel()
+	params := map[string]any{"protocolVersion": RemoteProtocolVersion}
+	if err := e.client.call(ictx, RemoteMethodInitialize, params, &e.info, nil); err != nil {
+		return nil, errors.Wrap(err, "initialize remote evaluator")
+	}
+	log.Debug().Str("name", e.info.Name).Interface("capabilities", e.info.Capabilities).Msg("remote evaluator initialized")
+	return e, nil
+}
+
+// Info returns the initialize result announced by the remote.
+func (e *RemoteEvaluator) Info() RemoteInitializeResult { return e.info }
+
+// Close asks the remote to shut down, closes its stdin and waits for it to exit.
+func (e *RemoteEvaluator) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	_ = e.client.call(ctx, RemoteMethodShutdown, struct{}{}, nil, nil)
+	cancel()
+	err := e.closer.Close()
+	if e.cmd == nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- e.cmd.Wait() }()
+	select {
+	case werr := <-done:
+		return werr
+	case <-time.After(e.shutdownTimeout):
+		_ = e.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// EvaluateStream sends repl/evaluate and forwards repl/event notifications until the response.
+// A JSON-RPC error is returned as a terminal failure after being shown as stderr.
+func (e *RemoteEvaluator) EvaluateStream(ctx context.Context, code string, emit func(Event)) error {
+	err := e.client.call(ctx, RemoteMethodEvaluate, map[string]string{"code": code}, nil, emit)
+	var rerr *RemoteError
+	if errors.As(err, &rerr) {
+		emit(Event{Kind: EventStderr, Props: map[string]any{"text": rerr.Message, "is_error": true}})
+	}
+	return err
+}
+
+func (e *RemoteEvaluator) GetPrompt() string {
+	if e.info.Prompt == "" {
+		return "> "
+	}
+	return e.info.Prompt
+}
+
+func (e *RemoteEvaluator) GetName() string {
+	if e.info.Name == "" {
+		return "remote"
+	}
+	return e.info.Name
+}
+
+func (e *RemoteEvaluator) SupportsMultiline() bool { return e.info.Multiline }
+
+func (e *RemoteEvaluator) GetFileExtension() string {
+	if e.info.FileExtension == "" {
+		return ".txt"
+	}
+	return e.info.FileExtension
+}
+
+// CompleteInput implements InputCompleter.
+func (e *RemoteEvaluator) CompleteInput(ctx context.Context, req CompletionRequest) (CompletionResult, error) {
+	if !e.info.Capabilities.Completion {
+		return CompletionResult{}, nil
+	}
+	var res remoteCompletionResult
+	params := remoteRequestParams{Input: req.Input, CursorByte: req.CursorByte, Reason: string(req.Reason), Shortcut: req.Shortcut}
+	if err := e.client.call(ctx, RemoteMethodComplete, params, &res, nil); err != nil {
+		return CompletionResult{}, err
+	}
+	out := CompletionResult{ReplaceFrom: res.ReplaceFrom, ReplaceTo: res.ReplaceTo, Show: res.Show}
+	for _, s := range res.Suggestions {
+		id, display := s.ID, s.DisplayText
+		if id == "" {
+			id = s.Value
+		}
+		if display == "" {
+			display = s.Value
+		}
+		out.Suggestions = append(out.Suggestions, autocomplete.Suggestion{
+			Id:            id,
+			Value:         s.Value,
+			DisplayText:   display,
+			Kind:          autocomplete.SuggestionKind(s.Kind),
+			Icon:          s.Icon,
+			Detail:        s.Detail,
+			Documentation: s.Documentation,
+			Snippet:       s.Snippet,
+		})
+	}
+	return out, nil
+}
+
+// GetHelpBar implements HelpBarProvider.
+func (e *RemoteEvaluator) GetHelpBar(ctx context.Context, req HelpBarRequest) (HelpBarPayload, error) {
+	if !e.info.Capabilities.HelpBar {
+		return HelpBarPayload{}, nil
+	}
+	var res remoteHelpBarResult
+	params := remoteRequestParams{Input: req.Input, CursorByte: req.CursorByte, Reason: string(req.Reason), Shortcut: req.Shortcut}
+	if err := e.client.call(ctx, RemoteMethodHelpBar, params, &res, nil); err != nil {
+		return HelpBarPayload{}, err
+	}
+	return HelpBarPayload{Show: res.Show, Text: res.Text, Kind: res.Kind, Severity: res.Severity, Ephemeral: res.Ephemeral}, nil
+}
+
+// GetHelpDrawer implements HelpDrawerProvider.
+func (e *RemoteEvaluator) GetHelpDrawer(ctx context.Context, req HelpDrawerRequest) (HelpDrawerDocument, error) {
+	if !e.info.Capabilities.HelpDrawer {
+		return HelpDrawerDocument{}, nil
+	}
+	var res remoteHelpDrawerResult
+	params := remoteRequestParams{Input: req.Input, CursorByte: req.CursorByte, Reason: string(req.Trigger)}
+	if err := e.client.call(ctx, RemoteMethodHelpDrawer, params, &res, nil); err != nil {
+		return HelpDrawerDocument{}, err
+	}
+	return HelpDrawerDocument{
+		Show:        res.Show,
+		Title:       res.Title,
+		Subtitle:    res.Subtitle,
+		Markdown:    res.Markdown,
+		Diagnostics: res.Diagnostics,
+		VersionTag:  res.VersionTag,
+	}, nil
+}
+
+// CheckInputCompleteness implements InputCompletenessChecker. Without the capability it has no
+// opinion and the REPL falls back to its heuristic.
+func (e *RemoteEvaluator) CheckInputCompleteness(ctx context.Context, req InputCompletenessRequest) (InputCompletenessResult, error) {
+	if !e.info.Capabilities.IsComplete {
+		return InputCompletenessResult{}, nil
+	}
+	var res remoteIsCompleteResult
+	params := remoteRequestParams{Input: req.Input, CursorByte: req.CursorByte}
+	if err := e.client.call(ctx, RemoteMethodIsComplete, params, &res, nil); err != nil {
+		return InputCompletenessResult{}, err
+	}
+	return InputCompletenessResult{Status: InputCompleteness(res.Status), Indent: res.Indent}, nil
+}
+
+// ListPaletteCommands implements PaletteCommandProvider. Running a command calls
+// repl/executePaletteCommand; the result may replace the input line or submit code.
+func (e *RemoteEvaluator) ListPaletteCommands(ctx context.Context) ([]PaletteCommand, error) {
+	if !e.info.Capabilities.PaletteCommands {
+		return nil, nil
+	}
+	var res remotePaletteCommandsResult
+	if err := e.client.call(ctx, RemoteMethodPaletteCommands, struct{}{}, &res, nil); err != nil {
+		return nil, err
+	}
+	ret := make([]PaletteCommand, 0, len(res.Commands))
+	for _, rc := range res.Commands {
+		id := rc.ID
+		ret = append(ret, PaletteCommand{
+			ID:          id,
+			Name:        rc.Name,
+			Description: rc.Description,
+			Category:    rc.Category,
+			Keywords:    rc.Keywords,
+			Action: func(m *Model) tea.Cmd {
+				return e.executePaletteCommand(m, id)
+			},
+		})
+	}
+	return ret, nil
+}
+
+func (e *RemoteEvaluator) executePaletteCommand(m *Model, id string) tea.Cmd {
+	ctx := m.appContext()
+	return func() tea.Msg {
+		var res remoteExecuteResult
+		if err := e.client.call(ctx, RemoteMethodExecuteCommand, map[string]string{"id": id}, &res, nil); err != nil {
+			log.Warn().Err(err).Str("command", id).Msg("remote palette command failed")
+			return nil
+		}
+		if res.Evaluate != "" {
+			return remoteEvaluateMsg{code: res.Evaluate}
+		}
+		if res.Insert != nil {
+			return remoteInsertInputMsg{text: *res.Insert}
+		}
+		return nil
+	}
+}
+
+// remoteInsertInputMsg replaces the input line with text produced by a remote palette command.
+type remoteInsertInputMsg struct {
+	text string
+}
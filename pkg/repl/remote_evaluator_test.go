@@ -0,0 +1,177 @@
+package repl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemote serves the remote evaluator protocol from the other end of two pipes. It
+// runs in its own goroutine, so it reports problems with assert rather than require,
+// whose FailNow only stops the test from the test goroutine.
+type fakeRemote struct {
+	t   *testing.T
+	out io.Writer
+	mu  sync.Mutex
+
+	cancelled chan int64
+}
+
+func (f *fakeRemote) send(v any) {
+	b, err := json.Marshal(v)
+	if !assert.NoError(f.t, err) {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, _ = f.out.Write(append(b, '\n'))
+}
+
+func (f *fakeRemote) reply(id *int64, result any) {
+	f.send(map[string]any{"jsonrpc": "2.0", "id": *id, "result": result})
+}
+
+func (f *fakeRemote) serve(in io.Reader) {
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		var msg rpcMessage
+		if !assert.NoError(f.t, json.Unmarshal(sc.Bytes(), &msg)) {
+			return
+		}
+		var params map[string]any
+		_ = json.Unmarshal(msg.Params, &params)
+		switch msg.Method {
+		case RemoteMethodInitialize:
+			f.reply(msg.ID, RemoteInitializeResult{Name: "fake", Prompt: "fake> ", Capabilities: RemoteCapabilities{Completion: true, IsComplete: true}})
+		case RemoteMethodEvaluate:
+			code := params["code"].(string)
+			switch code {
+			case "fail":
+				f.send(map[string]any{"jsonrpc": "2.0", "id": *msg.ID, "error": map[string]any{"code": 1, "message": "boom"}})
+			case "hang":
+				// never answers; the client cancels
+			case "stream-hang":
+				f.send(map[string]any{"jsonrpc": "2.0", "method": RemoteMethodEvent, "params": map[string]any{"requestId": *msg.ID, "kind": EventStdout, "props": map[string]any{"append": "1"}}})
+			default:
+				f.send(map[string]any{"jsonrpc": "2.0", "method": RemoteMethodEvent, "params": map[string]any{"requestId": *msg.ID, "kind": EventStdout, "props": map[string]any{"append": "1"}}})
+				f.send(map[string]any{"jsonrpc": "2.0", "method": RemoteMethodEvent, "params": map[string]any{"requestId": *msg.ID, "kind": EventResultMarkdown, "props": map[string]any{"markdown": "=" + code}}})
+				f.reply(msg.ID, map[string]any{})
+			}
+		case RemoteMethodComplete:
+			f.reply(msg.ID, map[string]any{"suggestions": []map[string]any{{"value": "print", "kind": "function", "detail": "builtin"}}, "replaceFrom": 0, "replaceTo": 2, "show": true})
+		case RemoteMethodIsComplete:
+			f.reply(msg.ID, map[string]any{"status": "incomplete", "indent": "    "})
+		case RemoteMethodCancelRequest:
+			f.cancelled <- int64(params["id"].(float64))
+		}
+	}
+}
+
+func newFakeRemoteEvaluator(t *testing.T) (*RemoteEvaluator, *fakeRemote) {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	f := &fakeRemote{t: t, out: serverW, cancelled: make(chan int64, 1)}
+	go f.serve(serverR)
+	e, err := NewRemoteEvaluatorFromConn(context.Background(), clientR, clientW)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = clientW.Close()
+		_ = serverW.Close()
+	})
+	return e, f
+}
+
+func TestRemoteEvaluatorStreamsEvents(t *testing.T) {
+	e, _ := newFakeRemoteEvaluator(t)
+	require.Equal(t, "fake", e.GetName())
+	require.Equal(t, "fake> ", e.GetPrompt())
+
+	var events []Event
+	require.NoError(t, e.EvaluateStream(context.Background(), "1+1", func(ev Event) { events = append(events, ev) }))
+	require.Len(t, events, 2)
+	require.Equal(t, EventStdout, events[0].Kind)
+	require.Equal(t, EventResultMarkdown, events[1].Kind)
+	require.Equal(t, "=1+1", events[1].Props["markdown"])
+}
+
+func TestRemoteEvaluatorSurfacesErrors(t *testing.T) {
+	e, _ := newFakeRemoteEvaluator(t)
+	var events []Event
+	err := e.EvaluateStream(context.Background(), "fail", func(ev Event) { events = append(events, ev) })
+	var rerr *RemoteError
+	require.ErrorAs(t, err, &rerr)
+	require.Equal(t, "boom", rerr.Message)
+	require.Len(t, events, 1)
+	require.Equal(t, EventStderr, events[0].Kind)
+}
+
+func TestRemoteEvaluatorCancelSendsCancelRequest(t *testing.T) {
+	e, f := newFakeRemoteEvaluator(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := e.EvaluateStream(ctx, "hang", func(Event) {})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case id := <-f.cancelled:
+		require.NotZero(t, id)
+	case <-time.After(time.Second):
+		t.Fatal("no $/cancelRequest received")
+	}
+}
+
+func TestRemoteEvaluatorCancelWaitsForEventDelivery(t *testing.T) {
+	e, _ := newFakeRemoteEvaluator(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	delivering, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- e.EvaluateStream(ctx, "stream-hang", func(Event) {
+			close(delivering)
+			<-release
+		})
+	}()
+
+	<-delivering
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("EvaluateStream returned while an event was being delivered")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestRemoteEvaluatorCapabilities(t *testing.T) {
+	e, _ := newFakeRemoteEvaluator(t)
+	res, err := e.CompleteInput(context.Background(), CompletionRequest{Input: "pr", CursorByte: 2})
+	require.NoError(t, err)
+	require.True(t, res.Show)
+	require.Len(t, res.Suggestions, 1)
+	require.Equal(t, "print", res.Suggestions[0].DisplayText)
+	require.Equal(t, autocomplete.KindFunction, res.Suggestions[0].Kind)
+	require.Equal(t, "builtin", res.Suggestions[0].Detail)
+
+	// help bar was not announced, so no request is made
+	bar, err := e.GetHelpBar(context.Background(), HelpBarRequest{Input: "x"})
+	require.NoError(t, err)
+	require.False(t, bar.Show)
+}
+
+func TestRemoteEvaluatorIsComplete(t *testing.T) {
+	e, _ := newFakeRemoteEvaluator(t)
+	res, err := e.CheckInputCompleteness(context.Background(), InputCompletenessRequest{Input: "def f():"})
+	require.NoError(t, err)
+	require.Equal(t, InputIncomplete, res.Status)
+	require.Equal(t, "    ", res.Indent)
+}
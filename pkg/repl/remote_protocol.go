@@ -0,0 +1,311 @@
+package repl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// RemoteProtocolVersion is the version of the remote evaluator protocol sent in "initialize".
+const RemoteProtocolVersion = 1
+
+// JSON-RPC methods of the remote evaluator protocol. See docs/repl.md ("Remote evaluators").
+const (
+	RemoteMethodInitialize      = "initialize"
+	RemoteMethodEvaluate        = "repl/evaluate"
+	RemoteMethodEvent           = "repl/event" // notification server -> client
+	RemoteMethodComplete        = "repl/complete"
+	RemoteMethodHelpBar         = "repl/helpBar"
+	RemoteMethodHelpDrawer      = "repl/helpDrawer"
+	RemoteMethodIsComplete      = "repl/isComplete"
+	RemoteMethodPaletteCommands = "repl/paletteCommands"
+	RemoteMethodExecuteCommand  = "repl/executePaletteCommand"
+	RemoteMethodCancelRequest   = "$/cancelRequest" // notification client -> server
+	RemoteMethodShutdown        = "shutdown"
+)
+
+// RemoteInitializeResult describes the remote evaluator and the optional methods it implements.
+type RemoteInitializeResult struct {
+	Name          string             `json:"name"`
+	Prompt        string             `json:"prompt"`
+	Multiline     bool               `json:"multiline"`
+	FileExtension string             `json:"fileExtension"`
+	Capabilities  RemoteCapabilities `json:"capabilities"`
+}
+
+// RemoteCapabilities lists the optional provider methods a remote evaluator answers.
+type RemoteCapabilities struct {
+	Completion      bool `json:"completion"`
+	HelpBar         bool `json:"helpBar"`
+	HelpDrawer      bool `json:"helpDrawer"`
+	PaletteCommands bool `json:"paletteCommands"`
+	IsComplete      bool `json:"isComplete"`
+}
+
+type remoteEventParams struct {
+	// RequestID is the id of the repl/evaluate request the event belongs to
+	RequestID int64          `json:"requestId"`
+	Kind      EventKind      `json:"kind"`
+	Props     map[string]any `json:"props,omitempty"`
+}
+
+type remoteRequestParams struct {
+	Input      string `json:"input"`
+	CursorByte int    `json:"cursorByte"`
+	Reason     string `json:"reason,omitempty"`
+	Shortcut   string `json:"shortcut,omitempty"`
+}
+
+type remoteSuggestion struct {
+	ID            string `json:"id"`
+	Value         string `json:"value"`
+	DisplayText   string `json:"displayText"`
+	Kind          string `json:"kind,omitempty"`
+	Icon          string `json:"icon,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	Documentation string `json:"documentation,omitempty"`
+	Snippet       bool   `json:"snippet,omitempty"`
+}
+
+type remoteCompletionResult struct {
+	Suggestions []remoteSuggestion `json:"suggestions"`
+	ReplaceFrom int                `json:"replaceFrom"`
+	ReplaceTo   int                `json:"replaceTo"`
+	Show        bool               `json:"show"`
+}
+
+type remoteHelpBarResult struct {
+	Show      bool   `json:"show"`
+	Text      string `json:"text"`
+	Kind      string `json:"kind"`
+	Severity  string `json:"severity"`
+	Ephemeral bool   `json:"ephemeral"`
+}
+
+type remoteHelpDrawerResult struct {
+	Show        bool     `json:"show"`
+	Title       string   `json:"title"`
+	Subtitle    string   `json:"subtitle"`
+	Markdown    string   `json:"markdown"`
+	Diagnostics []string `json:"diagnostics"`
+	VersionTag  string   `json:"versionTag"`
+}
+
+type remoteIsCompleteResult struct {
+	Status string `json:"status"`
+	Indent string `json:"indent"`
+}
+
+type remotePaletteCommand struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Category    string   `json:"category"`
+	Keywords    []string `json:"keywords"`
+}
+
+type remotePaletteCommandsResult struct {
+	Commands []remotePaletteCommand `json:"commands"`
+}
+
+// remoteExecuteResult tells the REPL what to do after a palette command ran remotely.
+type remoteExecuteResult struct {
+	// Insert replaces the input line
+	Insert *string `json:"insert,omitempty"`
+	// Evaluate is submitted as if the user had typed it
+	Evaluate string `json:"evaluate,omitempty"`
+}
+
+// RemoteError is a JSON-RPC error returned by a remote evaluator.
+type RemoteError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("remote error %d: %s", e.Code, e.Message)
+}
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RemoteError    `json:"error,omitempty"`
+}
+
+type rpcPending struct {
+	done    chan rpcMessage
+	onEvent func(Event)
+
+	// emu orders event delivery with close, so no event arrives after call returned
+	emu    sync.Mutex
+	closed bool
+}
+
+func (pc *rpcPending) deliver(ev Event) {
+	pc.emu.Lock()
+	defer pc.emu.Unlock()
+	if !pc.closed && pc.onEvent != nil {
+		pc.onEvent(ev)
+	}
+}
+
+// close stops event delivery, waiting for an event being delivered.
+func (pc *rpcPending) close() {
+	pc.emu.Lock()
+	pc.closed = true
+	pc.emu.Unlock()
+}
+
+// rpcClient speaks newline-delimited JSON-RPC 2.0 over a reader/writer pair.
+type rpcClient struct {
+	w      io.Writer
+	wmu    sync.Mutex
+	nextID atomic.Int64
+
+	mu      sync.Mutex
+	pending map[int64]*rpcPending
+	closed  error
+}
+
+func newRPCClient(r io.Reader, w io.Writer) *rpcClient {
+	c := &rpcClient{w: w, pending: map[int64]*rpcPending{}}
+	go c.readLoop(r)
+	return c
+}
+
+func (c *rpcClient) readLoop(r io.Reader) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var msg rpcMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			log.Warn().Err(err).Str("line", string(line)).Msg("remote evaluator: ignoring malformed message")
+			continue
+		}
+		c.dispatch(msg)
+	}
+	err := sc.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	c.fail(errors.Wrap(err, "remote evaluator connection closed"))
+}
+
+func (c *rpcClient) dispatch(msg rpcMessage) {
+	if msg.Method == RemoteMethodEvent {
+		var p remoteEventParams
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			log.Warn().Err(err).Msg("remote evaluator: bad event params")
+			return
+		}
+		c.mu.Lock()
+		pc := c.pending[p.RequestID]
+		c.mu.Unlock()
+		if pc != nil {
+			pc.deliver(Event{Kind: p.Kind, Props: p.Props})
+		}
+		return
+	}
+	if msg.ID == nil {
+		log.Debug().Str("method", msg.Method).Msg("remote evaluator: ignoring notification")
+		return
+	}
+	c.mu.Lock()
+	pc := c.pending[*msg.ID]
+	delete(c.pending, *msg.ID)
+	c.mu.Unlock()
+	if pc != nil {
+		pc.done <- msg
+	}
+}
+
+func (c *rpcClient) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = err
+	for id, pc := range c.pending {
+		pc.done <- rpcMessage{Error: &RemoteError{Code: -32000, Message: err.Error()}}
+		delete(c.pending, id)
+	}
+}
+
+func (c *rpcClient) write(msg rpcMessage) error {
+	msg.JSONRPC = "2.0"
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "marshal rpc message")
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, err = c.w.Write(append(b, '\n'))
+	return errors.Wrap(err, "write rpc message")
+}
+
+func (c *rpcClient) notify(method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return errors.Wrap(err, "marshal params")
+	}
+	return c.write(rpcMessage{Method: method, Params: raw})
+}
+
+// call sends a request and waits for its response. Events tagged with the request id are passed
+// to onEvent before call returns, and never after. Cancelling ctx sends $/cancelRequest and
+// returns ctx.Err().
+func (c *rpcClient) call(ctx context.Context, method string, params any, result any, onEvent func(Event)) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return errors.Wrap(err, "marshal params")
+	}
+	id := c.nextID.Add(1)
+	pc := &rpcPending{done: make(chan rpcMessage, 1), onEvent: onEvent}
+	defer pc.close()
+
+	c.mu.Lock()
+	if c.closed != nil {
+		err := c.closed
+		c.mu.Unlock()
+		return err
+	}
+	c.pending[id] = pc
+	c.mu.Unlock()
+
+	if err := c.write(rpcMessage{ID: &id, Method: method, Params: raw}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		_ = c.notify(RemoteMethodCancelRequest, map[string]int64{"id": id})
+		return ctx.Err()
+	case msg := <-pc.done:
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		return errors.Wrapf(json.Unmarshal(msg.Result, result), "decode %s result", method)
+	}
+}
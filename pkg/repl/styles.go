@@ -1,7 +1,10 @@
 package repl
 
 import (
+	"reflect"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
 )
 
 // Styles defines the visual styling for the REPL
@@ -15,6 +18,22 @@ type Styles struct {
 	CompletionPopup    lipgloss.Style
 	CompletionItem     lipgloss.Style
 	CompletionSelected lipgloss.Style
+	// CompletionIcon and CompletionDetail style the kind icon and detail column of suggestions;
+	// CompletionDoc styles the documentation pane (unset falls back to CompletionPopup).
+	CompletionIcon   lipgloss.Style
+	CompletionDetail lipgloss.Style
+	CompletionDoc    lipgloss.Style
+	// CompletionMatch highlights the runes matched by client-side completion filtering.
+	CompletionMatch lipgloss.Style
+	// InlineSuggestion styles the suggestion shown after the cursor (unset renders it faint).
+	InlineSuggestion lipgloss.Style
+	// HistoryMatch highlights the matched query in reverse history search results.
+	HistoryMatch lipgloss.Style
+	// HelpDrawer, HelpDrawerTitle and HelpDrawerSubtitle style the help drawer panel
+	// (unset keeps the panel's own styles).
+	HelpDrawer         lipgloss.Style
+	HelpDrawerTitle    lipgloss.Style
+	HelpDrawerSubtitle lipgloss.Style
 }
 
 // DefaultStyles returns the default styling configuration
@@ -53,7 +72,83 @@ func DefaultStyles() Styles {
 		CompletionSelected: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("33")).
 			Bold(true),
+		CompletionIcon: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("141")),
+		CompletionDetail: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("242")).
+			Italic(true),
+		CompletionMatch: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Bold(true),
+		InlineSuggestion: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("242")),
+		HistoryMatch: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Underline(true),
+	}
+}
+
+// StylesFromTheme returns the REPL styles for a theme palette.
+func StylesFromTheme(t theme.Theme) Styles {
+	p := t.Palette
+	popup := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(p.Border).
+		Padding(0, 1)
+	return Styles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Accent).
+			Background(p.Surface).
+			Padding(0, 1),
+		Prompt: lipgloss.NewStyle().
+			Foreground(p.Accent).
+			Bold(true),
+		Result: lipgloss.NewStyle().
+			Foreground(p.Foreground),
+		Error: lipgloss.NewStyle().
+			Foreground(p.Error).
+			Bold(true),
+		Info: lipgloss.NewStyle().
+			Foreground(p.Highlight).
+			Italic(true),
+		HelpText: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Italic(true),
+		CompletionPopup: popup,
+		CompletionItem: lipgloss.NewStyle().
+			Foreground(p.Foreground),
+		CompletionSelected: lipgloss.NewStyle().
+			Foreground(p.Accent).
+			Bold(true),
+		CompletionIcon: lipgloss.NewStyle().
+			Foreground(p.Secondary),
+		CompletionDetail: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Italic(true),
+		CompletionMatch: lipgloss.NewStyle().
+			Foreground(p.Highlight).
+			Bold(true),
+		InlineSuggestion: lipgloss.NewStyle().
+			Foreground(p.Muted),
+		HistoryMatch: lipgloss.NewStyle().
+			Foreground(p.Highlight).
+			Underline(true),
+		HelpDrawer: popup,
+		HelpDrawerTitle: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Accent),
+		HelpDrawerSubtitle: lipgloss.NewStyle().
+			Foreground(p.Muted),
+	}
+}
+
+// optionalStyle returns nil for an unset style.
+func optionalStyle(s lipgloss.Style) *lipgloss.Style {
+	if reflect.DeepEqual(s, lipgloss.Style{}) {
+		return nil
 	}
+	return &s
 }
 
 // Theme represents a color theme for the REPL
@@ -104,6 +199,9 @@ var BuiltinThemes = map[string]Theme{
 			CompletionSelected: lipgloss.NewStyle().
 				Foreground(lipgloss.Color("11")).
 				Bold(true),
+			HistoryMatch: lipgloss.NewStyle().
+				Foreground(lipgloss.Color("3")).
+				Underline(true),
 		},
 	},
 	"light": {
@@ -142,6 +240,9 @@ var BuiltinThemes = map[string]Theme{
 			CompletionSelected: lipgloss.NewStyle().
 				Foreground(lipgloss.Color("4")).
 				Bold(true),
+			HistoryMatch: lipgloss.NewStyle().
+				Foreground(lipgloss.Color("130")).
+				Underline(true),
 		},
 	},
 }
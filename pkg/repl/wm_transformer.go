@@ -32,6 +32,8 @@ func RegisterReplToTimelineTransformer(bus *eventbus.Bus) {
 	type turnState struct {
 		stdout, stderr bool
 		seq            int
+		// open maps a streamed entity key (progress/shell command) to its ID until completed
+		open map[string]timeline.EntityID
 	}
 	byTurn := map[string]*turnState{}
 
@@ -41,6 +43,57 @@ func RegisterReplToTimelineTransformer(bus *eventbus.Bus) {
 		return bus.Publisher.Publish(eventbus.TopicUIEntities, message.NewMessage(watermill.NewUUID(), env))
 	}
 
+	nextLocal := func(st *turnState, prefix string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		st.seq++
+		return fmt.Sprintf("%s-%d", prefix, st.seq)
+	}
+
+	// oneShot creates a completed entity for events that carry their full state at once.
+	oneShot := func(turnID string, st *turnState, prefix, kind string, props map[string]any) error {
+		c := timeline.UIEntityCreated{ID: timeline.EntityID{TurnID: turnID, LocalID: nextLocal(st, prefix), Kind: kind}, Renderer: timeline.RendererDescriptor{Kind: kind}, Props: props, StartedAt: time.Now()}
+		if err := publish("timeline.created", c); err != nil {
+			return err
+		}
+		return publish("timeline.completed", timeline.UIEntityCompleted{ID: c.ID, Result: nil})
+	}
+
+	// streamed creates an entity on the first event for a key (props["id"], default prefix) and
+	// updates it on subsequent events until terminal is true, which completes it.
+	streamed := func(turnID string, st *turnState, prefix, kind string, props map[string]any, terminal bool) error {
+		key := prefix
+		if v, ok := props["id"]; ok {
+			key = fmt.Sprintf("%s:%v", prefix, v)
+		}
+		mu.Lock()
+		id, exists := st.open[key]
+		mu.Unlock()
+		if exists {
+			u := timeline.UIEntityUpdated{ID: id, Patch: props, Version: time.Now().UnixNano(), UpdatedAt: time.Now()}
+			if err := publish("timeline.updated", u); err != nil {
+				return err
+			}
+		} else {
+			id = timeline.EntityID{TurnID: turnID, LocalID: nextLocal(st, prefix), Kind: kind}
+			c := timeline.UIEntityCreated{ID: id, Renderer: timeline.RendererDescriptor{Kind: kind}, Props: props, StartedAt: time.Now()}
+			if err := publish("timeline.created", c); err != nil {
+				return err
+			}
+		}
+		mu.Lock()
+		if terminal {
+			delete(st.open, key)
+		} else {
+			st.open[key] = id
+		}
+		mu.Unlock()
+		if terminal {
+			return publish("timeline.completed", timeline.UIEntityCompleted{ID: id, Result: nil})
+		}
+		return nil
+	}
+
 	bus.AddHandler("repl-to-ui", eventbus.TopicReplEvents, func(msg *message.Message) error {
 		defer msg.Ack()
 		var in replEventMsg
@@ -51,12 +104,11 @@ func RegisterReplToTimelineTransformer(bus *eventbus.Bus) {
 		mu.Lock()
 		st := byTurn[turnID]
 		if st == nil {
-			st = &turnState{}
+			st = &turnState{open: map[string]timeline.EntityID{}}
 			byTurn[turnID] = st
 		}
 		mu.Unlock()
 
-		//nolint:exhaustive
 		switch in.Event.Kind {
 		case EventInput:
 			e := timeline.UIEntityCreated{ID: timeline.EntityID{TurnID: turnID, LocalID: "input", Kind: "markdown"}, Renderer: timeline.RendererDescriptor{Kind: "markdown"}, Props: in.Event.Props, StartedAt: time.Now()}
@@ -133,6 +185,47 @@ func RegisterReplToTimelineTransformer(bus *eventbus.Bus) {
 				return err
 			}
 			return publish("timeline.completed", timeline.UIEntityCompleted{ID: c.ID, Result: nil})
+		case EventTable:
+			return oneShot(turnID, st, "table", "table", in.Event.Props)
+		case EventDiff:
+			return oneShot(turnID, st, "diff", "diff", in.Event.Props)
+		case EventPerf:
+			return oneShot(turnID, st, "perf", "perf", in.Event.Props)
+		case EventToolCalls:
+			return oneShot(turnID, st, "tools", "tool_calls_panel", in.Event.Props)
+		case EventProgress:
+			done, _ := in.Event.Props["done"].(bool)
+			errText, _ := in.Event.Props["error"].(string)
+			return streamed(turnID, st, "progress", "progress", in.Event.Props, done || errText != "")
+		case EventShellCmd:
+			_, exited := in.Event.Props["exit_code"]
+			return streamed(turnID, st, "shell", "shell_cmd", in.Event.Props, exited)
+		case EventInterrupted:
+			// complete whatever the turn left streaming, then record the interruption itself
+			mu.Lock()
+			var pending []timeline.EntityID
+			if st.stdout {
+				pending = append(pending, timeline.EntityID{TurnID: turnID, LocalID: "stdout", Kind: "text"})
+			}
+			if st.stderr {
+				pending = append(pending, timeline.EntityID{TurnID: turnID, LocalID: "stderr", Kind: "text"})
+			}
+			for key, id := range st.open {
+				pending = append(pending, id)
+				delete(st.open, key)
+			}
+			mu.Unlock()
+			for _, id := range pending {
+				result := map[string]any{"streaming": false, "interrupted": true}
+				if err := publish("timeline.completed", timeline.UIEntityCompleted{ID: id, Result: result}); err != nil {
+					return err
+				}
+			}
+			props := map[string]any{"is_error": true, "interrupted": true}
+			for k, v := range in.Event.Props {
+				props[k] = v
+			}
+			return oneShot(turnID, st, "interrupted", "text", props)
 		default:
 			mu.Lock()
 			st.seq++
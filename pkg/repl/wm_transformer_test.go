@@ -0,0 +1,121 @@
+package repl
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/go-go-golems/bobatea/pkg/eventbus"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedEnvelope struct {
+	typ string
+	id  timeline.EntityID
+}
+
+type transformerStep struct {
+	event Event
+	// envelopes is the number of ui.entities messages the event is expected to produce
+	envelopes int
+}
+
+// runTransformer publishes events one at a time, waiting for each to be transformed, because the
+// in-memory bus does not guarantee ordering between messages.
+func runTransformer(t *testing.T, steps []transformerStep) []recordedEnvelope {
+	t.Helper()
+	bus, err := eventbus.NewInMemoryBus()
+	require.NoError(t, err)
+	RegisterReplToTimelineTransformer(bus)
+
+	var mu sync.Mutex
+	var got []recordedEnvelope
+	bus.AddHandler("collect", eventbus.TopicUIEntities, func(msg *message.Message) error {
+		defer msg.Ack()
+		var env timeline.Envelope
+		require.NoError(t, json.Unmarshal(msg.Payload, &env))
+		var p struct {
+			ID timeline.EntityID `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(env.Payload, &p))
+		mu.Lock()
+		got = append(got, recordedEnvelope{typ: env.Type, id: p.ID})
+		mu.Unlock()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = bus.Run(ctx) }()
+	<-bus.Router.Running()
+
+	want := 0
+	for _, st := range steps {
+		payload, _ := json.Marshal(replEventMsg{TurnID: "t1", Event: st.event, Time: time.Now()})
+		require.NoError(t, bus.Publisher.Publish(eventbus.TopicReplEvents, message.NewMessage(watermill.NewUUID(), payload)))
+		want += st.envelopes
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(got) >= want
+		}, time.Second, time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]recordedEnvelope(nil), got...)
+}
+
+func TestTransformerMapsEveryDeclaredKind(t *testing.T) {
+	steps := []transformerStep{
+		{Event{Kind: EventTable, Props: map[string]any{"columns": []string{"a"}, "rows": [][]any{{1}}}}, 2},
+		{Event{Kind: EventDiff, Props: map[string]any{"diff": "+x"}}, 2},
+		{Event{Kind: EventPerf, Props: map[string]any{"phases": map[string]any{"parse": 1}}}, 2},
+		{Event{Kind: EventToolCalls, Props: map[string]any{"calls": []any{}}}, 2},
+		{Event{Kind: EventProgress, Props: map[string]any{"percent": 10}}, 1},
+		{Event{Kind: EventProgress, Props: map[string]any{"percent": 100, "done": true}}, 2},
+		{Event{Kind: EventShellCmd, Props: map[string]any{"command": "ls"}}, 1},
+		{Event{Kind: EventShellCmd, Props: map[string]any{"exit_code": 0}}, 2},
+	}
+	got := runTransformer(t, steps)
+	kinds := map[string][]string{}
+	locals := map[string]map[string]bool{}
+	for _, e := range got {
+		kinds[e.id.Kind] = append(kinds[e.id.Kind], e.typ)
+		if locals[e.id.Kind] == nil {
+			locals[e.id.Kind] = map[string]bool{}
+		}
+		locals[e.id.Kind][e.id.LocalID] = true
+	}
+	for _, k := range []string{"table", "diff", "perf", "tool_calls_panel"} {
+		require.ElementsMatch(t, []string{timeline.EnvelopeCreated, timeline.EnvelopeCompleted}, kinds[k], k)
+	}
+	// streamed kinds reuse one entity until a terminal event arrives
+	for _, k := range []string{"progress", "shell_cmd"} {
+		require.ElementsMatch(t, []string{timeline.EnvelopeCreated, timeline.EnvelopeUpdated, timeline.EnvelopeCompleted}, kinds[k], k)
+		require.Len(t, locals[k], 1, k)
+	}
+}
+
+func TestTransformerCompletesStreamingEntitiesOnInterrupt(t *testing.T) {
+	steps := []transformerStep{
+		{Event{Kind: EventStdout, Props: map[string]any{"append": "x"}}, 2},
+		{Event{Kind: EventProgress, Props: map[string]any{"percent": 10}}, 1},
+		// completes stdout and progress, then adds the interruption notice
+		{Event{Kind: EventInterrupted, Props: map[string]any{"text": "interrupted after 1.0s"}}, 4},
+	}
+	got := runTransformer(t, steps)
+	completed := map[string]bool{}
+	for _, e := range got {
+		if e.typ == timeline.EnvelopeCompleted {
+			completed[e.id.LocalID] = true
+		}
+	}
+	require.True(t, completed["stdout"])
+	require.True(t, completed["progress-1"])
+	require.True(t, completed["interrupted-2"])
+}
@@ -215,6 +215,9 @@ type Model struct {
 	// promptWidth is the width of the prompt.
 	promptWidth int
 
+	// If highlighter is set, it styles ranges of each logical line.
+	highlighter func(row int, line []rune) []Highlight
+
 	// width is the maximum number of characters that can be displayed at once.
 	// If 0 or less this setting is ignored.
 	width int
@@ -534,6 +537,18 @@ func (m *Model) SetCursor(col int) {
 	m.lastCharOffset = 0
 }
 
+// Column returns the cursor column (rune index) within the current line.
+func (m Model) Column() int {
+	return m.col
+}
+
+// SetCursorPosition moves the cursor to the given line and column (rune
+// index). Out of bounds values are clamped.
+func (m *Model) SetCursorPosition(row, col int) {
+	m.row = clamp(row, 0, len(m.value)-1)
+	m.SetCursor(col)
+}
+
 // CursorStart moves the cursor to the start of the input field.
 func (m *Model) CursorStart() {
 	m.SetCursor(0)
@@ -840,6 +855,27 @@ func (m *Model) repositionView() {
 	}
 }
 
+// VisualLineCount returns the number of rows the value occupies once soft
+// wrapped at the current width.
+func (m Model) VisualLineCount() int {
+	n := 0
+	for _, line := range m.value {
+		n += len(m.memoizedWrap(line, m.width))
+	}
+	return n
+}
+
+// CursorVisualRow returns the row of the cursor within the rendered view,
+// accounting for soft wrapping and vertical scrolling.
+func (m Model) CursorVisualRow() int {
+	return m.cursorLineNumber() - m.viewport.YOffset
+}
+
+// PromptWidth returns the number of columns reserved for the prompt.
+func (m Model) PromptWidth() int {
+	return m.promptWidth
+}
+
 // Width returns the width of the textarea.
 func (m Model) Width() int {
 	return m.width
@@ -905,6 +941,57 @@ func (m *Model) SetPromptFunc(promptWidth int, fn func(lineIdx int) string) {
 	m.promptWidth = promptWidth
 }
 
+// Highlight styles the runes [Start, End) of a logical line.
+type Highlight struct {
+	Start, End int
+	Style      lipgloss.Style
+}
+
+// SetHighlighter installs a function returning the highlights of a logical line, e.g. for
+// syntax highlighting. Highlighted runes inherit unset properties from the line style. Pass
+// nil to disable.
+func (m *Model) SetHighlighter(fn func(row int, line []rune) []Highlight) {
+	m.highlighter = fn
+}
+
+// renderRunes renders runes starting at column col of a logical line, applying highlights on top
+// of the line style.
+func (m Model) renderRunes(style lipgloss.Style, runes []rune, col int, highlights []Highlight) string {
+	if len(highlights) == 0 {
+		return style.Render(string(runes))
+	}
+	var s strings.Builder
+	runStart, runHighlight := 0, -2
+	flush := func(end int) {
+		if end <= runStart {
+			return
+		}
+		st := style
+		if runHighlight >= 0 {
+			st = highlights[runHighlight].Style.Inherit(style)
+		}
+		s.WriteString(st.Render(string(runes[runStart:end])))
+	}
+	for i := range runes {
+		h := highlightAt(highlights, col+i)
+		if h != runHighlight {
+			flush(i)
+			runStart, runHighlight = i, h
+		}
+	}
+	flush(len(runes))
+	return s.String()
+}
+
+func highlightAt(highlights []Highlight, col int) int {
+	for i, h := range highlights {
+		if col >= h.Start && col < h.End {
+			return i
+		}
+	}
+	return -1
+}
+
 // Height returns the current height of the textarea.
 func (m Model) Height() int {
 	return m.height
@@ -1074,6 +1161,11 @@ func (m Model) View() string {
 	displayLine := 0
 	for l, line := range m.value {
 		wrappedLines := m.memoizedWrap(line, m.width)
+		var highlights []Highlight
+		if m.highlighter != nil {
+			highlights = m.highlighter(l, line)
+		}
+		col := 0
 
 		if m.row == l {
 			style = m.style.CursorLine
@@ -1099,6 +1191,7 @@ func (m Model) View() string {
 				}
 			}
 
+			wrappedLen := len(wrappedLine)
 			strwidth := rw.StringWidth(string(wrappedLine))
 			padding := m.width - strwidth
 			// If the trailing space causes the line to be wider than the
@@ -1113,18 +1206,19 @@ func (m Model) View() string {
 				padding -= m.width - strwidth
 			}
 			if m.row == l && lineInfo.RowOffset == wl {
-				s.WriteString(style.Render(string(wrappedLine[:lineInfo.ColumnOffset])))
+				s.WriteString(m.renderRunes(style, wrappedLine[:lineInfo.ColumnOffset], col, highlights))
 				if m.col >= len(line) && lineInfo.CharOffset >= m.width {
 					m.Cursor.SetChar(" ")
 					s.WriteString(m.Cursor.View())
 				} else {
 					m.Cursor.SetChar(string(wrappedLine[lineInfo.ColumnOffset]))
 					s.WriteString(style.Render(m.Cursor.View()))
-					s.WriteString(style.Render(string(wrappedLine[lineInfo.ColumnOffset+1:])))
+					s.WriteString(m.renderRunes(style, wrappedLine[lineInfo.ColumnOffset+1:], col+lineInfo.ColumnOffset+1, highlights))
 				}
 			} else {
-				s.WriteString(style.Render(string(wrappedLine)))
+				s.WriteString(m.renderRunes(style, wrappedLine, col, highlights))
 			}
+			col += wrappedLen
 			s.WriteString(style.Render(strings.Repeat(" ", max(0, padding))))
 			s.WriteRune('\n')
 			newLines++
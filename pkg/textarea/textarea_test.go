@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestNew(t *testing.T) {
@@ -428,6 +429,28 @@ func TestRendersEndOfLineBuffer(t *testing.T) {
 	}
 }
 
+func TestHighlighterKeepsText(t *testing.T) {
+	textarea := newTextArea()
+	textarea.SetValue("let x = 1\nx + 2")
+
+	var rows []int
+	textarea.SetHighlighter(func(row int, line []rune) []Highlight {
+		rows = append(rows, row)
+		return []Highlight{{Start: 0, End: 3, Style: lipgloss.NewStyle().Bold(true)}}
+	})
+
+	view := textarea.View()
+	for _, want := range []string{"let x = 1", "x + 2"} {
+		if !strings.Contains(view, want) {
+			t.Log(view)
+			t.Errorf("Text area did not render highlighted line %q", want)
+		}
+	}
+	if len(rows) != 2 || rows[0] != 0 || rows[1] != 1 {
+		t.Errorf("Highlighter called for rows %v, expected [0 1]", rows)
+	}
+}
+
 func newTextArea() Model {
 	textarea := New()
 
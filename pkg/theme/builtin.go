@@ -0,0 +1,103 @@
+package theme
+
+// Names of the built-in themes.
+const (
+	Dark           = "dark"
+	Light          = "light"
+	HighContrast   = "high-contrast"
+	Solarized      = "solarized"
+	SolarizedLight = "solarized-light"
+)
+
+func builtinThemes() []Theme {
+	return []Theme{
+		{
+			Name: Dark,
+			Dark: true,
+			Palette: Palette{
+				Foreground:          "252",
+				Muted:               "243",
+				Border:              "240",
+				Surface:             "235",
+				Accent:              "33",
+				Secondary:           "212",
+				Highlight:           "214",
+				Selection:           "62",
+				SelectionForeground: "230",
+				Success:             "42",
+				Warning:             "214",
+				Error:               "196",
+			},
+		},
+		{
+			Name: Light,
+			Palette: Palette{
+				Foreground:          "235",
+				Muted:               "244",
+				Border:              "250",
+				Surface:             "254",
+				Accent:              "25",
+				Secondary:           "127",
+				Highlight:           "130",
+				Selection:           "153",
+				SelectionForeground: "16",
+				Success:             "28",
+				Warning:             "130",
+				Error:               "160",
+			},
+		},
+		{
+			Name: HighContrast,
+			Dark: true,
+			Palette: Palette{
+				Foreground:          "15",
+				Muted:               "252",
+				Border:              "15",
+				Surface:             "0",
+				Accent:              "14",
+				Secondary:           "13",
+				Highlight:           "11",
+				Selection:           "15",
+				SelectionForeground: "0",
+				Success:             "10",
+				Warning:             "11",
+				Error:               "9",
+			},
+		},
+		{
+			Name: Solarized,
+			Dark: true,
+			Palette: Palette{
+				Foreground:          "#839496",
+				Muted:               "#586e75",
+				Border:              "#586e75",
+				Surface:             "#073642",
+				Accent:              "#268bd2",
+				Secondary:           "#d33682",
+				Highlight:           "#b58900",
+				Selection:           "#268bd2",
+				SelectionForeground: "#fdf6e3",
+				Success:             "#859900",
+				Warning:             "#cb4b16",
+				Error:               "#dc322f",
+			},
+		},
+		{
+			Name: SolarizedLight,
+			Palette: Palette{
+				Foreground:          "#657b83",
+				Muted:               "#93a1a1",
+				Border:              "#93a1a1",
+				Surface:             "#eee8d5",
+				Accent:              "#268bd2",
+				Secondary:           "#d33682",
+				Highlight:           "#b58900",
+				Selection:           "#268bd2",
+				SelectionForeground: "#fdf6e3",
+				Success:             "#859900",
+				Warning:             "#cb4b16",
+				Error:               "#dc322f",
+			},
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package theme
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pkg/errors"
+)
+
+// EnvVar names the environment variable that overrides detection.
+const EnvVar = "BOBATEA_THEME"
+
+// Auto is the theme name that selects Detect.
+const Auto = "auto"
+
+// hasDarkBackground is replaced in tests.
+var hasDarkBackground = lipgloss.HasDarkBackground
+
+// Detect picks a theme for the terminal: the registered theme named by
+// $BOBATEA_THEME if set, otherwise dark or light depending on the terminal
+// background.
+func Detect() Theme {
+	if name := strings.TrimSpace(os.Getenv(EnvVar)); name != "" && normalizeName(name) != Auto {
+		if t, ok := Get(name); ok {
+			return t
+		}
+	}
+	name := Light
+	if hasDarkBackground() {
+		name = Dark
+	}
+	t, _ := Get(name)
+	return t
+}
+
+// Resolve returns the theme called name, or the detected one for "auto".
+func Resolve(name string) (Theme, error) {
+	if normalizeName(name) == Auto {
+		return Detect(), nil
+	}
+	t, ok := Get(name)
+	if !ok {
+		return Theme{}, errors.Errorf("unknown theme %s (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return t, nil
+}
@@ -0,0 +1,91 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// file is the YAML form of a theme:
+//
+//	name: midnight
+//	extends: dark
+//	colors:
+//	  accent: "#7aa2f7"
+//	  selection: "#283457"
+//
+// Colors left out are taken from the theme named by extends (dark when
+// omitted). dark defaults to the value of the extended theme.
+type file struct {
+	Name    string  `yaml:"name"`
+	Extends string  `yaml:"extends"`
+	Dark    *bool   `yaml:"dark"`
+	Colors  Palette `yaml:"colors"`
+}
+
+// Parse decodes a YAML theme. Extended themes must be registered.
+func Parse(data []byte) (Theme, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return Theme{}, errors.Wrap(err, "could not parse theme")
+	}
+	if strings.TrimSpace(f.Name) == "" {
+		return Theme{}, errors.New("theme has no name")
+	}
+	extends := f.Extends
+	if extends == "" {
+		extends = Dark
+	}
+	base, ok := Get(extends)
+	if !ok {
+		return Theme{}, errors.Errorf("theme %s extends unknown theme %s", f.Name, extends)
+	}
+	ret := Theme{Name: f.Name, Dark: base.Dark, Palette: f.Colors.merge(base.Palette)}
+	if f.Dark != nil {
+		ret.Dark = *f.Dark
+	}
+	return ret, nil
+}
+
+// Load reads a YAML theme file.
+func Load(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, errors.Wrapf(err, "could not read theme %s", path)
+	}
+	ret, err := Parse(data)
+	if err != nil {
+		return Theme{}, errors.Wrap(err, path)
+	}
+	return ret, nil
+}
+
+// LoadDir loads and registers every .yaml and .yml file of dir, in name
+// order so a theme can extend one defined in an earlier file. A missing
+// directory is not an error.
+func LoadDir(dir string) ([]Theme, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "could not read theme directory %s", dir)
+	}
+	var ret []Theme
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		t, err := Load(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return ret, err
+		}
+		Register(t)
+		ret = append(ret, t)
+	}
+	return ret, nil
+}
@@ -0,0 +1,191 @@
+// Package theme provides the color palettes shared by the bobatea widgets.
+//
+// A Theme names a Palette of semantic colors (foreground, accent, error, …).
+// Components translate the palette into their own lipgloss styles, for example
+// repl.StylesFromTheme or commandpalette.StylesFromTheme, so switching the
+// theme restyles the REPL, the chat view, the timeline renderers and the
+// widgets consistently.
+//
+// Themes are looked up by name in a registry that holds the built-in themes
+// (dark, light, high-contrast, solarized, solarized-light) and the ones
+// registered by the application, for example after loading them from YAML.
+// The active theme is process-wide: Set or Use it, and components that cannot
+// be handed a theme directly (timeline renderers) read it with Current.
+package theme
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette holds the semantic colors of a theme. Colors are lipgloss colors:
+// ANSI numbers ("62") or hex values ("#268bd2").
+type Palette struct {
+	// Foreground is the color of regular text.
+	Foreground lipgloss.Color `yaml:"foreground,omitempty"`
+	// Muted is used for help text, metadata and other secondary text.
+	Muted lipgloss.Color `yaml:"muted,omitempty"`
+	// Border is the color of unfocused borders and separators.
+	Border lipgloss.Color `yaml:"border,omitempty"`
+	// Surface is the background of popups, title bars and input fields.
+	Surface lipgloss.Color `yaml:"surface,omitempty"`
+	// Accent marks the primary interactive elements: prompts, focused
+	// borders, the selected entry.
+	Accent lipgloss.Color `yaml:"accent,omitempty"`
+	// Secondary marks headers, key hints and icons.
+	Secondary lipgloss.Color `yaml:"secondary,omitempty"`
+	// Highlight marks matched text and informational messages.
+	Highlight lipgloss.Color `yaml:"highlight,omitempty"`
+	// Selection and SelectionForeground color selected rows.
+	Selection           lipgloss.Color `yaml:"selection,omitempty"`
+	SelectionForeground lipgloss.Color `yaml:"selection-foreground,omitempty"`
+
+	Success lipgloss.Color `yaml:"success,omitempty"`
+	Warning lipgloss.Color `yaml:"warning,omitempty"`
+	Error   lipgloss.Color `yaml:"error,omitempty"`
+}
+
+// merge fills the colors unset in p from base.
+func (p Palette) merge(base Palette) Palette {
+	pick := func(c, fallback lipgloss.Color) lipgloss.Color {
+		if c == "" {
+			return fallback
+		}
+		return c
+	}
+	return Palette{
+		Foreground:          pick(p.Foreground, base.Foreground),
+		Muted:               pick(p.Muted, base.Muted),
+		Border:              pick(p.Border, base.Border),
+		Surface:             pick(p.Surface, base.Surface),
+		Accent:              pick(p.Accent, base.Accent),
+		Secondary:           pick(p.Secondary, base.Secondary),
+		Highlight:           pick(p.Highlight, base.Highlight),
+		Selection:           pick(p.Selection, base.Selection),
+		SelectionForeground: pick(p.SelectionForeground, base.SelectionForeground),
+		Success:             pick(p.Success, base.Success),
+		Warning:             pick(p.Warning, base.Warning),
+		Error:               pick(p.Error, base.Error),
+	}
+}
+
+// Theme is a named palette.
+type Theme struct {
+	Name string
+	// Dark is set for palettes meant for a dark terminal background. It picks
+	// the glamour style used to render markdown.
+	Dark    bool
+	Palette Palette
+}
+
+// GlamourStyle returns the standard glamour style matching the theme.
+func (t Theme) GlamourStyle() string {
+	if t.Dark {
+		return "dark"
+	}
+	return "light"
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Theme{}
+	current  *Theme
+)
+
+func init() {
+	for _, t := range builtinThemes() {
+		Register(t)
+	}
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Register adds t to the registry, replacing a theme of the same name.
+func Register(t Theme) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[normalizeName(t.Name)] = t
+}
+
+// Get looks up a registered theme by name, ignoring case.
+func Get(name string) (Theme, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	t, ok := registry[normalizeName(name)]
+	return t, ok
+}
+
+// Names lists the registered themes, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	ret := make([]string, 0, len(registry))
+	for name := range registry {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Current returns the active theme. ok is false until a theme was set, in
+// which case components keep their own default styles.
+func Current() (Theme, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		return Theme{}, false
+	}
+	return *current, true
+}
+
+// Set makes t the active theme.
+func Set(t Theme) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = &t
+}
+
+// Reset clears the active theme so components go back to their defaults.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	current = nil
+}
+
+// ChangedMsg announces a new active theme. Components that hold styles
+// rebuild them when they receive it; hosts forward it to their children.
+type ChangedMsg struct {
+	Theme Theme
+}
+
+// Use sets t as the active theme and returns a command broadcasting ChangedMsg.
+func Use(t Theme) tea.Cmd {
+	Set(t)
+	return func() tea.Msg {
+		return ChangedMsg{Theme: t}
+	}
+}
+
+// HelpStyles returns bubbles help styles for t.
+func HelpStyles(t Theme) help.Styles {
+	p := t.Palette
+	key := lipgloss.NewStyle().Foreground(p.Secondary)
+	desc := lipgloss.NewStyle().Foreground(p.Muted)
+	sep := lipgloss.NewStyle().Foreground(p.Border)
+	return help.Styles{
+		Ellipsis:       sep,
+		ShortKey:       key,
+		ShortDesc:      desc,
+		ShortSeparator: sep,
+		FullKey:        key,
+		FullDesc:       desc,
+		FullSeparator:  sep,
+	}
+}
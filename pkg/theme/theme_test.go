@@ -0,0 +1,97 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinThemesAreComplete(t *testing.T) {
+	for _, name := range []string{Dark, Light, HighContrast, Solarized, SolarizedLight} {
+		th, ok := Get(name)
+		require.True(t, ok, name)
+		require.Equal(t, th.Palette, th.Palette.merge(Palette{}), "%s leaves colors unset", name)
+		require.Equal(t, th.Palette, Palette{}.merge(th.Palette), name)
+	}
+	_, ok := Get("Solarized")
+	require.True(t, ok, "lookup ignores case")
+}
+
+func TestParseExtendsBaseTheme(t *testing.T) {
+	th, err := Parse([]byte(`
+name: midnight
+extends: solarized
+colors:
+  accent: "#7aa2f7"
+  selection-foreground: "0"
+`))
+	require.NoError(t, err)
+	base, _ := Get(Solarized)
+	require.Equal(t, "midnight", th.Name)
+	require.True(t, th.Dark)
+	require.Equal(t, lipgloss.Color("#7aa2f7"), th.Palette.Accent)
+	require.Equal(t, lipgloss.Color("0"), th.Palette.SelectionForeground)
+	require.Equal(t, base.Palette.Error, th.Palette.Error)
+
+	th, err = Parse([]byte("name: paper\nextends: light\ndark: true\n"))
+	require.NoError(t, err)
+	require.True(t, th.Dark)
+
+	_, err = Parse([]byte("name: broken\nextends: nope\n"))
+	require.ErrorContains(t, err, "unknown theme nope")
+	_, err = Parse([]byte("colors: {}\n"))
+	require.ErrorContains(t, err, "no name")
+}
+
+func TestLoadDirRegistersThemesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("name: test-base\ncolors:\n  error: \"1\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte("name: test-child\nextends: test-base\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	themes, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, themes, 2)
+	child, ok := Get("test-child")
+	require.True(t, ok)
+	require.Equal(t, lipgloss.Color("1"), child.Palette.Error)
+
+	themes, err = LoadDir(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Empty(t, themes)
+}
+
+func TestDetectAndResolve(t *testing.T) {
+	prev := hasDarkBackground
+	t.Cleanup(func() { hasDarkBackground = prev })
+
+	t.Setenv(EnvVar, "")
+	hasDarkBackground = func() bool { return false }
+	require.Equal(t, Light, Detect().Name)
+	hasDarkBackground = func() bool { return true }
+	require.Equal(t, Dark, Detect().Name)
+
+	t.Setenv(EnvVar, "solarized")
+	th, err := Resolve("auto")
+	require.NoError(t, err)
+	require.Equal(t, Solarized, th.Name)
+
+	_, err = Resolve("nope")
+	require.ErrorContains(t, err, "unknown theme nope")
+}
+
+func TestUseSetsCurrentAndBroadcasts(t *testing.T) {
+	t.Cleanup(Reset)
+	_, ok := Current()
+	require.False(t, ok)
+
+	th, _ := Get(HighContrast)
+	msg := Use(th)()
+	require.Equal(t, ChangedMsg{Theme: th}, msg)
+	cur, ok := Current()
+	require.True(t, ok)
+	require.Equal(t, HighContrast, cur.Name)
+}
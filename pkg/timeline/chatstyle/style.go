@@ -1,6 +1,11 @@
 package chatstyle
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+)
 
 type Style struct {
 	UnselectedMessage lipgloss.Style
@@ -9,6 +14,20 @@ type Style struct {
 	MetadataStyle     lipgloss.Style
 	ErrorMessage      lipgloss.Style
 	ErrorSelected     lipgloss.Style
+	// Colors are used by renderers for the text inside the message frame.
+	Colors Colors
+}
+
+// Colors are the text colors shared by the timeline renderers.
+type Colors struct {
+	Text    lipgloss.TerminalColor
+	Dim     lipgloss.TerminalColor
+	Muted   lipgloss.TerminalColor
+	Accent  lipgloss.TerminalColor
+	Info    lipgloss.TerminalColor
+	Success lipgloss.TerminalColor
+	Warning lipgloss.TerminalColor
+	Error   lipgloss.TerminalColor
 }
 
 type BorderColors struct {
@@ -57,5 +76,68 @@ func DefaultStyles() *Style {
 			Padding(0, 1).
 			BorderForeground(lipgloss.Color(errorColors.Selected)).
 			Foreground(lipgloss.Color(errorColors.Selected)),
+		Colors: Colors{
+			Text:    lipgloss.Color("252"),
+			Dim:     lipgloss.Color("245"),
+			Muted:   lipgloss.Color("240"),
+			Accent:  lipgloss.Color("212"),
+			Info:    lipgloss.Color("39"),
+			Success: lipgloss.Color("42"),
+			Warning: lipgloss.Color("214"),
+			Error:   lipgloss.Color("196"),
+		},
+	}
+}
+
+// FromTheme builds the renderer styles from a theme palette.
+func FromTheme(t theme.Theme) *Style {
+	p := t.Palette
+	frame := func(border lipgloss.Border, color lipgloss.Color) lipgloss.Style {
+		return lipgloss.NewStyle().Border(border).Padding(0, 1).BorderForeground(color)
+	}
+	return &Style{
+		UnselectedMessage: frame(lipgloss.NormalBorder(), p.Border),
+		SelectedMessage:   frame(lipgloss.ThickBorder(), p.Secondary),
+		FocusedMessage:    frame(lipgloss.NormalBorder(), p.Highlight),
+		MetadataStyle: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Align(lipgloss.Right),
+		ErrorMessage:  frame(lipgloss.NormalBorder(), p.Error).Foreground(p.Error),
+		ErrorSelected: frame(lipgloss.ThickBorder(), p.Error).Foreground(p.Error).Bold(true),
+		Colors: Colors{
+			Text:    p.Foreground,
+			Dim:     p.Muted,
+			Muted:   p.Border,
+			Accent:  p.Secondary,
+			Info:    p.Accent,
+			Success: p.Success,
+			Warning: p.Warning,
+			Error:   p.Error,
+		},
+	}
+}
+
+// Current returns the styles of the active theme, or DefaultStyles when no
+// theme was set.
+func Current() *Style {
+	if t, ok := theme.Current(); ok {
+		return FromTheme(t)
+	}
+	return DefaultStyles()
+}
+
+// Level returns the color of a log level label.
+func (c Colors) Level(level string) lipgloss.TerminalColor {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "error", "err":
+		return c.Error
+	case "warn", "warning":
+		return c.Warning
+	case "debug":
+		return c.Muted
+	case "info":
+		return c.Info
+	default:
+		return c.Dim
 	}
 }
@@ -18,10 +18,14 @@ type Controller struct {
 	// selectionVisible controls whether renderers receive selected=true in props
 	selectionVisible bool
 	entering         bool
+	// recorder, when set, receives every applied lifecycle event
+	recorder *TranscriptWriter
+	// search holds the active query and its matches, see SetSearchQuery
+	search searchState
 }
 
 func NewController(reg *Registry) *Controller {
-	c := &Controller{store: newEntityStore(), reg: reg, selected: -1}
+	c := &Controller{store: newEntityStore(), reg: reg, selected: -1, search: searchState{current: -1, currentLine: -1}}
 	log.Debug().Str("component", "timeline_controller").Msg("initialized controller")
 	return c
 }
@@ -37,6 +41,9 @@ func (c *Controller) SetSize(w, h int) {
 		}
 	}
 }
+
+// SetTheme tells the entity models that the theme called theme became active. Renderers
+// restyle from theme.Current and drop cached output when they receive the "theme" prop.
 func (c *Controller) SetTheme(theme string) {
 	c.theme = theme
 	// Propagate theme to interactive models via props update message
@@ -59,7 +66,8 @@ func (c *Controller) OnCreated(e UIEntityCreated) {
 		Time("started_at", e.StartedAt).
 		Int("props_len", len(e.Props)).
 		Msg("applying created")
-	rec := &entityRecord{ID: e.ID, Renderer: e.Renderer, Props: cloneMap(e.Props), StartedAt: e.StartedAt.UnixNano()}
+	c.record(e)
+	rec := &entityRecord{ID: e.ID, Renderer: e.Renderer, Props: cloneMap(e.Props), Labels: e.Labels, StartedAt: unixNanoOrZero(e.StartedAt)}
 	// Instantiate interactive model if a factory is registered
 	if e.Renderer.Key != "" {
 		if f, ok := c.reg.GetModelFactoryByKey(e.Renderer.Key); ok {
@@ -88,9 +96,11 @@ func (c *Controller) OnCreated(e UIEntityCreated) {
 	if c.selected < 0 {
 		c.selected = 0
 	}
+	c.refreshSearch()
 }
 
 func (c *Controller) OnUpdated(e UIEntityUpdated) {
+	c.record(e)
 	if rec, ok := c.store.get(e.ID); ok {
 		log.Debug().Str("component", "timeline_controller").Str("event", "updated").Str("kind", e.ID.Kind).Str("local_id", e.ID.LocalID).Int64("version", e.Version).Int("patch_len", len(e.Patch)).Msg("applying update")
 		applyPatch(rec.Props, e.Patch)
@@ -98,11 +108,13 @@ func (c *Controller) OnUpdated(e UIEntityUpdated) {
 			rec.model.Update(EntityPropsUpdatedMsg{ID: rec.ID, Patch: e.Patch})
 		}
 		rec.Version = max64(rec.Version, e.Version)
-		rec.UpdatedAt = e.UpdatedAt.UnixNano()
+		rec.UpdatedAt = unixNanoOrZero(e.UpdatedAt)
+		c.refreshSearch()
 	}
 }
 
 func (c *Controller) OnCompleted(e UIEntityCompleted) {
+	c.record(e)
 	if rec, ok := c.store.get(e.ID); ok {
 		log.Debug().Str("component", "timeline_controller").Str("event", "completed").Str("kind", e.ID.Kind).Str("local_id", e.ID.LocalID).Int("result_len", len(e.Result)).Msg("applying complete")
 		if len(e.Result) > 0 {
@@ -112,15 +124,18 @@ func (c *Controller) OnCompleted(e UIEntityCompleted) {
 		if rec.model != nil {
 			rec.model.Update(EntityPropsUpdatedMsg{ID: rec.ID, Patch: e.Result})
 		}
+		c.refreshSearch()
 	}
 }
 
 func (c *Controller) OnDeleted(e UIEntityDeleted) {
+	c.record(e)
 	log.Debug().Str("component", "timeline_controller").Str("event", "deleted").Str("kind", e.ID.Kind).Str("local_id", e.ID.LocalID).Msg("applying delete")
 	c.store.remove(e.ID)
 	if c.selected >= len(c.store.order) {
 		c.selected = len(c.store.order) - 1
 	}
+	c.refreshSearch()
 }
 
 func (c *Controller) SelectNext() {
@@ -144,6 +159,28 @@ func (c *Controller) SelectLast() {
 	}
 }
 
+// Select selects the entity at index i. It returns false if i is out of range.
+func (c *Controller) Select(i int) bool {
+	if i < 0 || i >= len(c.store.order) {
+		return false
+	}
+	c.selected = i
+	log.Debug().Str("component", "timeline_controller").Str("op", "select").Int("selected_index", c.selected).Int("count", len(c.store.order)).Msg("selection changed")
+	return true
+}
+
+// Clear deletes every entity, emitting (and recording) a UIEntityDeleted for each.
+func (c *Controller) Clear() {
+	ids := append([]EntityID(nil), c.store.order...)
+	for _, id := range ids {
+		c.OnDeleted(UIEntityDeleted{ID: id})
+	}
+	c.selected = -1
+}
+
+// Len returns the number of entities in the timeline.
+func (c *Controller) Len() int { return len(c.store.order) }
+
 // SelectedIndex returns the current selected index or -1 if none.
 func (c *Controller) SelectedIndex() int { return c.selected }
 
@@ -153,9 +190,13 @@ func (c *Controller) SetSelectionVisible(v bool) {
 	log.Debug().Str("component", "timeline_controller").Str("op", "set_selection_visible").Bool("visible", v).Int("selected_index", c.selected).Msg("selection visibility updated")
 }
 
+// View renders every entity. While a search is active the matches are highlighted and the
+// line of the current match is remembered for SearchMatchLine.
 func (c *Controller) View() string {
 	var b strings.Builder
-	for _, id := range c.store.order {
+	lines := 0
+	c.search.currentLine = -1
+	for idx, id := range c.store.order {
 		rec, _ := c.store.get(id)
 		// Interactive models are now the only rendering path
 		sel := c.selectionVisible && c.selected >= 0 && keyID(id) == keyID(c.store.order[c.selected])
@@ -172,14 +213,23 @@ func (c *Controller) View() string {
 				rec.model.Update(EntityBlurMsg{ID: rec.ID})
 			}
 			s := rec.model.View()
+			if c.search.query != "" {
+				var line int
+				s, line = highlightSearch(s, c.search.query, c.currentOccurrence(idx))
+				if line >= 0 {
+					c.search.currentLine = lines + line
+				}
+			}
 			b.WriteString(s)
 			b.WriteByte('\n')
+			lines += strings.Count(s, "\n") + 1
 			continue
 		}
 		// If no model, render a minimal plain line
 		s := "[entity] " + rec.ID.Kind
 		b.WriteString(s)
 		b.WriteByte('\n')
+		lines++
 	}
 	return b.String()
 }
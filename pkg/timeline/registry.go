@@ -11,6 +11,13 @@ import (
 // For simplicity, View takes selection/focus flags.
 type EntityModel interface{ tea.Model }
 
+// EntitySnapshotter is implemented by entity models whose state is not the merge of their
+// props, such as models that accumulate streamed "append" patches. Controller.Snapshot stores
+// the returned props on top of the merged ones, so a restored model shows the same content.
+type EntitySnapshotter interface {
+	SnapshotProps() map[string]any
+}
+
 // EntityModelFactory constructs an EntityModel for a given renderer Key/Kind
 type EntityModelFactory interface {
 	Key() string
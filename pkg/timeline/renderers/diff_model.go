@@ -0,0 +1,107 @@
+package renderers
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	chatstyle "github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+)
+
+// DiffModel renders a unified diff with colored additions, removals and hunk headers.
+//
+// Props:
+//   - diff (or text): unified diff text
+//   - title (or path): optional heading
+type DiffModel struct {
+	width    int
+	selected bool
+	title    string
+	diff     string
+}
+
+func (m *DiffModel) Init() tea.Cmd { return nil }
+
+func (m *DiffModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch v := msg.(type) {
+	case timeline.EntitySelectedMsg:
+		m.selected = true
+	case timeline.EntityUnselectedMsg:
+		m.selected = false
+	case timeline.EntitySetSizeMsg:
+		m.width = v.Width
+	case timeline.EntityPropsUpdatedMsg:
+		if v.Patch != nil {
+			m.OnProps(v.Patch)
+		}
+	case timeline.EntityCopyTextMsg, timeline.EntityCopyCodeMsg:
+		d := m.diff
+		return m, func() tea.Msg { return timeline.CopyCodeRequestedMsg{Code: d} }
+	}
+	return m, nil
+}
+
+func (m *DiffModel) OnProps(patch map[string]any) {
+	if v, ok := patch["selected"].(bool); ok {
+		m.selected = v
+	}
+	if v, ok := patch["title"].(string); ok {
+		m.title = v
+	} else if v, ok := patch["path"].(string); ok {
+		m.title = v
+	}
+	if v, ok := patch["diff"].(string); ok {
+		m.diff = v
+	} else if v, ok := patch["text"].(string); ok {
+		m.diff = v
+	}
+}
+
+func (m *DiffModel) View() string {
+	st := chatstyle.Current()
+	sty := st.UnselectedMessage
+	if m.selected {
+		sty = st.SelectedMessage
+	}
+	add := lipgloss.NewStyle().Foreground(st.Colors.Success)
+	del := lipgloss.NewStyle().Foreground(st.Colors.Error)
+	hunk := lipgloss.NewStyle().Foreground(st.Colors.Info)
+	meta := lipgloss.NewStyle().Foreground(st.Colors.Muted).Bold(true)
+
+	adds, dels := 0, 0
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(m.diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"), strings.HasPrefix(l, "diff "), strings.HasPrefix(l, "index "):
+			lines = append(lines, meta.Render(l))
+		case strings.HasPrefix(l, "@@"):
+			lines = append(lines, hunk.Render(l))
+		case strings.HasPrefix(l, "+"):
+			adds++
+			lines = append(lines, add.Render(l))
+		case strings.HasPrefix(l, "-"):
+			dels++
+			lines = append(lines, del.Render(l))
+		default:
+			lines = append(lines, l)
+		}
+	}
+	header := m.title
+	if header == "" {
+		header = "diff"
+	}
+	header = lipgloss.NewStyle().Bold(true).Render(header) + "  " + add.Render("+"+strconv.Itoa(adds)) + " " + del.Render("-"+strconv.Itoa(dels))
+	return sty.Width(m.width - sty.GetHorizontalPadding()).Render(header + "\n" + strings.Join(lines, "\n"))
+}
+
+type DiffFactory struct{}
+
+func (DiffFactory) Key() string  { return "renderer.diff.v1" }
+func (DiffFactory) Kind() string { return "diff" }
+func (DiffFactory) NewEntityModel(initialProps map[string]any) timeline.EntityModel {
+	m := &DiffModel{}
+	m.OnProps(initialProps)
+	return m
+}
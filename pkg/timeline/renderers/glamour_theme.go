@@ -0,0 +1,41 @@
+package renderers
+
+import (
+	"sync"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/go-go-golems/bobatea/pkg/theme"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	themedGlamourMu        sync.Mutex
+	themedGlamourRenderers = map[string]*glamour.TermRenderer{}
+)
+
+// markdownRenderer returns the glamour renderer matching the active theme, or
+// fallback (the factory's renderer, styled from the terminal background at
+// startup) when no theme is set or stdout is not a terminal.
+func markdownRenderer(fallback *glamour.TermRenderer) *glamour.TermRenderer {
+	t, ok := theme.Current()
+	if !ok || !stdoutIsTerminal() {
+		return fallback
+	}
+	style := t.GlamourStyle()
+
+	themedGlamourMu.Lock()
+	defer themedGlamourMu.Unlock()
+	if r, ok := themedGlamourRenderers[style]; ok {
+		return r
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(80),
+	)
+	if err != nil {
+		log.Error().Err(err).Str("component", "renderer").Str("style", style).Msg("Failed to create glamour renderer")
+		return fallback
+	}
+	themedGlamourRenderers[style] = r
+	return r
+}
@@ -1,6 +1,7 @@
 package renderers
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -72,7 +73,7 @@ func (m *LLMTextModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *LLMTextModel) View() string {
 	if m.style == nil {
-		m.style = chatstyle.DefaultStyles()
+		m.style = chatstyle.Current()
 	}
 	if m.role == "" {
 		m.role = "assistant"
@@ -103,8 +104,8 @@ func (m *LLMTextModel) View() string {
 
 	// Render markdown with glamour
 	var body string
-	if m.renderer != nil {
-		if out, err := m.renderer.Render(m.text + "\n"); err == nil {
+	if r := markdownRenderer(m.renderer); r != nil {
+		if out, err := r.Render(m.text + "\n"); err == nil {
 			body = strings.TrimSpace(out)
 		}
 	}
@@ -161,6 +162,10 @@ func (m *LLMTextModel) View() string {
 }
 
 func (m *LLMTextModel) OnProps(patch map[string]any) {
+	if _, ok := patch["theme"]; ok {
+		// rebuilt from the new theme on the next View
+		m.style = nil
+	}
 	if v, ok := patch["role"].(string); ok {
 		m.role = v
 	}
@@ -255,6 +260,17 @@ func formatMetadata(md any) string {
 		tt := t
 		out := formatFromLLMInferenceData(&tt)
 		return out
+	case map[string]any:
+		// Restored from a transcript, which stores props as JSON
+		b, err := json.Marshal(t)
+		if err != nil {
+			return ""
+		}
+		var d geppetto_events.LLMInferenceData
+		if err := json.Unmarshal(b, &d); err != nil {
+			return ""
+		}
+		return formatFromLLMInferenceData(&d)
 	}
 	// Unknown type: ignore
 	return ""
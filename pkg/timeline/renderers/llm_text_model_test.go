@@ -0,0 +1,23 @@
+package renderers
+
+import (
+	"encoding/json"
+	"testing"
+
+	geppetto_events "github.com/go-go-golems/geppetto/pkg/events"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatMetadataDecodesRestoredProps(t *testing.T) {
+	temp := 0.5
+	md := geppetto_events.LLMInferenceData{Model: "gpt-test", Temperature: &temp}
+
+	// a transcript stores the typed metadata as a JSON object
+	b, err := json.Marshal(md)
+	require.NoError(t, err)
+	var restored map[string]any
+	require.NoError(t, json.Unmarshal(b, &restored))
+
+	require.Equal(t, formatMetadata(md), formatMetadata(restored))
+	require.Contains(t, formatMetadata(restored), "gpt-test")
+}
@@ -60,6 +60,9 @@ func (m *MarkdownModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *MarkdownModel) onProps(patch map[string]any) {
+	if _, ok := patch["theme"]; ok {
+		m.cachedRendered = ""
+	}
 	if v, ok := patch["selected"].(bool); ok {
 		m.selected = v
 	}
@@ -76,7 +79,7 @@ func (m *MarkdownModel) onProps(patch map[string]any) {
 
 func (m *MarkdownModel) View() string {
 	log.Trace().Str("component", "markdown_model").Str("phase", "view").Int("md_len", len(m.md)).Msg("calling model.View")
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
@@ -94,10 +97,10 @@ func (m *MarkdownModel) View() string {
 	if m.cachedRendered != "" && m.cachedWidth == contentWidth && m.cachedMD == m.md {
 		body = m.cachedRendered
 	} else {
-		if m.renderer != nil {
+		if r := markdownRenderer(m.renderer); r != nil {
 			start := time.Now()
 			log.Trace().Str("component", "markdown_model").Str("phase", "view").Int("md_len", len(m.md)).Msg("calling glamour renderer")
-			if out, err := m.renderer.Render(strings.TrimSpace(m.md) + "\n"); err == nil {
+			if out, err := r.Render(strings.TrimSpace(m.md) + "\n"); err == nil {
 				body = strings.TrimSpace(out)
 			}
 			dur := time.Since(start)
@@ -0,0 +1,178 @@
+package renderers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+	"github.com/mattn/go-runewidth"
+)
+
+// PerfModel renders a timing breakdown as proportional horizontal bars.
+//
+// Props:
+//   - label: optional heading (defaults to "timing")
+//   - total_ms: overall duration; defaults to the sum of the phases
+//   - phases: []{"name": string, "ms": number} in display order, or map[name]ms (sorted by duration)
+type PerfModel struct {
+	width    int
+	selected bool
+	label    string
+	totalMs  float64
+	phases   []perfPhase
+}
+
+type perfPhase struct {
+	name string
+	ms   float64
+}
+
+func (m *PerfModel) Init() tea.Cmd { return nil }
+
+func (m *PerfModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch v := msg.(type) {
+	case timeline.EntitySelectedMsg:
+		m.selected = true
+	case timeline.EntityUnselectedMsg:
+		m.selected = false
+	case timeline.EntitySetSizeMsg:
+		m.width = v.Width
+	case timeline.EntityPropsUpdatedMsg:
+		if v.Patch != nil {
+			m.OnProps(v.Patch)
+		}
+	case timeline.EntityCopyTextMsg, timeline.EntityCopyCodeMsg:
+		txt := m.plain()
+		return m, func() tea.Msg { return timeline.CopyTextRequestedMsg{Text: txt} }
+	}
+	return m, nil
+}
+
+func (m *PerfModel) OnProps(patch map[string]any) {
+	if v, ok := patch["selected"].(bool); ok {
+		m.selected = v
+	}
+	if v, ok := patch["label"].(string); ok {
+		m.label = v
+	}
+	if v, ok := propFloat(patch["total_ms"]); ok {
+		m.totalMs = v
+	}
+	switch t := patch["phases"].(type) {
+	case []any:
+		m.phases = m.phases[:0]
+		for _, e := range t {
+			mp, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := mp["name"].(string)
+			ms, _ := propFloat(mp["ms"])
+			m.phases = append(m.phases, perfPhase{name: name, ms: ms})
+		}
+	case map[string]any:
+		m.phases = m.phases[:0]
+		for name, v := range t {
+			ms, _ := propFloat(v)
+			m.phases = append(m.phases, perfPhase{name: name, ms: ms})
+		}
+		sort.SliceStable(m.phases, func(i, j int) bool {
+			if m.phases[i].ms != m.phases[j].ms {
+				return m.phases[i].ms > m.phases[j].ms
+			}
+			return m.phases[i].name < m.phases[j].name
+		})
+	}
+}
+
+func (m *PerfModel) total() float64 {
+	if m.totalMs > 0 {
+		return m.totalMs
+	}
+	sum := 0.0
+	for _, p := range m.phases {
+		sum += p.ms
+	}
+	return sum
+}
+
+func (m *PerfModel) heading() string {
+	label := m.label
+	if label == "" {
+		label = "timing"
+	}
+	return fmt.Sprintf("⏱ %s  %s", label, formatMs(m.total()))
+}
+
+func (m *PerfModel) plain() string {
+	lines := []string{m.heading()}
+	for _, p := range m.phases {
+		lines = append(lines, fmt.Sprintf("  %s  %s", p.name, formatMs(p.ms)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *PerfModel) View() string {
+	base := lipgloss.NewStyle().Padding(0, 1)
+	inner := m.width - base.GetHorizontalPadding()
+
+	colors := chatstyle.Current().Colors
+	fg := colors.Dim
+	if m.selected {
+		fg = colors.Text
+	}
+	text := lipgloss.NewStyle().Foreground(fg)
+	lines := []string{text.Bold(true).Render(m.heading())}
+
+	nameW := 0
+	for _, p := range m.phases {
+		nameW = max(nameW, runewidth.StringWidth(p.name))
+	}
+	nameW = min(nameW, max(inner/3, 8))
+	total := m.total()
+	for _, p := range m.phases {
+		value := fmt.Sprintf("%8s %5.1f%%", formatMs(p.ms), pct(p.ms, total))
+		barW := max(inner-nameW-runewidth.StringWidth(value)-4, 4)
+		filled := 0
+		if total > 0 {
+			filled = int(float64(barW) * p.ms / total)
+		}
+		filled = min(max(filled, 0), barW)
+		bar := lipgloss.NewStyle().Foreground(colors.Warning).Render(strings.Repeat("▇", filled)) +
+			strings.Repeat(" ", barW-filled)
+		lines = append(lines, "  "+text.Render(alignCell(p.name, nameW, lipgloss.Left))+" "+bar+" "+text.Render(value))
+	}
+	return base.Width(inner).Render(strings.Join(lines, "\n"))
+}
+
+func pct(v, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return v / total * 100
+}
+
+func formatMs(ms float64) string {
+	switch {
+	case ms < 1:
+		return fmt.Sprintf("%.0fµs", ms*1000)
+	case ms < 1000:
+		return fmt.Sprintf("%.1fms", ms)
+	default:
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+}
+
+type PerfFactory struct{}
+
+func (PerfFactory) Key() string  { return "renderer.perf.v1" }
+func (PerfFactory) Kind() string { return "perf" }
+func (PerfFactory) NewEntityModel(initialProps map[string]any) timeline.EntityModel {
+	m := &PerfModel{}
+	m.OnProps(initialProps)
+	return m
+}
@@ -38,7 +38,7 @@ func (m *PlainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 func (m *PlainModel) View() string {
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
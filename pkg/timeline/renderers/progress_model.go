@@ -0,0 +1,197 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+)
+
+// ProgressModel renders a progress bar with percentage and ETA.
+//
+// Props:
+//   - label (or message): text shown before the bar
+//   - percent: 0..100; alternatively current + total
+//   - unit: optional unit appended to current/total (e.g. "MB")
+//   - eta_ms: explicit ETA; otherwise estimated from elapsed time and progress
+//   - done: marks the progress as finished; error: marks it as failed
+type ProgressModel struct {
+	width    int
+	selected bool
+
+	label    string
+	unit     string
+	percent  float64
+	current  float64
+	total    float64
+	hasCount bool
+	etaMs    float64
+	hasEta   bool
+	done     bool
+	errText  string
+
+	startedAt time.Time
+	now       func() time.Time
+}
+
+func (m *ProgressModel) Init() tea.Cmd { return nil }
+
+func (m *ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch v := msg.(type) {
+	case timeline.EntitySelectedMsg:
+		m.selected = true
+	case timeline.EntityUnselectedMsg:
+		m.selected = false
+	case timeline.EntitySetSizeMsg:
+		m.width = v.Width
+	case timeline.EntityPropsUpdatedMsg:
+		if v.Patch != nil {
+			m.OnProps(v.Patch)
+		}
+	case timeline.EntityCopyTextMsg, timeline.EntityCopyCodeMsg:
+		txt := m.summary()
+		return m, func() tea.Msg { return timeline.CopyTextRequestedMsg{Text: txt} }
+	}
+	return m, nil
+}
+
+func (m *ProgressModel) OnProps(patch map[string]any) {
+	if v, ok := patch["selected"].(bool); ok {
+		m.selected = v
+	}
+	if v, ok := patch["label"].(string); ok {
+		m.label = v
+	} else if v, ok := patch["message"].(string); ok {
+		m.label = v
+	}
+	if v, ok := patch["unit"].(string); ok {
+		m.unit = v
+	}
+	if v, ok := propFloat(patch["current"]); ok {
+		m.current = v
+		m.hasCount = true
+	}
+	if v, ok := propFloat(patch["total"]); ok {
+		m.total = v
+	}
+	if v, ok := propFloat(patch["percent"]); ok {
+		m.percent = v
+	} else if m.hasCount && m.total > 0 {
+		m.percent = m.current / m.total * 100
+	}
+	if v, ok := propFloat(patch["eta_ms"]); ok {
+		m.etaMs = v
+		m.hasEta = true
+	}
+	if v, ok := patch["done"].(bool); ok {
+		m.done = v
+	}
+	if v, ok := patch["error"].(string); ok {
+		m.errText = v
+	}
+	if v, ok := patch["interrupted"].(bool); ok && v && !m.done && m.errText == "" {
+		m.errText = "interrupted"
+	}
+	if m.done && m.errText == "" {
+		m.percent = 100
+	}
+	m.percent = min(max(m.percent, 0), 100)
+}
+
+// eta returns the remaining duration, estimated linearly when not provided.
+func (m *ProgressModel) eta() (time.Duration, bool) {
+	if m.done || m.errText != "" {
+		return 0, false
+	}
+	if m.hasEta {
+		return time.Duration(m.etaMs) * time.Millisecond, true
+	}
+	if m.percent <= 0 || m.startedAt.IsZero() {
+		return 0, false
+	}
+	elapsed := m.now().Sub(m.startedAt)
+	remaining := time.Duration(float64(elapsed) * (100 - m.percent) / m.percent)
+	return remaining, true
+}
+
+func (m *ProgressModel) summary() string {
+	parts := []string{}
+	if m.label != "" {
+		parts = append(parts, m.label)
+	}
+	parts = append(parts, fmt.Sprintf("%3.0f%%", m.percent))
+	if m.hasCount && m.total > 0 {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%s/%s %s", cellString(m.current), cellString(m.total), m.unit)))
+	}
+	switch {
+	case m.errText != "":
+		parts = append(parts, "failed: "+m.errText)
+	case m.done:
+		parts = append(parts, "done")
+	default:
+		if d, ok := m.eta(); ok {
+			parts = append(parts, "ETA "+formatETA(d))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+func (m *ProgressModel) View() string {
+	base := lipgloss.NewStyle().Padding(0, 1)
+	inner := m.width - base.GetHorizontalPadding()
+
+	colors := chatstyle.Current().Colors
+	fill := colors.Info
+	switch {
+	case m.errText != "":
+		fill = colors.Error
+	case m.done:
+		fill = colors.Success
+	}
+	labelColor := colors.Dim
+	if m.selected {
+		labelColor = colors.Text
+	}
+
+	info := lipgloss.NewStyle().Foreground(labelColor).Render(m.summary())
+	barWidth := inner - lipgloss.Width(info) - 1
+	if barWidth < 10 {
+		// Not enough room on one line; put the bar below the label.
+		barWidth = max(inner, 10)
+		return base.Width(inner).Render(info + "\n" + renderBar(barWidth, m.percent, fill, colors.Muted))
+	}
+	return base.Width(inner).Render(renderBar(barWidth, m.percent, fill, colors.Muted) + " " + info)
+}
+
+func renderBar(width int, percent float64, fill, track lipgloss.TerminalColor) string {
+	filled := int(float64(width) * percent / 100)
+	filled = min(max(filled, 0), width)
+	return lipgloss.NewStyle().Foreground(fill).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(track).Render(strings.Repeat("░", width-filled))
+}
+
+func formatETA(d time.Duration) string {
+	if d < time.Second {
+		return "<1s"
+	}
+	d = d.Round(time.Second)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+type ProgressFactory struct{}
+
+func (ProgressFactory) Key() string  { return "renderer.progress.v1" }
+func (ProgressFactory) Kind() string { return "progress" }
+func (ProgressFactory) NewEntityModel(initialProps map[string]any) timeline.EntityModel {
+	m := &ProgressModel{now: time.Now}
+	m.startedAt = m.now()
+	m.OnProps(initialProps)
+	return m
+}
@@ -0,0 +1,25 @@
+package renderers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressModelEstimatesETA(t *testing.T) {
+	start := time.Unix(1000, 0)
+	now := start.Add(10 * time.Second)
+	m := &ProgressModel{now: func() time.Time { return now }, startedAt: start}
+	m.OnProps(map[string]any{"label": "copy", "current": 25.0, "total": 100.0})
+
+	d, ok := m.eta()
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, d)
+	require.Contains(t, m.summary(), "25/100")
+
+	m.OnProps(map[string]any{"done": true})
+	_, ok = m.eta()
+	require.False(t, ok)
+	require.Equal(t, 100.0, m.percent)
+}
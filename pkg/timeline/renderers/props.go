@@ -0,0 +1,69 @@
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Props travel through the JSON bus, so numbers usually arrive as float64.
+// These helpers accept the common Go numeric types as well.
+
+func propFloat(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case uint:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(t, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func propInt(v any) (int, bool) {
+	f, ok := propFloat(v)
+	return int(f), ok
+}
+
+func propStrings(v any) []string {
+	switch t := v.(type) {
+	case []string:
+		return t
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			out = append(out, cellString(e))
+		}
+		return out
+	}
+	return nil
+}
+
+func cellString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(t), 'f', -1, 32)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,151 @@
+package renderers
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	chatstyle "github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+)
+
+// ShellCmdModel renders a shell command invocation with its output and exit status.
+//
+// Props:
+//   - command: the command line; cwd: optional working directory
+//   - output (replace) / append: combined output; stderr: error output shown in red
+//   - exit_code: process exit code; absent while the command is running
+//   - duration_ms: optional wall time
+type ShellCmdModel struct {
+	width      int
+	selected   bool
+	command    string
+	cwd        string
+	output     strings.Builder
+	stderr     string
+	exitCode   int
+	hasExit    bool
+	durationMs float64
+	// interrupted is set when the REPL turn was cancelled before the command exited
+	interrupted bool
+}
+
+func (m *ShellCmdModel) Init() tea.Cmd { return nil }
+
+func (m *ShellCmdModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch v := msg.(type) {
+	case timeline.EntitySelectedMsg:
+		m.selected = true
+	case timeline.EntityUnselectedMsg:
+		m.selected = false
+	case timeline.EntitySetSizeMsg:
+		m.width = v.Width
+	case timeline.EntityPropsUpdatedMsg:
+		if v.Patch != nil {
+			m.OnProps(v.Patch)
+		}
+	case timeline.EntityCopyTextMsg:
+		txt := m.output.String()
+		return m, func() tea.Msg { return timeline.CopyTextRequestedMsg{Text: txt} }
+	case timeline.EntityCopyCodeMsg:
+		cmd := m.command
+		return m, func() tea.Msg { return timeline.CopyCodeRequestedMsg{Code: cmd} }
+	}
+	return m, nil
+}
+
+func (m *ShellCmdModel) OnProps(patch map[string]any) {
+	if v, ok := patch["selected"].(bool); ok {
+		m.selected = v
+	}
+	if v, ok := patch["command"].(string); ok {
+		m.command = v
+	}
+	if v, ok := patch["cwd"].(string); ok {
+		m.cwd = v
+	}
+	if v, ok := patch["output"].(string); ok {
+		m.output.Reset()
+		m.output.WriteString(v)
+	}
+	if v, ok := patch["append"].(string); ok {
+		m.output.WriteString(v)
+	}
+	if v, ok := patch["stderr"].(string); ok {
+		m.stderr = v
+	}
+	if v, ok := propInt(patch["exit_code"]); ok {
+		m.exitCode = v
+		m.hasExit = true
+	}
+	if v, ok := propFloat(patch["duration_ms"]); ok {
+		m.durationMs = v
+	}
+	if v, ok := patch["interrupted"].(bool); ok {
+		m.interrupted = v
+	}
+}
+
+// SnapshotProps reports the accumulated output, including streamed appends.
+func (m *ShellCmdModel) SnapshotProps() map[string]any {
+	return map[string]any{"output": m.output.String()}
+}
+
+func (m *ShellCmdModel) status(c chatstyle.Colors) string {
+	if !m.hasExit && m.interrupted {
+		return lipgloss.NewStyle().Foreground(c.Warning).Render("⏹ interrupted")
+	}
+	if !m.hasExit {
+		return lipgloss.NewStyle().Foreground(c.Warning).Render("… running")
+	}
+	s := fmt.Sprintf("exit %d", m.exitCode)
+	if m.durationMs > 0 {
+		s += " · " + formatMs(m.durationMs)
+	}
+	if m.exitCode == 0 {
+		return lipgloss.NewStyle().Foreground(c.Success).Render("✓ " + s)
+	}
+	return lipgloss.NewStyle().Foreground(c.Error).Render("✗ " + s)
+}
+
+func (m *ShellCmdModel) View() string {
+	st := chatstyle.Current()
+	sty := st.UnselectedMessage
+	if m.selected {
+		sty = st.SelectedMessage
+	}
+	if m.hasExit && m.exitCode != 0 {
+		sty = sty.BorderForeground(st.ErrorMessage.GetBorderTopForeground())
+	}
+	inner := m.width - sty.GetHorizontalFrameSize()
+
+	prompt := lipgloss.NewStyle().Bold(true).Render("$ " + m.command)
+	status := m.status(st.Colors)
+	gap := inner - lipgloss.Width(prompt) - lipgloss.Width(status)
+	header := prompt + "  " + status
+	if gap >= 2 {
+		header = prompt + strings.Repeat(" ", gap) + status
+	}
+	lines := []string{header}
+	if m.cwd != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(st.Colors.Muted).Render("in "+m.cwd))
+	}
+	if out := strings.TrimRight(m.output.String(), "\n"); out != "" {
+		lines = append(lines, out)
+	}
+	if errOut := strings.TrimRight(m.stderr, "\n"); errOut != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(st.Colors.Error).Render(errOut))
+	}
+	return sty.Width(m.width - sty.GetHorizontalPadding()).Render(strings.Join(lines, "\n"))
+}
+
+type ShellCmdFactory struct{}
+
+func (ShellCmdFactory) Key() string  { return "renderer.shell_cmd.v1" }
+func (ShellCmdFactory) Kind() string { return "shell_cmd" }
+func (ShellCmdFactory) NewEntityModel(initialProps map[string]any) timeline.EntityModel {
+	m := &ShellCmdModel{}
+	m.OnProps(initialProps)
+	return m
+}
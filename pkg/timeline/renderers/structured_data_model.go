@@ -58,7 +58,7 @@ func (m *StructuredDataModel) onProps(patch map[string]any) {
 }
 
 func (m *StructuredDataModel) View() string {
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 )
@@ -74,24 +75,11 @@ func (m *StructuredLogEventModel) View() string {
 	level := strings.ToUpper(strings.TrimSpace(m.level))
 	msg := strings.TrimSpace(m.message)
 
-	var lvlColor lipgloss.Color
-	switch strings.ToLower(level) {
-	case "error", "err":
-		lvlColor = lipgloss.Color("196")
-	case "warn", "warning":
-		lvlColor = lipgloss.Color("214")
-	case "debug":
-		lvlColor = lipgloss.Color("243")
-	case "info":
-		lvlColor = lipgloss.Color("39")
-	default:
-		lvlColor = lipgloss.Color("245")
-	}
-
-	lvl := lipgloss.NewStyle().Foreground(lvlColor).Bold(true).Render("[" + level + "]")
-	msgColor := lipgloss.Color("245")
+	colors := chatstyle.Current().Colors
+	lvl := lipgloss.NewStyle().Foreground(colors.Level(level)).Bold(true).Render("[" + level + "]")
+	msgColor := colors.Dim
 	if m.selected {
-		msgColor = lipgloss.Color("252")
+		msgColor = colors.Text
 	}
 	msgStyled := lipgloss.NewStyle().Foreground(msgColor).Render(msg)
 
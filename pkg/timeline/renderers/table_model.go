@@ -0,0 +1,261 @@
+package renderers
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	chatstyle "github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+	"github.com/mattn/go-runewidth"
+)
+
+// TableModel renders tabular data with per-column alignment.
+//
+// Props:
+//   - columns: []string header names
+//   - rows: [][]any cells, or []map[string]any keyed by column name
+//   - align: []string per column ("left", "right", "center"); numeric columns default to right
+//   - title: optional caption rendered above the table
+type TableModel struct {
+	width    int
+	selected bool
+	title    string
+	columns  []string
+	rows     [][]string
+	align    []string
+}
+
+func (m *TableModel) Init() tea.Cmd { return nil }
+
+func (m *TableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch v := msg.(type) {
+	case timeline.EntitySelectedMsg:
+		m.selected = true
+	case timeline.EntityUnselectedMsg:
+		m.selected = false
+	case timeline.EntitySetSizeMsg:
+		m.width = v.Width
+	case timeline.EntityPropsUpdatedMsg:
+		if v.Patch != nil {
+			m.OnProps(v.Patch)
+		}
+	case timeline.EntityCopyTextMsg, timeline.EntityCopyCodeMsg:
+		txt := m.tsv()
+		return m, func() tea.Msg { return timeline.CopyTextRequestedMsg{Text: txt} }
+	}
+	return m, nil
+}
+
+func (m *TableModel) OnProps(patch map[string]any) {
+	if v, ok := patch["selected"].(bool); ok {
+		m.selected = v
+	}
+	if v, ok := patch["title"].(string); ok {
+		m.title = v
+	}
+	if v, ok := patch["columns"]; ok {
+		m.columns = propStrings(v)
+	}
+	if v, ok := patch["align"]; ok {
+		m.align = propStrings(v)
+	}
+	if v, ok := patch["rows"]; ok {
+		m.rows = m.parseRows(v)
+	}
+}
+
+func (m *TableModel) parseRows(v any) [][]string {
+	var out [][]string
+	switch t := v.(type) {
+	case [][]string:
+		return t
+	case [][]any:
+		for _, r := range t {
+			out = append(out, propStrings(r))
+		}
+	case []map[string]any:
+		for _, r := range t {
+			out = append(out, m.rowFromMap(r))
+		}
+	case []any:
+		for _, r := range t {
+			if mp, ok := r.(map[string]any); ok {
+				out = append(out, m.rowFromMap(mp))
+				continue
+			}
+			out = append(out, propStrings(r))
+		}
+	}
+	return out
+}
+
+func (m *TableModel) rowFromMap(r map[string]any) []string {
+	row := make([]string, len(m.columns))
+	for i, c := range m.columns {
+		row[i] = cellString(r[c])
+	}
+	return row
+}
+
+func (m *TableModel) numCols() int {
+	n := len(m.columns)
+	for _, r := range m.rows {
+		if len(r) > n {
+			n = len(r)
+		}
+	}
+	return n
+}
+
+// columnAlign resolves the alignment of column i, defaulting to right for numeric columns.
+func (m *TableModel) columnAlign(i int) lipgloss.Position {
+	if i < len(m.align) {
+		switch strings.ToLower(m.align[i]) {
+		case "right", "r":
+			return lipgloss.Right
+		case "center", "c":
+			return lipgloss.Center
+		case "left", "l":
+			return lipgloss.Left
+		}
+	}
+	numeric := false
+	for _, r := range m.rows {
+		if i >= len(r) || r[i] == "" {
+			continue
+		}
+		if _, ok := propFloat(r[i]); !ok {
+			return lipgloss.Left
+		}
+		numeric = true
+	}
+	if numeric {
+		return lipgloss.Right
+	}
+	return lipgloss.Left
+}
+
+func (m *TableModel) View() string {
+	st := chatstyle.Current()
+	sty := st.UnselectedMessage
+	if m.selected {
+		sty = st.SelectedMessage
+	}
+	inner := m.width - sty.GetHorizontalFrameSize()
+	return sty.Width(m.width - sty.GetHorizontalPadding()).Render(m.renderTable(inner))
+}
+
+func (m *TableModel) renderTable(maxWidth int) string {
+	n := m.numCols()
+	if n == 0 {
+		if m.title != "" {
+			return m.title
+		}
+		return "(empty table)"
+	}
+	widths := make([]int, n)
+	for i := 0; i < n; i++ {
+		if i < len(m.columns) {
+			widths[i] = runewidth.StringWidth(m.columns[i])
+		}
+		for _, r := range m.rows {
+			if i < len(r) {
+				widths[i] = max(widths[i], runewidth.StringWidth(r[i]))
+			}
+		}
+	}
+	fitWidths(widths, maxWidth-(n-1)*3)
+
+	header := lipgloss.NewStyle().Bold(true)
+	sep := lipgloss.NewStyle().Foreground(chatstyle.Current().Colors.Muted)
+	var lines []string
+	if m.title != "" {
+		lines = append(lines, header.Render(m.title))
+	}
+	if len(m.columns) > 0 {
+		cells := make([]string, n)
+		for i := 0; i < n; i++ {
+			c := ""
+			if i < len(m.columns) {
+				c = m.columns[i]
+			}
+			cells[i] = header.Render(alignCell(c, widths[i], m.columnAlign(i)))
+		}
+		lines = append(lines, strings.Join(cells, sep.Render(" │ ")))
+		rules := make([]string, n)
+		for i, w := range widths {
+			rules[i] = strings.Repeat("─", w)
+		}
+		lines = append(lines, sep.Render(strings.Join(rules, "─┼─")))
+	}
+	for _, r := range m.rows {
+		cells := make([]string, n)
+		for i := 0; i < n; i++ {
+			c := ""
+			if i < len(r) {
+				c = r[i]
+			}
+			cells[i] = alignCell(c, widths[i], m.columnAlign(i))
+		}
+		lines = append(lines, strings.Join(cells, sep.Render(" │ ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *TableModel) tsv() string {
+	var lines []string
+	if len(m.columns) > 0 {
+		lines = append(lines, strings.Join(m.columns, "\t"))
+	}
+	for _, r := range m.rows {
+		lines = append(lines, strings.Join(r, "\t"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fitWidths shrinks the widest columns until the sum fits into budget.
+func fitWidths(widths []int, budget int) {
+	if budget <= 0 {
+		return
+	}
+	for {
+		total, widest := 0, 0
+		for i, w := range widths {
+			total += w
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if total <= budget || widths[widest] <= 3 {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func alignCell(s string, width int, pos lipgloss.Position) string {
+	if runewidth.StringWidth(s) > width {
+		s = runewidth.Truncate(s, width, "…")
+	}
+	pad := width - runewidth.StringWidth(s)
+	switch pos {
+	case lipgloss.Right:
+		return strings.Repeat(" ", pad) + s
+	case lipgloss.Center:
+		left := pad / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", pad-left)
+	default:
+		return s + strings.Repeat(" ", pad)
+	}
+}
+
+type TableFactory struct{}
+
+func (TableFactory) Key() string  { return "renderer.table.v1" }
+func (TableFactory) Kind() string { return "table" }
+func (TableFactory) NewEntityModel(initialProps map[string]any) timeline.EntityModel {
+	m := &TableModel{}
+	m.OnProps(initialProps)
+	return m
+}
@@ -0,0 +1,38 @@
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableModelAlignsNumericColumnsRight(t *testing.T) {
+	m := TableFactory{}.NewEntityModel(map[string]any{
+		"columns": []any{"name", "size"},
+		"rows": []any{
+			map[string]any{"name": "a", "size": 5.0},
+			map[string]any{"name": "bbbb", "size": 1234.0},
+		},
+	}).(*TableModel)
+
+	require.Equal(t, lipgloss.Left, m.columnAlign(0))
+	require.Equal(t, lipgloss.Right, m.columnAlign(1))
+
+	out := m.renderTable(80)
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[2], "a    ")
+	require.True(t, strings.HasSuffix(lines[2], "   5"), lines[2])
+	require.True(t, strings.HasSuffix(lines[3], "1234"), lines[3])
+}
+
+func TestTableModelExplicitAlignOverridesDetection(t *testing.T) {
+	m := TableFactory{}.NewEntityModel(map[string]any{
+		"columns": []string{"n"},
+		"rows":    [][]any{{1}, {22}},
+		"align":   []string{"left"},
+	}).(*TableModel)
+	require.Equal(t, lipgloss.Left, m.columnAlign(0))
+}
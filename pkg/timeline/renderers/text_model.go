@@ -62,8 +62,13 @@ func (m *TextModel) onProps(patch map[string]any) {
 	}
 }
 
+// SnapshotProps reports the accumulated text, including streamed appends.
+func (m *TextModel) SnapshotProps() map[string]any {
+	return map[string]any{"text": m.text.String()}
+}
+
 func (m *TextModel) View() string {
-	st := chatstyle.DefaultStyles()
+	st := chatstyle.Current()
 	sty := st.UnselectedMessage
 	if m.selected {
 		sty = st.SelectedMessage
@@ -0,0 +1,33 @@
+package renderers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-go-golems/bobatea/pkg/timeline"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextModelSnapshotKeepsStreamedAppends(t *testing.T) {
+	reg := timeline.NewRegistry()
+	reg.RegisterModelFactory(TextFactory{})
+	id := timeline.EntityID{TurnID: "t1", LocalID: "stdout", Kind: "text"}
+
+	src := timeline.NewController(reg)
+	src.OnCreated(timeline.UIEntityCreated{ID: id, Renderer: timeline.RendererDescriptor{Kind: "text"}, Props: map[string]any{"text": "", "streaming": true}})
+	src.OnUpdated(timeline.UIEntityUpdated{ID: id, Patch: map[string]any{"append": "hello "}, Version: 1})
+	src.OnUpdated(timeline.UIEntityUpdated{ID: id, Patch: map[string]any{"append": "world"}, Version: 2})
+
+	var buf bytes.Buffer
+	require.NoError(t, src.Snapshot(&buf))
+	require.NotContains(t, buf.String(), `"append"`)
+
+	dst := timeline.NewController(reg)
+	require.NoError(t, dst.Restore(&buf))
+	_, _, props, ok := dst.GetSelectedMeta()
+	require.True(t, ok)
+	require.Equal(t, "hello world", props["text"])
+
+	dst.SetSize(80, 10)
+	require.Contains(t, dst.View(), "hello world")
+}
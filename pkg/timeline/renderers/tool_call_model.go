@@ -50,7 +50,7 @@ func (m *ToolCallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *ToolCallModel) View() string {
 	if m.style == nil {
-		m.style = chatstyle.DefaultStyles()
+		m.style = chatstyle.Current()
 	}
 	sty := m.style.UnselectedMessage
 	if m.selected {
@@ -69,8 +69,8 @@ func (m *ToolCallModel) View() string {
 
 	// Use glamour if available
 	rendered := body
-	if m.renderer != nil {
-		if out, err := m.renderer.Render(body + "\n"); err == nil {
+	if r := markdownRenderer(m.renderer); r != nil {
+		if out, err := r.Render(body + "\n"); err == nil {
 			rendered = strings.TrimSpace(out)
 		}
 	}
@@ -79,6 +79,10 @@ func (m *ToolCallModel) View() string {
 }
 
 func (m *ToolCallModel) OnProps(patch map[string]any) {
+	if _, ok := patch["theme"]; ok {
+		// rebuilt from the new theme on the next View
+		m.style = nil
+	}
 	if v, ok := patch["name"].(string); ok {
 		m.name = v
 	}
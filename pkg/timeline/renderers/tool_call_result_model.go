@@ -2,7 +2,6 @@ package renderers
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/go-go-golems/bobatea/pkg/timeline"
 	chatstyle "github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
 	"github.com/rs/zerolog/log"
@@ -43,10 +42,9 @@ func (m *ToolCallResultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *ToolCallResultModel) View() string {
 	if m.style == nil {
-		m.style = chatstyle.DefaultStyles()
+		m.style = chatstyle.Current()
 	}
-	// Light pink foreground
-	pink := lipgloss.Color("212")
+	pink := m.style.Colors.Accent
 	base := m.style.UnselectedMessage.Foreground(pink)
 	if m.selected {
 		base = m.style.SelectedMessage.Foreground(pink)
@@ -58,6 +56,10 @@ func (m *ToolCallResultModel) View() string {
 }
 
 func (m *ToolCallResultModel) OnProps(patch map[string]any) {
+	if _, ok := patch["theme"]; ok {
+		// rebuilt from the new theme on the next View
+		m.style = nil
+	}
 	if v, ok := patch["result"].(string); ok {
 		m.result = v
 	}
@@ -46,7 +46,7 @@ func (m *WebSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *WebSearchModel) View() string {
 	if m.style == nil {
-		m.style = chatstyle.DefaultStyles()
+		m.style = chatstyle.Current()
 	}
 
 	// Header: state + query
@@ -114,6 +114,10 @@ func (m *WebSearchModel) View() string {
 }
 
 func (m *WebSearchModel) OnProps(patch map[string]any) {
+	if _, ok := patch["theme"]; ok {
+		// rebuilt from the new theme on the next View
+		m.style = nil
+	}
 	if v, ok := patch["status"].(string); ok {
 		m.status = v
 	}
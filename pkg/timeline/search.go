@@ -0,0 +1,376 @@
+package timeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SearchMatch is one occurrence of the search query in the text of an entity.
+type SearchMatch struct {
+	// Entity is the position of the entity in the timeline.
+	Entity int
+	ID     EntityID
+	// Occurrence numbers the matches within the entity, starting at 0.
+	Occurrence int
+}
+
+type searchState struct {
+	query   string
+	matches []SearchMatch
+	current int
+	// currentLine is the line of the current match in the last View, or -1.
+	currentLine int
+}
+
+// searchSkippedProps are props that carry rendering state rather than content.
+var searchSkippedProps = map[string]bool{
+	"selected":    true,
+	"focused":     true,
+	"streaming":   true,
+	"theme":       true,
+	"metadata":    true,
+	"interrupted": true,
+}
+
+// searchText flattens the content props of an entity (text, markdown, structured data, …)
+// into one string, walking maps in key order.
+func searchText(props map[string]any) string {
+	var parts []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			parts = append(parts, v)
+		case map[string]any:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(v[k])
+			}
+		case []any:
+			for _, e := range v {
+				walk(e)
+			}
+		case []string:
+			parts = append(parts, v...)
+		default:
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		if !searchSkippedProps[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		walk(props[k])
+	}
+	return strings.Join(parts, "\n")
+}
+
+// smartCase reports whether matching ignores case: it does unless the query has an upper
+// case letter.
+func smartCase(query []rune) bool {
+	for _, r := range query {
+		if unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// findRunes returns the rune offsets of the non-overlapping occurrences of query in text.
+func findRunes(text, query []rune, fold bool) []int {
+	if len(query) == 0 {
+		return nil
+	}
+	eq := func(a, b rune) bool {
+		if fold {
+			return unicode.ToLower(a) == unicode.ToLower(b)
+		}
+		return a == b
+	}
+	var ret []int
+	for i := 0; i+len(query) <= len(text); {
+		ok := true
+		for j := range query {
+			if !eq(text[i+j], query[j]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			ret = append(ret, i)
+			i += len(query)
+			continue
+		}
+		i++
+	}
+	return ret
+}
+
+// SetSearchQuery searches the content of every entity for query and makes the first match
+// at or after the selection current, selecting its entity. Matching ignores case unless
+// the query contains upper case letters. An empty query clears the search.
+func (c *Controller) SetSearchQuery(query string) {
+	if query == "" {
+		c.ClearSearch()
+		return
+	}
+	c.search.query = query
+	c.search.matches = c.findSearchMatches()
+	c.search.current = -1
+	for i, m := range c.search.matches {
+		if m.Entity >= c.selected {
+			c.search.current = i
+			break
+		}
+	}
+	if c.search.current < 0 && len(c.search.matches) > 0 {
+		c.search.current = 0
+	}
+	c.selectSearchMatch()
+	log.Debug().Str("component", "timeline_controller").Str("op", "search").Int("matches", len(c.search.matches)).Msg("search updated")
+}
+
+// ClearSearch drops the query and its highlights.
+func (c *Controller) ClearSearch() {
+	c.search = searchState{current: -1, currentLine: -1}
+}
+
+// SearchQuery returns the active query, empty when not searching.
+func (c *Controller) SearchQuery() string { return c.search.query }
+
+// SearchMatches returns the matches of the active query in timeline order.
+func (c *Controller) SearchMatches() []SearchMatch {
+	return append([]SearchMatch(nil), c.search.matches...)
+}
+
+// SearchIndex returns the position of the current match in SearchMatches, or -1.
+func (c *Controller) SearchIndex() int {
+	if len(c.search.matches) == 0 {
+		return -1
+	}
+	return c.search.current
+}
+
+// SearchNext moves to the next match, wrapping around, and selects its entity.
+func (c *Controller) SearchNext() bool { return c.stepSearch(1) }
+
+// SearchPrev moves to the previous match, wrapping around, and selects its entity.
+func (c *Controller) SearchPrev() bool { return c.stepSearch(-1) }
+
+func (c *Controller) stepSearch(delta int) bool {
+	n := len(c.search.matches)
+	if n == 0 {
+		return false
+	}
+	c.search.current = ((c.search.current+delta)%n + n) % n
+	c.selectSearchMatch()
+	return true
+}
+
+func (c *Controller) selectSearchMatch() {
+	if c.search.current < 0 || c.search.current >= len(c.search.matches) {
+		return
+	}
+	c.selected = c.search.matches[c.search.current].Entity
+}
+
+// SearchMatchLine returns the line of the current match in the output of the last View.
+func (c *Controller) SearchMatchLine() (int, bool) {
+	return c.search.currentLine, c.search.currentLine >= 0
+}
+
+func (c *Controller) findSearchMatches() []SearchMatch {
+	query := []rune(c.search.query)
+	fold := smartCase(query)
+	var ret []SearchMatch
+	for idx, id := range c.store.order {
+		rec, ok := c.store.get(id)
+		if !ok {
+			continue
+		}
+		hits := findRunes([]rune(searchText(rec.Props)), query, fold)
+		for i := range hits {
+			ret = append(ret, SearchMatch{Entity: idx, ID: rec.ID, Occurrence: i})
+		}
+	}
+	return ret
+}
+
+// refreshSearch recomputes the matches after the timeline changed, staying on the current
+// match when it still exists.
+func (c *Controller) refreshSearch() {
+	if c.search.query == "" {
+		return
+	}
+	var prev *SearchMatch
+	if c.search.current >= 0 && c.search.current < len(c.search.matches) {
+		m := c.search.matches[c.search.current]
+		prev = &m
+	}
+	c.search.matches = c.findSearchMatches()
+	c.search.current = min(max(c.search.current, 0), len(c.search.matches)-1)
+	if prev == nil {
+		return
+	}
+	for i, m := range c.search.matches {
+		if keyID(m.ID) == keyID(prev.ID) && m.Occurrence == prev.Occurrence {
+			c.search.current = i
+			return
+		}
+	}
+}
+
+// currentOccurrence returns the occurrence of the current match within the entity at idx,
+// or -1 when the current match is elsewhere.
+func (c *Controller) currentOccurrence(idx int) int {
+	if c.search.current < 0 || c.search.current >= len(c.search.matches) {
+		return -1
+	}
+	m := c.search.matches[c.search.current]
+	if m.Entity != idx {
+		return -1
+	}
+	return m.Occurrence
+}
+
+const (
+	searchMatchOn    = "\x1b[7m"
+	searchMatchOff   = "\x1b[27m"
+	searchCurrentOn  = "\x1b[7;4m"
+	searchCurrentOff = "\x1b[24;27m"
+)
+
+// highlightSearch marks the occurrences of query in a rendered view with reverse video, the
+// one numbered current also underlined. ANSI sequences are skipped when matching and the
+// highlight is re-applied after each sequence inside a match, so styles set by the renderer
+// survive. It returns the line of the current occurrence, or -1. Occurrences split across
+// wrapped lines are not highlighted.
+func highlightSearch(view, query string, current int) (string, int) {
+	q := []rune(query)
+	if len(q) == 0 {
+		return view, -1
+	}
+	fold := smartCase(q)
+	lines := strings.Split(view, "\n")
+	count, currentLine := 0, -1
+	for li, line := range lines {
+		if !strings.ContainsRune(line, '\x1b') && !containsFold(line, query, fold) {
+			continue
+		}
+		plain, offsets := stripANSI(line)
+		hits := findRunes(plain, q, fold)
+		if len(hits) == 0 {
+			continue
+		}
+		var b strings.Builder
+		pos := 0
+		for _, h := range hits {
+			on, off := searchMatchOn, searchMatchOff
+			if count == current {
+				on, off = searchCurrentOn, searchCurrentOff
+				currentLine = li
+			}
+			count++
+			start, end := offsets[h], offsets[h+len(q)-1]+runeLen(line, offsets[h+len(q)-1])
+			b.WriteString(line[pos:start])
+			b.WriteString(on)
+			b.WriteString(reapplyAfterEscapes(line[start:end], on))
+			b.WriteString(off)
+			pos = end
+		}
+		b.WriteString(line[pos:])
+		lines[li] = b.String()
+	}
+	return strings.Join(lines, "\n"), currentLine
+}
+
+func containsFold(s, query string, fold bool) bool {
+	if fold {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(query))
+	}
+	return strings.Contains(s, query)
+}
+
+func runeLen(s string, at int) int {
+	for i := range s[at:] {
+		if i > 0 {
+			return i
+		}
+	}
+	return len(s) - at
+}
+
+// stripANSI returns the visible runes of s and the byte offset of each in s.
+func stripANSI(s string) ([]rune, []int) {
+	runes := make([]rune, 0, len(s))
+	offsets := make([]int, 0, len(s))
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r := []rune(s[i:min(len(s), i+4)])[0]
+		runes = append(runes, r)
+		offsets = append(offsets, i)
+		i += runeLen(s, i)
+	}
+	return runes, offsets
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of s, or 0.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+func reapplyAfterEscapes(s, on string) string {
+	if !strings.ContainsRune(s, '\x1b') {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			b.WriteString(s[i : i+n])
+			b.WriteString(on)
+			i += n
+			continue
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	return b.String()
+}
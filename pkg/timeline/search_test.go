@@ -0,0 +1,145 @@
+package timeline
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+// boldTextModel renders its text prop in bold, one line per entity.
+type boldTextModel struct{ text string }
+
+func (m *boldTextModel) Init() tea.Cmd { return nil }
+func (m *boldTextModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if v, ok := msg.(EntityPropsUpdatedMsg); ok {
+		if s, ok := v.Patch["text"].(string); ok {
+			m.text = s
+		}
+	}
+	return m, nil
+}
+func (m *boldTextModel) View() string { return "\x1b[1m" + m.text + "\x1b[22m" }
+
+type boldTextFactory struct{}
+
+func (boldTextFactory) Key() string  { return "" }
+func (boldTextFactory) Kind() string { return "text" }
+func (boldTextFactory) NewEntityModel(props map[string]any) EntityModel {
+	s, _ := props["text"].(string)
+	return &boldTextModel{text: s}
+}
+
+func newSearchController(texts ...string) *Controller {
+	reg := NewRegistry()
+	reg.RegisterModelFactory(boldTextFactory{})
+	c := NewController(reg)
+	for i, text := range texts {
+		c.OnCreated(UIEntityCreated{
+			ID:       EntityID{LocalID: fmt.Sprint(i), Kind: "text"},
+			Renderer: RendererDescriptor{Kind: "text"},
+			Props:    map[string]any{"text": text, "metadata": map[string]any{"note": "error"}},
+		})
+	}
+	return c
+}
+
+func TestSearchFindsMatchesAcrossEntitiesAndWraps(t *testing.T) {
+	c := newSearchController("an Error here", "nothing", "error, error")
+	c.Select(1)
+
+	c.SetSearchQuery("error")
+	require.Len(t, c.SearchMatches(), 3)
+	// the first match at or after the selection is current
+	require.Equal(t, 1, c.SearchIndex())
+	require.Equal(t, 2, c.SelectedIndex())
+
+	require.True(t, c.SearchNext())
+	require.Equal(t, 2, c.SearchIndex())
+	require.True(t, c.SearchNext())
+	require.Equal(t, 0, c.SearchIndex())
+	require.Equal(t, 0, c.SelectedIndex())
+	require.True(t, c.SearchPrev())
+	require.Equal(t, 2, c.SearchIndex())
+
+	// an upper case letter makes the search case sensitive
+	c.SetSearchQuery("Error")
+	require.Len(t, c.SearchMatches(), 1)
+
+	c.ClearSearch()
+	require.Empty(t, c.SearchMatches())
+	require.False(t, c.SearchNext())
+}
+
+func TestSearchFollowsTimelineUpdates(t *testing.T) {
+	c := newSearchController("foo", "bar")
+	c.SetSearchQuery("foo")
+	require.Len(t, c.SearchMatches(), 1)
+
+	c.OnUpdated(UIEntityUpdated{ID: EntityID{LocalID: "1", Kind: "text"}, Patch: map[string]any{"text": "foo foo"}})
+	require.Len(t, c.SearchMatches(), 3)
+	require.Equal(t, 0, c.SearchIndex())
+
+	c.OnDeleted(UIEntityDeleted{ID: EntityID{LocalID: "0", Kind: "text"}})
+	require.Len(t, c.SearchMatches(), 2)
+	require.Equal(t, 0, c.SearchMatches()[0].Entity)
+}
+
+func TestSearchHighlightsRenderedView(t *testing.T) {
+	c := newSearchController("alpha", "beta beta")
+	c.SetSearchQuery("beta")
+	c.SearchNext()
+
+	view := c.View()
+	lines := strings.Split(view, "\n")
+	require.Equal(t, "\x1b[1malpha\x1b[22m", lines[0])
+	require.Equal(t, "\x1b[1m"+searchMatchOn+"beta"+searchMatchOff+" "+searchCurrentOn+"beta"+searchCurrentOff+"\x1b[22m", lines[1])
+	line, ok := c.SearchMatchLine()
+	require.True(t, ok)
+	require.Equal(t, 1, line)
+}
+
+func TestHighlightSearchReappliesAfterEscapes(t *testing.T) {
+	out, line := highlightSearch("x\nfo\x1b[31mo\x1b[0m", "foo", 0)
+	require.Equal(t, 1, line)
+	require.Equal(t, "x\n"+searchCurrentOn+"fo\x1b[31m"+searchCurrentOn+"o"+searchCurrentOff+"\x1b[0m", out)
+}
+
+func TestShellSearchInput(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterModelFactory(boldTextFactory{})
+	sh := NewShell(reg)
+	sh.SetSize(40, 2)
+	for i := 0; i < 10; i++ {
+		text := "line"
+		if i == 7 {
+			text = "needle"
+		}
+		sh.OnCreated(UIEntityCreated{ID: EntityID{LocalID: fmt.Sprint(i), Kind: "text"}, Renderer: RendererDescriptor{Kind: "text"}, Props: map[string]any{"text": text}})
+	}
+	sh.SetScrollToBottom(false)
+	sh.Select(0)
+
+	sh.StartSearch()
+	require.True(t, sh.SearchInputActive())
+	for _, r := range "needle" {
+		sh.UpdateSearch(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	require.Equal(t, "1/1", sh.SearchStatus())
+	require.Equal(t, 7, sh.SelectedIndex())
+	require.Contains(t, sh.View(), "needle")
+
+	sh.UpdateSearch(tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, sh.SearchInputActive())
+	require.True(t, sh.SearchActive())
+	require.Contains(t, sh.SearchView(), "/needle")
+
+	sh.StartSearch()
+	sh.UpdateSearch(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	require.Equal(t, "no matches", sh.SearchStatus())
+	sh.UpdateSearch(tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, sh.SearchActive())
+	require.Equal(t, 7, sh.SelectedIndex())
+}
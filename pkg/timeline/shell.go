@@ -15,6 +15,7 @@ type Shell struct {
 	viewport       viewport.Model
 	width, height  int
 	scrollToBottom bool
+	search         shellSearch
 }
 
 // NewShell constructs a Shell with a fresh Controller backed by the provided registry.
@@ -23,6 +24,7 @@ func NewShell(reg *Registry) *Shell {
 		ctrl:           NewController(reg),
 		viewport:       viewport.New(0, 0),
 		scrollToBottom: true,
+		search:         newShellSearch(),
 	}
 }
 
@@ -108,6 +110,16 @@ func (s *Shell) SelectLast() { s.ctrl.SelectLast(); s.RefreshView(false) }
 func (s *Shell) SelectNext() { s.ctrl.SelectNext(); s.ScrollToSelected() }
 func (s *Shell) SelectPrev() { s.ctrl.SelectPrev(); s.ScrollToSelected() }
 
+// Select selects the entity at index i and scrolls it into view.
+func (s *Shell) Select(i int) bool {
+	ok := s.ctrl.Select(i)
+	s.ScrollToSelected()
+	return ok
+}
+
+// Clear removes all entities and refreshes the view.
+func (s *Shell) Clear() { s.ctrl.Clear(); s.RefreshView(false) }
+
 func (s *Shell) EnterSelection()  { s.ctrl.EnterSelection(); s.RefreshView(false) }
 func (s *Shell) ExitSelection()   { s.ctrl.ExitSelection(); s.RefreshView(false) }
 func (s *Shell) IsEntering() bool { return s.ctrl.IsEntering() }
@@ -0,0 +1,143 @@
+package timeline
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/timeline/chatstyle"
+)
+
+// shellSearch is the search mode of a Shell: a query input while typing, then match
+// navigation until the search is cleared.
+type shellSearch struct {
+	input  textinput.Model
+	typing bool
+	// restore is the selection to go back to when the search is cancelled.
+	restore int
+}
+
+func newShellSearch() shellSearch {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "search timeline"
+	return shellSearch{input: input, restore: -1}
+}
+
+// StartSearch opens the search input. Typing updates the matches incrementally, enter
+// confirms the query and esc cancels the search.
+func (s *Shell) StartSearch() tea.Cmd {
+	s.search.typing = true
+	s.search.restore = s.ctrl.SelectedIndex()
+	s.search.input.SetValue(s.ctrl.SearchQuery())
+	s.search.input.CursorEnd()
+	return s.search.input.Focus()
+}
+
+// SearchInputActive reports whether the search query is being typed.
+func (s *Shell) SearchInputActive() bool { return s.search.typing }
+
+// SearchActive reports whether a search is open, either typing or navigating matches.
+func (s *Shell) SearchActive() bool { return s.search.typing || s.ctrl.SearchQuery() != "" }
+
+// UpdateSearch handles a key while the search input is active.
+func (s *Shell) UpdateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		s.search.typing = false
+		s.search.input.Blur()
+		if s.ctrl.SearchQuery() == "" {
+			s.RefreshView(false)
+		}
+		return nil
+	case tea.KeyEsc:
+		restore := s.search.restore
+		s.ClearSearch()
+		if restore >= 0 {
+			s.Select(restore)
+		}
+		return nil
+	}
+	var cmd tea.Cmd
+	before := s.search.input.Value()
+	s.search.input, cmd = s.search.input.Update(msg)
+	if q := s.search.input.Value(); q != before {
+		if q == "" {
+			s.ctrl.ClearSearch()
+		} else {
+			s.ctrl.SetSearchQuery(q)
+		}
+		s.scrollToSearchMatch()
+	}
+	return cmd
+}
+
+// SearchNext jumps to the next match, wrapping around.
+func (s *Shell) SearchNext() bool {
+	ok := s.ctrl.SearchNext()
+	s.scrollToSearchMatch()
+	return ok
+}
+
+// SearchPrev jumps to the previous match, wrapping around.
+func (s *Shell) SearchPrev() bool {
+	ok := s.ctrl.SearchPrev()
+	s.scrollToSearchMatch()
+	return ok
+}
+
+// ClearSearch closes the search and removes the highlights.
+func (s *Shell) ClearSearch() {
+	s.search.typing = false
+	s.search.restore = -1
+	s.search.input.Blur()
+	s.search.input.SetValue("")
+	s.ctrl.ClearSearch()
+	s.RefreshView(false)
+}
+
+// SearchStatus returns the position among the matches, e.g. "3/17", or "no matches".
+func (s *Shell) SearchStatus() string {
+	if s.ctrl.SearchQuery() == "" {
+		return ""
+	}
+	n := len(s.ctrl.search.matches)
+	if n == 0 {
+		return "no matches"
+	}
+	return fmt.Sprintf("%d/%d", s.ctrl.SearchIndex()+1, n)
+}
+
+// SearchView renders the search bar: the query input, or the confirmed query, followed by
+// the match count.
+func (s *Shell) SearchView() string {
+	colors := chatstyle.Current().Colors
+	query := s.search.input.View()
+	if !s.search.typing {
+		query = "/" + s.ctrl.SearchQuery()
+	}
+	status := lipgloss.NewStyle().Foreground(colors.Muted).Render(s.SearchStatus())
+	if s.ctrl.SearchQuery() != "" && len(s.ctrl.search.matches) == 0 {
+		status = lipgloss.NewStyle().Foreground(colors.Warning).Render(s.SearchStatus())
+	}
+	bar := query + "  " + status
+	if s.width > 0 {
+		bar = lipgloss.NewStyle().MaxWidth(s.width).Render(bar)
+	}
+	return bar
+}
+
+// scrollToSearchMatch re-renders the timeline and, when the current match is off-screen,
+// centers its line in the viewport. Matches found only in props that the renderer does not
+// show fall back to scrolling the selected entity into view.
+func (s *Shell) scrollToSearchMatch() {
+	s.ScrollToSelected()
+	line, ok := s.ctrl.SearchMatchLine()
+	if !ok {
+		return
+	}
+	if line < s.viewport.YOffset || line >= s.viewport.YOffset+s.viewport.Height {
+		s.viewport.SetYOffset(max(line-s.viewport.Height/2, 0))
+	}
+}
@@ -9,6 +9,7 @@ type entityRecord struct {
 	ID        EntityID
 	Renderer  RendererDescriptor
 	Props     map[string]any
+	Labels    map[string]string
 	StartedAt int64
 	UpdatedAt int64
 	Version   int64
@@ -0,0 +1,255 @@
+package timeline
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Envelope types for timeline lifecycle events. They are shared by the
+// ui.entities bus topic and by JSONL transcripts, so a transcript line and a
+// bus payload are interchangeable.
+const (
+	EnvelopeCreated   = "timeline.created"
+	EnvelopeUpdated   = "timeline.updated"
+	EnvelopeCompleted = "timeline.completed"
+	EnvelopeDeleted   = "timeline.deleted"
+)
+
+// Envelope wraps a lifecycle event together with its type tag.
+type Envelope struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// EncodeEnvelope wraps a UIEntityCreated/Updated/Completed/Deleted value (or a pointer to one) in an Envelope.
+func EncodeEnvelope(ev any) (Envelope, error) {
+	var typ string
+	switch ev.(type) {
+	case UIEntityCreated, *UIEntityCreated:
+		typ = EnvelopeCreated
+	case UIEntityUpdated, *UIEntityUpdated:
+		typ = EnvelopeUpdated
+	case UIEntityCompleted, *UIEntityCompleted:
+		typ = EnvelopeCompleted
+	case UIEntityDeleted, *UIEntityDeleted:
+		typ = EnvelopeDeleted
+	default:
+		return Envelope{}, errors.Errorf("unsupported timeline event %T", ev)
+	}
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return Envelope{}, errors.Wrapf(err, "marshal %s", typ)
+	}
+	return Envelope{Type: typ, Payload: b}, nil
+}
+
+// DecodeEnvelope returns the typed lifecycle event carried by env.
+func DecodeEnvelope(env Envelope) (any, error) {
+	var (
+		ev  any
+		err error
+	)
+	switch env.Type {
+	case EnvelopeCreated:
+		var e UIEntityCreated
+		err = json.Unmarshal(env.Payload, &e)
+		ev = e
+	case EnvelopeUpdated:
+		var e UIEntityUpdated
+		err = json.Unmarshal(env.Payload, &e)
+		ev = e
+	case EnvelopeCompleted:
+		var e UIEntityCompleted
+		err = json.Unmarshal(env.Payload, &e)
+		ev = e
+	case EnvelopeDeleted:
+		var e UIEntityDeleted
+		err = json.Unmarshal(env.Payload, &e)
+		ev = e
+	default:
+		return nil, errors.Errorf("unknown envelope type %q", env.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshal %s", env.Type)
+	}
+	return ev, nil
+}
+
+// TranscriptWriter appends lifecycle events to a writer as JSONL, one Envelope per line.
+// It is safe for concurrent use.
+type TranscriptWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewTranscriptWriter creates a TranscriptWriter that writes to w.
+func NewTranscriptWriter(w io.Writer) *TranscriptWriter {
+	return &TranscriptWriter{enc: json.NewEncoder(w)}
+}
+
+// Write appends a single lifecycle event to the transcript.
+func (t *TranscriptWriter) Write(ev any) error {
+	env, err := EncodeEnvelope(ev)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return errors.Wrap(t.enc.Encode(env), "write transcript event")
+}
+
+// ReadTranscript decodes a JSONL transcript and calls fn with each typed lifecycle event in order.
+// Blank lines are skipped; decoding stops at the first malformed line.
+func ReadTranscript(r io.Reader, fn func(ev any) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		b := sc.Bytes()
+		if len(bytes.TrimSpace(b)) == 0 {
+			continue
+		}
+		var env Envelope
+		if err := json.Unmarshal(b, &env); err != nil {
+			return errors.Wrapf(err, "transcript line %d", line)
+		}
+		ev, err := DecodeEnvelope(env)
+		if err != nil {
+			return errors.Wrapf(err, "transcript line %d", line)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(sc.Err(), "read transcript")
+}
+
+// SetRecorder attaches a TranscriptWriter that receives every lifecycle event applied to the
+// controller. Pass nil to stop recording.
+func (c *Controller) SetRecorder(w *TranscriptWriter) { c.recorder = w }
+
+// Apply dispatches a typed lifecycle event to the matching On* method.
+// It returns false if ev is not a timeline lifecycle event.
+func (c *Controller) Apply(ev any) bool {
+	switch e := ev.(type) {
+	case UIEntityCreated:
+		c.OnCreated(e)
+	case UIEntityUpdated:
+		c.OnUpdated(e)
+	case UIEntityCompleted:
+		c.OnCompleted(e)
+	case UIEntityDeleted:
+		c.OnDeleted(e)
+	default:
+		return false
+	}
+	return true
+}
+
+// Snapshot writes the current entity store as a compacted JSONL transcript: one created event
+// carrying the merged props per entity, followed by an updated event restoring the version and a
+// completed event when applicable. Entities are written in timeline order.
+//
+// Streamed "append" patches are folded in through EntitySnapshotter. Props go through JSON, so
+// typed values such as structs come back as map[string]any; renderers decode those themselves.
+func (c *Controller) Snapshot(w io.Writer) error {
+	tw := NewTranscriptWriter(w)
+	for _, id := range c.store.order {
+		rec, ok := c.store.get(id)
+		if !ok {
+			continue
+		}
+		created := UIEntityCreated{
+			ID:        rec.ID,
+			Renderer:  rec.Renderer,
+			Props:     snapshotProps(rec),
+			StartedAt: timeFromUnixNano(rec.StartedAt),
+			Labels:    rec.Labels,
+		}
+		if err := tw.Write(created); err != nil {
+			return err
+		}
+		if rec.Version != 0 || rec.UpdatedAt != 0 {
+			updated := UIEntityUpdated{ID: rec.ID, Patch: map[string]any{}, Version: rec.Version, UpdatedAt: timeFromUnixNano(rec.UpdatedAt)}
+			if err := tw.Write(updated); err != nil {
+				return err
+			}
+		}
+		if rec.Completed {
+			if err := tw.Write(UIEntityCompleted{ID: rec.ID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotProps returns the props that recreate the entity of rec. The last "append" chunk is
+// dropped since the model reports the accumulated content.
+func snapshotProps(rec *entityRecord) map[string]any {
+	props := cloneMap(rec.Props)
+	delete(props, "append")
+	if s, ok := rec.model.(EntitySnapshotter); ok {
+		applyPatch(props, s.SnapshotProps())
+	}
+	return props
+}
+
+// Restore replays a transcript (either a Snapshot or a recorded event log) into the controller.
+// Events are applied on top of the current store; use a fresh controller to rebuild a session.
+func (c *Controller) Restore(r io.Reader) error {
+	return ReadTranscript(r, func(ev any) error {
+		c.Apply(ev)
+		return nil
+	})
+}
+
+// Snapshot writes the timeline state as a JSONL transcript. See Controller.Snapshot.
+func (s *Shell) Snapshot(w io.Writer) error { return s.ctrl.Snapshot(w) }
+
+// Restore replays a transcript into the shell and scrolls to the bottom.
+func (s *Shell) Restore(r io.Reader) error {
+	err := s.ctrl.Restore(r)
+	s.RefreshView(true)
+	return err
+}
+
+// NewShellFromTranscript rebuilds a Shell from a transcript using the provided registry.
+func NewShellFromTranscript(reg *Registry, r io.Reader) (*Shell, error) {
+	s := NewShell(reg)
+	if err := s.ctrl.Restore(r); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (c *Controller) record(ev any) {
+	if c.recorder == nil {
+		return
+	}
+	if err := c.recorder.Write(ev); err != nil {
+		log.Warn().Err(err).Str("component", "timeline_controller").Msg("failed to record transcript event")
+	}
+}
+
+func unixNanoOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func timeFromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
@@ -0,0 +1,74 @@
+package timeline
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func seedController(c *Controller) {
+	started := time.Unix(1700000000, 0)
+	a := EntityID{TurnID: "t1", LocalID: "input", Kind: "text"}
+	b := EntityID{TurnID: "t1", LocalID: "stdout", Kind: "text"}
+	gone := EntityID{TurnID: "t1", LocalID: "tmp", Kind: "text"}
+	c.OnCreated(UIEntityCreated{ID: a, Renderer: RendererDescriptor{Kind: "text"}, Props: map[string]any{"text": "1+1"}, StartedAt: started, Labels: map[string]string{"role": "user"}})
+	c.OnCreated(UIEntityCreated{ID: gone, Renderer: RendererDescriptor{Kind: "text"}, Props: map[string]any{"text": "x"}, StartedAt: started})
+	c.OnCreated(UIEntityCreated{ID: b, Renderer: RendererDescriptor{Kind: "text"}, Props: map[string]any{"text": "", "streaming": true}, StartedAt: started})
+	c.OnUpdated(UIEntityUpdated{ID: b, Patch: map[string]any{"text": "2"}, Version: 7, UpdatedAt: started.Add(time.Second)})
+	c.OnCompleted(UIEntityCompleted{ID: b, Result: map[string]any{"streaming": false}})
+	c.OnDeleted(UIEntityDeleted{ID: gone})
+}
+
+func assertSeeded(t *testing.T, c *Controller) {
+	t.Helper()
+	require.Len(t, c.store.order, 2)
+	a, ok := c.store.get(c.store.order[0])
+	require.True(t, ok)
+	require.Equal(t, "1+1", a.Props["text"])
+	require.Equal(t, "user", a.Labels["role"])
+	require.False(t, a.Completed)
+
+	b, ok := c.store.get(c.store.order[1])
+	require.True(t, ok)
+	require.Equal(t, "2", b.Props["text"])
+	require.Equal(t, false, b.Props["streaming"])
+	require.Equal(t, int64(7), b.Version)
+	require.True(t, b.Completed)
+	require.Equal(t, time.Unix(1700000000, 0).UnixNano(), b.StartedAt)
+}
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	src := NewController(NewRegistry())
+	seedController(src)
+
+	var buf bytes.Buffer
+	require.NoError(t, src.Snapshot(&buf))
+
+	dst := NewController(NewRegistry())
+	require.NoError(t, dst.Restore(&buf))
+	assertSeeded(t, dst)
+}
+
+func TestRecorderReplaysEventLog(t *testing.T) {
+	var buf bytes.Buffer
+	src := NewController(NewRegistry())
+	src.SetRecorder(NewTranscriptWriter(&buf))
+	seedController(src)
+
+	// one line per applied lifecycle event
+	require.Equal(t, 6, strings.Count(buf.String(), "\n"))
+
+	sh, err := NewShellFromTranscript(NewRegistry(), &buf)
+	require.NoError(t, err)
+	assertSeeded(t, sh.Controller())
+}
+
+func TestReadTranscriptRejectsUnknownType(t *testing.T) {
+	in := "\n{\"type\":\"timeline.bogus\",\"payload\":{}}\n"
+	err := ReadTranscript(strings.NewReader(in), func(any) error { return nil })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 2")
+}
@@ -12,34 +12,13 @@ import (
 func RegisterUIForwarder(bus *eventbus.Bus, p *tea.Program) {
 	bus.AddHandler("ui-forward", eventbus.TopicUIEntities, func(msg *message.Message) error {
 		defer msg.Ack()
-		var env struct {
-			Type    string          `json:"type"`
-			Payload json.RawMessage `json:"payload"`
-		}
+		var env Envelope
 		if err := json.Unmarshal(msg.Payload, &env); err != nil {
 			return err
 		}
-		switch env.Type {
-		case "timeline.created":
-			var e UIEntityCreated
-			if err := json.Unmarshal(env.Payload, &e); err == nil {
-				p.Send(e)
-			}
-		case "timeline.updated":
-			var e UIEntityUpdated
-			if err := json.Unmarshal(env.Payload, &e); err == nil {
-				p.Send(e)
-			}
-		case "timeline.completed":
-			var e UIEntityCompleted
-			if err := json.Unmarshal(env.Payload, &e); err == nil {
-				p.Send(e)
-			}
-		case "timeline.deleted":
-			var e UIEntityDeleted
-			if err := json.Unmarshal(env.Payload, &e); err == nil {
-				p.Send(e)
-			}
+		// Unknown or malformed lifecycle payloads are dropped, matching the bus contract.
+		if ev, err := DecodeEnvelope(env); err == nil {
+			p.Send(ev)
 		}
 		return nil
 	})
@@ -1,3 +1,4 @@
 // Package inputhistory provides a reusable bounded input history state machine
-// with up/down navigation semantics for terminal input widgets.
+// with up/down navigation semantics, reverse search and optional file persistence
+// for terminal input widgets.
 package inputhistory
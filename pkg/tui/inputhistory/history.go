@@ -1,6 +1,24 @@
 package inputhistory
 
-import "slices"
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DedupPolicy controls how repeated inputs are recorded.
+type DedupPolicy string
+
+const (
+	// DedupConsecutive skips an input equal to the previous one (the default).
+	DedupConsecutive DedupPolicy = "consecutive"
+	// DedupAll moves a repeated input to the end, dropping its earlier occurrence.
+	DedupAll DedupPolicy = "all"
+	// DedupNone records every input.
+	DedupNone DedupPolicy = "none"
+)
 
 // HistoryEntry represents a single entry in input history.
 type HistoryEntry struct {
@@ -15,6 +33,8 @@ type History struct {
 	inputHistory []string
 	currentIndex int
 	maxSize      int
+	dedup        DedupPolicy
+	store        Store
 }
 
 // NewHistory creates a new history manager.
@@ -40,16 +60,61 @@ func (h *History) Add(input, output string, isErr bool) {
 		h.entries = h.entries[1:]
 	}
 
-	if input != "" && (len(h.inputHistory) == 0 || h.inputHistory[len(h.inputHistory)-1] != input) {
-		h.inputHistory = append(h.inputHistory, input)
-		if len(h.inputHistory) > h.maxSize {
-			h.inputHistory = h.inputHistory[1:]
+	if h.appendInput(input) && h.store != nil {
+		if err := h.store.Append(input); err != nil {
+			log.Warn().Err(err).Msg("could not persist input history")
 		}
 	}
 
 	h.currentIndex = -1
 }
 
+// appendInput records input according to the dedup policy and reports whether it was added.
+func (h *History) appendInput(input string) bool {
+	if input == "" {
+		return false
+	}
+	switch h.dedup {
+	case DedupNone:
+	case DedupAll:
+		h.inputHistory = slices.DeleteFunc(h.inputHistory, func(s string) bool { return s == input })
+	case DedupConsecutive, "":
+		if len(h.inputHistory) > 0 && h.inputHistory[len(h.inputHistory)-1] == input {
+			return false
+		}
+	}
+	h.inputHistory = append(h.inputHistory, input)
+	if len(h.inputHistory) > h.maxSize {
+		h.inputHistory = h.inputHistory[len(h.inputHistory)-h.maxSize:]
+	}
+	return true
+}
+
+// SetDedupPolicy changes how subsequent inputs are recorded.
+func (h *History) SetDedupPolicy(p DedupPolicy) {
+	h.dedup = p
+}
+
+// Attach loads the inputs saved in store and persists subsequent inputs to it. When the store
+// holds noticeably more lines than survive deduplication and the size bound, it is compacted.
+func (h *History) Attach(store Store) error {
+	inputs, err := store.Load()
+	if err != nil {
+		return err
+	}
+	h.store = nil
+	h.inputHistory = h.inputHistory[:0]
+	for _, in := range inputs {
+		h.appendInput(in)
+	}
+	h.store = store
+	h.currentIndex = -1
+	if len(inputs) > 2*len(h.inputHistory) && len(inputs) > 100 {
+		return store.Rewrite(slices.Clone(h.inputHistory))
+	}
+	return nil
+}
+
 // NavigateUp moves up in history (to older entries).
 func (h *History) NavigateUp() string {
 	if len(h.inputHistory) == 0 {
@@ -85,6 +150,11 @@ func (h *History) Clear() {
 	h.entries = make([]HistoryEntry, 0)
 	h.inputHistory = make([]string, 0)
 	h.currentIndex = -1
+	if h.store != nil {
+		if err := h.store.Rewrite(nil); err != nil {
+			log.Warn().Err(err).Msg("could not clear persisted input history")
+		}
+	}
 }
 
 // GetEntries returns all history entries.
@@ -106,3 +176,54 @@ func (h *History) IsNavigating() bool {
 func (h *History) ResetNavigation() {
 	h.currentIndex = -1
 }
+
+// SearchMatch is an input history entry matching a search query.
+type SearchMatch struct {
+	// Index is the position in GetAll()
+	Index int
+	Input string
+	// Start and End are the byte offsets of the first match in Input
+	Start, End int
+}
+
+// Search returns entries containing query, newest first, considering only entries older than
+// before (pass -1 to search everything). The match is case-insensitive unless query contains
+// an upper-case letter. limit <= 0 returns all matches.
+func (h *History) Search(query string, before int, limit int) []SearchMatch {
+	if before < 0 || before > len(h.inputHistory) {
+		before = len(h.inputHistory)
+	}
+	fold := !strings.ContainsFunc(query, unicode.IsUpper)
+	var out []SearchMatch
+	for i := before - 1; i >= 0; i-- {
+		in := h.inputHistory[i]
+		start, end := matchIndex(in, query, fold)
+		if start < 0 {
+			continue
+		}
+		out = append(out, SearchMatch{Index: i, Input: in, Start: start, End: end})
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+	return out
+}
+
+func matchIndex(s, sub string, fold bool) (int, int) {
+	if !fold {
+		i := strings.Index(s, sub)
+		if i < 0 {
+			return -1, -1
+		}
+		return i, i + len(sub)
+	}
+	for i := range s {
+		if j := i + len(sub); j <= len(s) && strings.EqualFold(s[i:j], sub) {
+			return i, j
+		}
+	}
+	if sub == "" {
+		return 0, 0
+	}
+	return -1, -1
+}
@@ -1,6 +1,10 @@
 package inputhistory
 
-import "testing"
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
 
 func TestHistory(t *testing.T) {
 	history := NewHistory(5)
@@ -46,3 +50,71 @@ func TestHistory(t *testing.T) {
 		t.Fatalf("expected no entries after clear")
 	}
 }
+
+func TestHistoryDedupPolicies(t *testing.T) {
+	h := NewHistory(10)
+	for _, in := range []string{"a", "a", "b", "a"} {
+		h.Add(in, "", false)
+	}
+	if got := h.GetAll(); !slices.Equal(got, []string{"a", "b", "a"}) {
+		t.Fatalf("consecutive: got %q", got)
+	}
+
+	h = NewHistory(10)
+	h.SetDedupPolicy(DedupAll)
+	for _, in := range []string{"a", "b", "a"} {
+		h.Add(in, "", false)
+	}
+	if got := h.GetAll(); !slices.Equal(got, []string{"b", "a"}) {
+		t.Fatalf("all: got %q", got)
+	}
+}
+
+func TestHistoryFileStorePersistsAcrossSessions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "js.jsonl")
+
+	h := NewHistory(3)
+	if err := h.Attach(NewFileStore(path)); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	for _, in := range []string{"one", "two\nlines", "three", "four"} {
+		h.Add(in, "", false)
+	}
+
+	next := NewHistory(3)
+	if err := next.Attach(NewFileStore(path)); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	if got := next.GetAll(); !slices.Equal(got, []string{"two\nlines", "three", "four"}) {
+		t.Fatalf("got %q", got)
+	}
+
+	next.Clear()
+	inputs, err := NewFileStore(path).Load()
+	if err != nil || len(inputs) != 0 {
+		t.Fatalf("expected empty store after clear, got %q (%v)", inputs, err)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	h := NewHistory(10)
+	for _, in := range []string{"print(x)", "let y", "Print(y)", "z"} {
+		h.Add(in, "", false)
+	}
+
+	matches := h.Search("print", -1, 0)
+	if len(matches) != 2 || matches[0].Input != "Print(y)" || matches[1].Input != "print(x)" {
+		t.Fatalf("unexpected matches: %#v", matches)
+	}
+	if matches[0].Start != 0 || matches[0].End != 5 {
+		t.Fatalf("unexpected span: %#v", matches[0])
+	}
+
+	// upper-case query is case-sensitive; before restricts to older entries
+	if matches := h.Search("Print", -1, 0); len(matches) != 1 {
+		t.Fatalf("expected one case-sensitive match, got %#v", matches)
+	}
+	if matches := h.Search("print", 2, 0); len(matches) != 1 || matches[0].Index != 0 {
+		t.Fatalf("expected only older match, got %#v", matches)
+	}
+}
@@ -0,0 +1,138 @@
+package inputhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Store persists input history across sessions.
+type Store interface {
+	// Load returns saved inputs, oldest first.
+	Load() ([]string, error)
+	// Append saves one input.
+	Append(input string) error
+	// Rewrite replaces the saved inputs.
+	Rewrite(inputs []string) error
+}
+
+// FileStore keeps inputs in a file, one JSON-encoded string per line so multiline inputs
+// survive the round trip.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore returns a store backed by path. The file and its directory are created on the
+// first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Path returns the file backing the store.
+func (s *FileStore) Path() string { return s.path }
+
+func (s *FileStore) Load() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "open history file")
+	}
+	defer func() { _ = f.Close() }()
+
+	var out []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var in string
+		if err := json.Unmarshal(line, &in); err != nil {
+			// tolerate hand-edited files with plain lines
+			in = string(line)
+		}
+		out = append(out, in)
+	}
+	return out, errors.Wrap(sc.Err(), "read history file")
+}
+
+func (s *FileStore) Append(input string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return errors.Wrap(err, "create history directory")
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "open history file")
+	}
+	b, _ := json.Marshal(input)
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "append history file")
+	}
+	return errors.Wrap(f.Close(), "close history file")
+}
+
+func (s *FileStore) Rewrite(inputs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return errors.Wrap(err, "create history directory")
+	}
+	var b strings.Builder
+	for _, in := range inputs {
+		line, _ := json.Marshal(in)
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return errors.Wrap(err, "write history file")
+	}
+	return errors.Wrap(os.Rename(tmp, s.path), "replace history file")
+}
+
+// DefaultFilePath returns the history file for a named input, e.g. an evaluator name, under
+// $XDG_STATE_HOME/bobatea/history (~/.local/state/bobatea/history by default).
+func DefaultFilePath(name string) (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.Wrap(err, "resolve history directory")
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "bobatea", "history", sanitizeName(name)+".jsonl"), nil
+}
+
+func sanitizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "default"
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package suggest
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/sahilm/fuzzy"
+)
+
+type suggestionSource []autocomplete.Suggestion
+
+func (s suggestionSource) String(i int) string { return s[i].DisplayText }
+func (s suggestionSource) Len() int            { return len(s) }
+
+// FilterSuggestions fuzzy-matches query against the display text of suggestions and returns the
+// matches best first, with Submatches set to the matched runes. An empty query returns the
+// suggestions unchanged.
+func FilterSuggestions(suggestions []autocomplete.Suggestion, query string) []autocomplete.Suggestion {
+	if query == "" {
+		return suggestions
+	}
+	matches := fuzzy.FindFrom(query, suggestionSource(suggestions))
+	ret := make([]autocomplete.Suggestion, 0, len(matches))
+	for _, match := range matches {
+		s := suggestions[match.Index]
+		s.Submatches = matchSpans(s.DisplayText, match.MatchedIndexes)
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+// matchSpans converts matched byte offsets into rune spans, merging adjacent runes.
+func matchSpans(text string, byteIndexes []int) []autocomplete.Span {
+	var spans []autocomplete.Span
+	for _, idx := range byteIndexes {
+		r := utf8.RuneCountInString(text[:idx])
+		if n := len(spans); n > 0 && spans[n-1].End == r {
+			spans[n-1].End++
+			continue
+		}
+		spans = append(spans, autocomplete.Span{Start: r, End: r + 1})
+	}
+	return spans
+}
+
+// refilter narrows the provider's last result to the current input when the edit stays inside
+// the replace range the result was computed for. It returns false when the provider has to be
+// asked again.
+func (w *Widget) refilter(value string, cursor int) bool {
+	if !w.hasBase {
+		return false
+	}
+	prefix := w.baseInput[:w.baseFrom]
+	suffix := w.baseInput[w.baseTo:]
+	if !strings.HasPrefix(value, prefix) || !strings.HasSuffix(value, suffix) {
+		return false
+	}
+	to := len(value) - len(suffix)
+	if to < w.baseFrom || cursor < w.baseFrom || cursor > to {
+		return false
+	}
+	query := value[w.baseFrom:to]
+	if strings.ContainsAny(query, " \t\n") {
+		// the user moved on to the next token
+		return false
+	}
+
+	filtered := w.base
+	filtered.Suggestions = FilterSuggestions(w.base.Suggestions, query)
+	w.lastResult = filtered
+	w.replaceFrom = w.baseFrom
+	w.replaceTo = to
+	w.selection = 0
+	w.scrollTop = 0
+	w.visible = len(filtered.Suggestions) > 0
+	return true
+}
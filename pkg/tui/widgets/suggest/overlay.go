@@ -6,6 +6,13 @@ import (
 )
 
 func (w *Widget) ComputeOverlayLayout(width int, height int, headerHeight int, timelineHeight int, prompt string, input string, cursor int, popupStyle lipgloss.Style) (OverlayLayout, bool) {
+	inputY := headerHeight + 1 + timelineHeight
+	return w.ComputeOverlayLayoutAt(width, height, inputY, completionAnchorColumn(prompt, input, cursor), popupStyle)
+}
+
+// ComputeOverlayLayoutAt places the popup relative to an explicit anchor: inputY is the screen
+// row of the cursor and anchorX its column. Use it for inputs spanning several rows.
+func (w *Widget) ComputeOverlayLayoutAt(width int, height int, inputY int, anchorX int, popupStyle lipgloss.Style) (OverlayLayout, bool) {
 	if !w.visible || width <= 0 || height <= 0 {
 		return OverlayLayout{}, false
 	}
@@ -14,13 +21,13 @@ func (w *Widget) ComputeOverlayLayout(width int, height int, headerHeight int, t
 		return OverlayLayout{}, false
 	}
 
-	inputY := headerHeight + 1 + timelineHeight
 	frameWidth := popupStyle.GetHorizontalFrameSize()
 	frameHeight := popupStyle.GetVerticalFrameSize()
 
+	_, iconWidth, detailWidth := w.itemColumns(suggestions)
 	contentWidth := 1
 	for _, suggestion := range suggestions {
-		itemWidth := runewidth.StringWidth("  " + suggestion.DisplayText)
+		itemWidth := rowWidth(runewidth.StringWidth(suggestion.DisplayText), iconWidth, detailWidth)
 		if itemWidth > contentWidth {
 			contentWidth = itemWidth
 		}
@@ -89,7 +96,6 @@ func (w *Widget) ComputeOverlayLayout(width int, height int, headerHeight int, t
 		return OverlayLayout{}, false
 	}
 
-	anchorX := completionAnchorColumn(prompt, input, cursor)
 	popupX := anchorX
 	if w.horizontal == HorizontalGrowLeft {
 		popupX -= popupWidth
@@ -105,6 +111,64 @@ func (w *Widget) ComputeOverlayLayout(width int, height int, headerHeight int, t
 		PopupWidth:   popupWidth,
 		VisibleRows:  visibleRows,
 		ContentWidth: contentWidth,
+		Above:        popupY < inputY,
+	}, true
+}
+
+// ComputeDocumentationLayout places the documentation pane of the selected suggestion beside the
+// rendered popup: to the right when it fits, otherwise to the left. Above popups keep their
+// bottom edge aligned so the pane does not cover the input.
+func (w *Widget) ComputeDocumentationLayout(width int, height int, popup OverlayLayout, popupView string, styles Styles) (DocumentationLayout, bool) {
+	if w.docWidth <= 0 || popupView == "" || width <= 0 || height <= 0 {
+		return DocumentationLayout{}, false
+	}
+	style := w.documentationStyle(styles)
+	popupWidth := lipgloss.Width(popupView)
+	popupHeight := lipgloss.Height(popupView)
+
+	docWidth := w.docWidth
+	x := popup.PopupX + popupWidth
+	if x+docWidth > width {
+		x = popup.PopupX - docWidth
+		if x < 0 {
+			// not enough room on either side; shrink into the wider gap
+			right := width - (popup.PopupX + popupWidth)
+			if right >= popup.PopupX {
+				x, docWidth = popup.PopupX+popupWidth, right
+			} else {
+				x, docWidth = 0, popup.PopupX
+			}
+		}
+	}
+	innerWidth := docWidth - style.GetHorizontalFrameSize()
+	if innerWidth < minItemWidth {
+		return DocumentationLayout{}, false
+	}
+	lines := w.documentationLines(innerWidth)
+	if len(lines) == 0 {
+		return DocumentationLayout{}, false
+	}
+
+	maxHeight := w.maxHeight
+	if maxHeight <= 0 {
+		maxHeight = height
+	}
+	docHeight := len(lines) + style.GetVerticalFrameSize()
+	y := popup.PopupY
+	if popup.Above {
+		docHeight = minInt(docHeight, maxInt(popupHeight, minInt(maxHeight, popup.PopupY+popupHeight)))
+		y = popup.PopupY + popupHeight - docHeight
+	} else {
+		docHeight = minInt(docHeight, maxInt(popupHeight, minInt(maxHeight, height-popup.PopupY)))
+	}
+	if docHeight <= style.GetVerticalFrameSize() {
+		return DocumentationLayout{}, false
+	}
+	return DocumentationLayout{
+		X:      x,
+		Y:      clampInt(y, 0, maxInt(0, height-1)),
+		Width:  docWidth,
+		Height: docHeight,
 	}, true
 }
 
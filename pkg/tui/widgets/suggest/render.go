@@ -4,7 +4,16 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
 	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const (
+	// maxDetailWidth caps the detail column so long signatures don't squeeze the items.
+	maxDetailWidth = 24
+	// minItemWidth is the width kept for the display text before the detail column shrinks.
+	minItemWidth = 8
 )
 
 func (w *Widget) PopupStyle(baseStyle lipgloss.Style) lipgloss.Style {
@@ -16,6 +25,35 @@ func (w *Widget) PopupStyle(baseStyle lipgloss.Style) lipgloss.Style {
 		Padding(0, 0)
 }
 
+func (w *Widget) iconFor(s autocomplete.Suggestion) string {
+	if s.Icon != "" {
+		return s.Icon
+	}
+	return w.icons[s.Kind]
+}
+
+// itemColumns measures the text, icon and detail columns shared by every row of the popup.
+func (w *Widget) itemColumns(suggestions []autocomplete.Suggestion) (textWidth int, iconWidth int, detailWidth int) {
+	for _, s := range suggestions {
+		textWidth = maxInt(textWidth, runewidth.StringWidth(s.DisplayText))
+		iconWidth = maxInt(iconWidth, runewidth.StringWidth(w.iconFor(s)))
+		detailWidth = maxInt(detailWidth, runewidth.StringWidth(s.Detail))
+	}
+	return textWidth, iconWidth, minInt(detailWidth, maxDetailWidth)
+}
+
+// rowWidth is the width a row needs to show the item and its columns untruncated.
+func rowWidth(displayWidth int, iconWidth int, detailWidth int) int {
+	ret := 2 + displayWidth
+	if iconWidth > 0 {
+		ret += iconWidth + 1
+	}
+	if detailWidth > 0 {
+		ret += 2 + detailWidth
+	}
+	return ret
+}
+
 func (w *Widget) RenderPopup(styles Styles, layout OverlayLayout) string {
 	if layout.VisibleRows <= 0 || layout.ContentWidth <= 0 {
 		return ""
@@ -25,21 +63,141 @@ func (w *Widget) RenderPopup(styles Styles, layout OverlayLayout) string {
 		return ""
 	}
 
+	maxTextWidth, iconWidth, detailWidth := w.itemColumns(suggestions)
+	iconCol := 0
+	if iconWidth > 0 {
+		iconCol = iconWidth + 1
+	}
+	// the detail column gives way first when the popup is narrow
+	detailCol := 0
+	if detailWidth > 0 {
+		detailCol = minInt(detailWidth+2, layout.ContentWidth-2-iconCol-minInt(maxTextWidth, minItemWidth))
+		if detailCol < 3 {
+			detailCol = 0
+		}
+	}
+	textWidth := maxInt(0, layout.ContentWidth-2-iconCol-detailCol)
+
 	start := clampInt(w.scrollTop, 0, maxInt(0, len(suggestions)-1))
 	end := minInt(len(suggestions), start+layout.VisibleRows)
 	lines := make([]string, 0, layout.VisibleRows)
 	for i := start; i < end; i++ {
-		itemText := "  " + suggestions[i].DisplayText
+		s := suggestions[i]
+		prefix := "  "
 		itemStyle := styles.Item
 		if i == w.selection {
 			itemStyle = styles.Selected
-			itemText = "› " + suggestions[i].DisplayText
+			prefix = "› "
+		}
+
+		var row strings.Builder
+		if iconCol > 0 {
+			row.WriteString(itemStyle.Render(prefix))
+			row.WriteString(styles.Icon.Inherit(itemStyle).Render(padRight(w.iconFor(s), iconWidth)))
+			prefix = " "
 		}
-		itemText = runewidth.Truncate(itemText, layout.ContentWidth, "")
-		if delta := layout.ContentWidth - runewidth.StringWidth(itemText); delta > 0 {
-			itemText += strings.Repeat(" ", delta)
+		text := runewidth.Truncate(s.DisplayText, textWidth, "")
+		if len(s.Submatches) == 0 {
+			row.WriteString(itemStyle.Render(prefix + padRight(text, textWidth)))
+		} else {
+			row.WriteString(itemStyle.Render(prefix))
+			row.WriteString(renderMatches(text, s.Submatches, itemStyle, styles.Match.Inherit(itemStyle)))
+			row.WriteString(itemStyle.Render(padRight("", textWidth-runewidth.StringWidth(text))))
 		}
-		lines = append(lines, itemStyle.Render(itemText))
+		if detailCol > 0 {
+			detail := runewidth.Truncate(s.Detail, detailCol-2, "…")
+			detail = strings.Repeat(" ", detailCol-runewidth.StringWidth(detail)) + detail
+			row.WriteString(styles.Detail.Inherit(itemStyle).Render(detail))
+		}
+		lines = append(lines, row.String())
 	}
 	return w.PopupStyle(styles.Popup).Width(layout.PopupWidth).Render(strings.Join(lines, "\n"))
 }
+
+// selectedSuggestion returns the highlighted suggestion, if any.
+func (w *Widget) selectedSuggestion() (autocomplete.Suggestion, bool) {
+	suggestions := w.lastResult.Suggestions
+	if !w.visible || w.selection < 0 || w.selection >= len(suggestions) {
+		return autocomplete.Suggestion{}, false
+	}
+	return suggestions[w.selection], true
+}
+
+// documentationLines wraps the selected suggestion's detail and documentation to width.
+func (w *Widget) documentationLines(width int) []string {
+	s, ok := w.selectedSuggestion()
+	if !ok || strings.TrimSpace(s.Documentation) == "" || width <= 0 {
+		return nil
+	}
+	var lines []string
+	if s.Detail != "" {
+		lines = append(lines, strings.Split(wordwrap.String(s.Detail, width), "\n")...)
+		lines = append(lines, "")
+	}
+	doc := wordwrap.String(strings.TrimSpace(s.Documentation), width)
+	for _, line := range strings.Split(doc, "\n") {
+		lines = append(lines, runewidth.Truncate(line, width, "…"))
+	}
+	return lines
+}
+
+// RenderDocumentation renders the documentation pane of the selected suggestion.
+func (w *Widget) RenderDocumentation(styles Styles, layout DocumentationLayout) string {
+	style := w.documentationStyle(styles)
+	innerWidth := layout.Width - style.GetHorizontalFrameSize()
+	rows := layout.Height - style.GetVerticalFrameSize()
+	lines := w.documentationLines(innerWidth)
+	if len(lines) == 0 || rows <= 0 {
+		return ""
+	}
+	if len(lines) > rows {
+		lines = lines[:rows]
+		lines[rows-1] = runewidth.Truncate(lines[rows-1]+" …", innerWidth, "…")
+	}
+	s, _ := w.selectedSuggestion()
+	if s.Detail != "" {
+		// the first rows hold the detail; style them like the popup's detail column
+		detailRows := len(strings.Split(wordwrap.String(s.Detail, innerWidth), "\n"))
+		for i := 0; i < minInt(detailRows, len(lines)); i++ {
+			lines[i] = styles.Detail.Render(lines[i])
+		}
+	}
+	return style.Width(layout.Width - style.GetHorizontalBorderSize()).Render(strings.Join(lines, "\n"))
+}
+
+func (w *Widget) documentationStyle(styles Styles) lipgloss.Style {
+	style := styles.Documentation
+	if style.GetHorizontalFrameSize() == 0 && style.GetVerticalFrameSize() == 0 {
+		style = styles.Popup
+	}
+	return w.PopupStyle(style)
+}
+
+// renderMatches renders text with the runes covered by spans in the match style.
+func renderMatches(text string, spans []autocomplete.Span, base lipgloss.Style, match lipgloss.Style) string {
+	runes := []rune(text)
+	var b strings.Builder
+	pos := 0
+	for _, span := range spans {
+		start := clampInt(span.Start, pos, len(runes))
+		end := clampInt(span.End, start, len(runes))
+		if start > pos {
+			b.WriteString(base.Render(string(runes[pos:start])))
+		}
+		if end > start {
+			b.WriteString(match.Render(string(runes[start:end])))
+		}
+		pos = end
+	}
+	if pos < len(runes) {
+		b.WriteString(base.Render(string(runes[pos:])))
+	}
+	return b.String()
+}
+
+func padRight(s string, width int) string {
+	if delta := width - runewidth.StringWidth(s); delta > 0 {
+		return s + strings.Repeat(" ", delta)
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+package suggest
+
+import (
+	"sort"
+	"strings"
+)
+
+// SnippetStop is a tab stop of an expanded snippet: the byte range [Start, End) of its placeholder
+// text. Index 0 is the final cursor position.
+type SnippetStop struct {
+	Index int
+	Start int
+	End   int
+}
+
+// ParseSnippet expands a snippet written in the usual `$1`, `${1:placeholder}`, `$0` syntax and
+// returns the plain text with its tab stops in visiting order, the final stop ($0, or the end of
+// the text when absent) last. Placeholders may nest; `\$`, `\}` and `\\` escape. When an index
+// appears more than once, its first occurrence is the tab stop.
+func ParseSnippet(snippet string) (string, []SnippetStop) {
+	p := &snippetParser{src: snippet, stops: map[int]SnippetStop{}}
+	p.parse(false)
+	text := p.out.String()
+
+	final, ok := p.stops[0]
+	if !ok {
+		final = SnippetStop{Index: 0, Start: len(text), End: len(text)}
+	}
+	delete(p.stops, 0)
+	stops := make([]SnippetStop, 0, len(p.stops)+1)
+	for _, stop := range p.stops {
+		stops = append(stops, stop)
+	}
+	sort.Slice(stops, func(i, j int) bool { return stops[i].Index < stops[j].Index })
+	return text, append(stops, final)
+}
+
+type snippetParser struct {
+	src   string
+	pos   int
+	out   strings.Builder
+	stops map[int]SnippetStop
+}
+
+func (p *snippetParser) parse(inPlaceholder bool) {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src) && strings.IndexByte(`$}\`, p.src[p.pos+1]) >= 0:
+			p.out.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		case c == '}' && inPlaceholder:
+			return
+		case c == '$' && p.tabStop():
+		default:
+			p.out.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// tabStop parses `$N`, `${N}` or `${N:placeholder}` at the current position. It returns false,
+// consuming nothing, when the `$` does not start a tab stop.
+func (p *snippetParser) tabStop() bool {
+	i := p.pos + 1
+	braced := i < len(p.src) && p.src[i] == '{'
+	if braced {
+		i++
+	}
+	digits := i
+	for i < len(p.src) && p.src[i] >= '0' && p.src[i] <= '9' {
+		i++
+	}
+	if i == digits {
+		return false
+	}
+	index := 0
+	for _, d := range p.src[digits:i] {
+		index = index*10 + int(d-'0')
+	}
+
+	start := p.out.Len()
+	switch {
+	case !braced:
+		p.pos = i
+	case i < len(p.src) && p.src[i] == '}':
+		p.pos = i + 1
+	case i < len(p.src) && p.src[i] == ':':
+		p.pos = i + 1
+		p.parse(true)
+		if p.pos < len(p.src) {
+			p.pos++ // closing brace
+		}
+	default:
+		return false
+	}
+	if _, ok := p.stops[index]; !ok {
+		p.stops[index] = SnippetStop{Index: index, Start: start, End: p.out.Len()}
+	}
+	return true
+}
+
+// snippetSession tracks the tab stops of an applied snippet while the user edits it. Offsets are
+// absolute byte offsets into value, the buffer content the session last saw.
+type snippetSession struct {
+	stops   []SnippetStop
+	current int
+	value   string
+	// fresh is set while the current placeholder text is untouched; typing replaces it
+	fresh bool
+}
+
+// SnippetActive reports whether an applied snippet still has tab stops to visit.
+func (w *Widget) SnippetActive() bool { return w.snippet != nil }
+
+// EndSnippet leaves snippet navigation, keeping the text as is.
+func (w *Widget) EndSnippet() { w.snippet = nil }
+
+// NextSnippetStop moves the cursor to the next tab stop. Reaching the final stop ends the snippet.
+// It returns false, ending the snippet, if the buffer was changed behind the widget's back.
+func (w *Widget) NextSnippetStop(buffer Buffer) bool {
+	return w.moveSnippetStop(buffer, 1)
+}
+
+// PrevSnippetStop moves the cursor back to the previous tab stop.
+func (w *Widget) PrevSnippetStop(buffer Buffer) bool {
+	return w.moveSnippetStop(buffer, -1)
+}
+
+func (w *Widget) moveSnippetStop(buffer Buffer, delta int) bool {
+	s := w.snippet
+	if s == nil {
+		return false
+	}
+	if buffer.Value() != s.value {
+		w.snippet = nil
+		return false
+	}
+	s.current = clampInt(s.current+delta, 0, len(s.stops)-1)
+	w.enterSnippetStop(buffer)
+	return true
+}
+
+func (w *Widget) enterSnippetStop(buffer Buffer) {
+	s := w.snippet
+	stop := s.stops[s.current]
+	buffer.SetCursorByte(stop.End)
+	s.fresh = stop.End > stop.Start
+	if s.current == len(s.stops)-1 {
+		w.snippet = nil
+	}
+}
+
+// ClearPlaceholder deletes the untouched placeholder text of the current tab stop, so that typing
+// replaces it. It returns true when text was removed.
+func (w *Widget) ClearPlaceholder(buffer Buffer) bool {
+	s := w.snippet
+	if s == nil || !s.fresh {
+		return false
+	}
+	s.fresh = false
+	stop := s.stops[s.current]
+	if buffer.Value() != s.value || stop.End <= stop.Start {
+		return false
+	}
+	value := s.value[:stop.Start] + s.value[stop.End:]
+	for i := range s.stops {
+		// placeholders nested in the cleared one go away with it
+		if i != s.current && s.stops[i].Start >= stop.Start && s.stops[i].Start < stop.End {
+			s.stops[i].Start, s.stops[i].End = stop.Start, stop.Start
+		}
+	}
+	s.shiftStops(stop.End, stop.Start-stop.End)
+	s.stops[s.current].End = stop.Start
+	s.value = value
+	buffer.SetValue(value)
+	buffer.SetCursorByte(stop.Start)
+	return true
+}
+
+// trackSnippetEdit adjusts the tab stops to an edit of the buffer. Edits inside the current
+// placeholder grow or shrink it; any other edit ends the snippet.
+func (w *Widget) trackSnippetEdit(value string, cursor int) {
+	s := w.snippet
+	if s == nil || value == s.value {
+		return
+	}
+	s.fresh = false
+	old := s.value
+	delta := len(value) - len(old)
+
+	// anchor the edit at the cursor: an insertion ends there, a deletion starts there
+	prefix := commonPrefixLen(old, value)
+	prefix = minInt(prefix, maxInt(0, cursor-maxInt(0, delta)))
+	suffix := minInt(commonSuffixLen(old, value), minInt(len(old), len(value))-prefix)
+	editEnd := len(old) - suffix
+
+	stop := s.stops[s.current]
+	if prefix < stop.Start || editEnd > stop.End {
+		w.snippet = nil
+		return
+	}
+	s.shiftStops(stop.End, delta)
+	s.stops[s.current].End = stop.End + delta
+	s.value = value
+}
+
+// shiftStops moves the other stops starting at or after offset by delta bytes.
+func (s *snippetSession) shiftStops(offset int, delta int) {
+	for i := range s.stops {
+		if i != s.current && s.stops[i].Start >= offset {
+			s.stops[i].Start += delta
+			s.stops[i].End += delta
+		}
+	}
+}
+
+func commonPrefixLen(a string, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func commonSuffixLen(a string, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
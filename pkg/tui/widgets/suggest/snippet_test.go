@@ -0,0 +1,90 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSnippet(t *testing.T) {
+	text, stops := ParseSnippet("concat(${1:a}, ${2:b})$0")
+	assert.Equal(t, "concat(a, b)", text)
+	assert.Equal(t, []SnippetStop{
+		{Index: 1, Start: 7, End: 8},
+		{Index: 2, Start: 10, End: 11},
+		{Index: 0, Start: 12, End: 12},
+	}, stops)
+
+	// nested placeholders, escapes, out-of-order indexes and an implicit final stop
+	text, stops = ParseSnippet(`${2:f(${1:x})} \$1 costs \${3}`)
+	assert.Equal(t, "f(x) $1 costs ${3}", text)
+	assert.Equal(t, []SnippetStop{
+		{Index: 1, Start: 2, End: 3},
+		{Index: 2, Start: 0, End: 4},
+		{Index: 0, Start: 18, End: 18},
+	}, stops)
+
+	text, stops = ParseSnippet("price: $ ${x}")
+	assert.Equal(t, "price: $ ${x}", text)
+	assert.Equal(t, []SnippetStop{{Index: 0, Start: 13, End: 13}}, stops)
+}
+
+func TestSnippetApplyAndNavigate(t *testing.T) {
+	w := newTestWidget(&fakeProvider{})
+	w.SetVisible(true)
+	w.SetReplaceFrom(0)
+	w.SetReplaceTo(3)
+	w.SetLastResult(Result{Show: true, Suggestions: []autocomplete.Suggestion{
+		{Id: "1", Value: "concat(${1:a}, ${2:b})$0", DisplayText: "concat", Snippet: true},
+	}})
+	buffer := &fakeBuffer{value: "con + 1", cursor: 3}
+
+	require.True(t, w.HandleNavigation(ActionAccept, buffer))
+	assert.Equal(t, "concat(a, b) + 1", buffer.value)
+	assert.Equal(t, 8, buffer.cursor, "cursor sits on the first placeholder")
+	require.True(t, w.SnippetActive())
+
+	// typing on an untouched placeholder replaces it
+	require.True(t, w.ClearPlaceholder(buffer))
+	assert.Equal(t, "concat(, b) + 1", buffer.value)
+	prev := buffer.value
+	buffer.value, buffer.cursor = "concat(xy, b) + 1", 9
+	w.OnBufferChanged(prev, 7, buffer.value, buffer.cursor)
+	require.True(t, w.SnippetActive())
+
+	require.True(t, w.NextSnippetStop(buffer))
+	assert.Equal(t, 12, buffer.cursor)
+	require.True(t, w.PrevSnippetStop(buffer))
+	assert.Equal(t, 9, buffer.cursor, "the first stop grew with the typed text")
+	require.True(t, w.NextSnippetStop(buffer))
+
+	// the final stop ends the snippet
+	require.True(t, w.NextSnippetStop(buffer))
+	assert.Equal(t, 13, buffer.cursor)
+	assert.False(t, w.SnippetActive())
+	assert.False(t, w.NextSnippetStop(buffer))
+}
+
+func TestSnippetEndsOnEditOutsidePlaceholder(t *testing.T) {
+	w := newTestWidget(&fakeProvider{})
+	w.SetVisible(true)
+	w.SetLastResult(Result{Show: true, Suggestions: []autocomplete.Suggestion{
+		{Id: "1", Value: "f(${1:x})", DisplayText: "f", Snippet: true},
+	}})
+	buffer := &fakeBuffer{}
+	w.ApplySelected(buffer)
+	require.True(t, w.SnippetActive())
+
+	w.OnBufferChanged("f(x)", 3, "f(x)!", 5)
+	assert.False(t, w.SnippetActive())
+
+	// a buffer replaced without notifying the widget ends the snippet on the next jump
+	w.SetVisible(true)
+	w.ApplySelected(buffer)
+	require.True(t, w.SnippetActive())
+	buffer.value = "other"
+	assert.False(t, w.NextSnippetStop(buffer))
+	assert.False(t, w.SnippetActive())
+}
@@ -66,6 +66,14 @@ type Config struct {
 	NoBorder       bool
 	Placement      Placement
 	HorizontalGrow HorizontalGrow
+	// Icons maps suggestion kinds to the icon shown before the item; nil uses DefaultIcons.
+	Icons map[autocomplete.SuggestionKind]string
+	// ClientFilter keeps the provider's last result and fuzzy-filters it locally while the user
+	// types inside the same replace range; the provider is asked again once the range changes.
+	ClientFilter bool
+	// DocumentationWidth is the width of the pane showing the selected item's documentation;
+	// 0 hides the pane.
+	DocumentationWidth int
 }
 
 type DebounceMsg struct {
@@ -84,12 +92,46 @@ type OverlayLayout struct {
 	PopupWidth   int
 	VisibleRows  int
 	ContentWidth int
+	// Above is true when the popup sits above the input row.
+	Above bool
+}
+
+// DocumentationLayout places the documentation pane next to the popup.
+type DocumentationLayout struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
 }
 
 type Styles struct {
 	Item     lipgloss.Style
 	Selected lipgloss.Style
 	Popup    lipgloss.Style
+	// Match styles the runes of an item matched by the client-side filter.
+	Match lipgloss.Style
+	// Icon and Detail style the kind icon and the right-aligned detail column.
+	Icon   lipgloss.Style
+	Detail lipgloss.Style
+	// Documentation styles the documentation pane; it falls back to Popup when unset.
+	Documentation lipgloss.Style
+}
+
+// DefaultIcons returns single-cell icons for the built-in suggestion kinds.
+func DefaultIcons() map[autocomplete.SuggestionKind]string {
+	return map[autocomplete.SuggestionKind]string{
+		autocomplete.KindFunction:  "ƒ",
+		autocomplete.KindMethod:    "m",
+		autocomplete.KindVariable:  "v",
+		autocomplete.KindConstant:  "c",
+		autocomplete.KindProperty:  "p",
+		autocomplete.KindType:      "t",
+		autocomplete.KindKeyword:   "k",
+		autocomplete.KindModule:    "M",
+		autocomplete.KindFile:      "F",
+		autocomplete.KindDirectory: "D",
+		autocomplete.KindSnippet:   "s",
+	}
 }
 
 type Action int
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/go-go-golems/bobatea/pkg/autocomplete"
 	"github.com/go-go-golems/bobatea/pkg/tui/asyncprovider"
 )
 
@@ -33,15 +34,32 @@ type Widget struct {
 	noBorder   bool
 	placement  Placement
 	horizontal HorizontalGrow
+	icons      map[autocomplete.SuggestionKind]string
+	docWidth   int
+
+	snippet *snippetSession
+
+	clientFilter bool
+	// base is the unfiltered provider result that client-side filtering narrows down
+	base      Result
+	baseInput string
+	baseFrom  int
+	baseTo    int
+	hasBase   bool
 
 	lastResult   Result
 	lastError    error
 	lastReqID    uint64
 	lastReqKind  Reason
 	lastInputLen int
+	lastInput    string
 }
 
 func New(provider Provider, cfg Config) *Widget {
+	icons := cfg.Icons
+	if icons == nil {
+		icons = DefaultIcons()
+	}
 	return &Widget{
 		provider:   provider,
 		debounce:   cfg.Debounce,
@@ -57,6 +75,10 @@ func New(provider Provider, cfg Config) *Widget {
 		noBorder:   cfg.NoBorder,
 		placement:  cfg.Placement,
 		horizontal: cfg.HorizontalGrow,
+		icons:      icons,
+		docWidth:   cfg.DocumentationWidth,
+
+		clientFilter: cfg.ClientFilter,
 	}
 }
 
@@ -106,13 +128,26 @@ func (w *Widget) LastError() error                            { return w.lastErr
 func (w *Widget) LastRequestID() uint64                       { return w.lastReqID }
 func (w *Widget) LastRequestReason() Reason                   { return w.lastReqKind }
 
+func (w *Widget) Icons() map[autocomplete.SuggestionKind]string         { return w.icons }
+func (w *Widget) SetIcons(icons map[autocomplete.SuggestionKind]string) { w.icons = icons }
+func (w *Widget) ClientFilter() bool                                    { return w.clientFilter }
+func (w *Widget) SetClientFilter(enabled bool)                          { w.clientFilter = enabled }
+func (w *Widget) DocumentationWidth() int                               { return w.docWidth }
+func (w *Widget) SetDocumentationWidth(width int)                       { w.docWidth = width }
+
 func (w *Widget) OnBufferChanged(prevValue string, prevCursor int, value string, cursor int) tea.Cmd {
+	w.trackSnippetEdit(value, cursor)
 	if w.provider == nil {
 		return nil
 	}
 	if prevValue == value && prevCursor == cursor {
 		return nil
 	}
+	if w.clientFilter && w.refilter(value, cursor) {
+		// drop pending debounces and responses computed for older input
+		w.reqSeq++
+		return nil
+	}
 
 	w.reqSeq++
 	reqID := w.reqSeq
@@ -138,6 +173,7 @@ func (w *Widget) HandleDebounce(ctx context.Context, msg DebounceMsg, value stri
 	w.lastReqID = req.RequestID
 	w.lastReqKind = req.Reason
 	w.lastInputLen = len(req.Input)
+	w.lastInput = req.Input
 	return w.CommandForRequest(ctx, req)
 }
 
@@ -156,6 +192,7 @@ func (w *Widget) TriggerShortcut(ctx context.Context, value string, cursor int,
 	w.lastReqID = req.RequestID
 	w.lastReqKind = req.Reason
 	w.lastInputLen = len(req.Input)
+	w.lastInput = req.Input
 	return w.CommandForRequest(ctx, req)
 }
 
@@ -169,6 +206,7 @@ func (w *Widget) CommandForRequest(ctx context.Context, req Request) tea.Cmd {
 	w.lastReqID = req.RequestID
 	w.lastReqKind = req.Reason
 	w.lastInputLen = len(req.Input)
+	w.lastInput = req.Input
 
 	return func() tea.Msg {
 		result, err := asyncprovider.Run(
@@ -208,6 +246,13 @@ func (w *Widget) HandleResult(msg ResultMsg) {
 	w.visibleRows = 0
 	w.replaceFrom = clampInt(msg.Result.ReplaceFrom, 0, w.lastInputLen)
 	w.replaceTo = clampInt(msg.Result.ReplaceTo, w.replaceFrom, w.lastInputLen)
+	if w.clientFilter {
+		w.base = msg.Result
+		w.baseInput = w.lastInput
+		w.baseFrom = w.replaceFrom
+		w.baseTo = w.replaceTo
+		w.hasBase = true
+	}
 	w.ensureSelectionVisible()
 }
 
@@ -267,13 +312,31 @@ func (w *Widget) ApplySelected(buffer Buffer) {
 	input := buffer.Value()
 	from := clampInt(w.replaceFrom, 0, len(input))
 	to := clampInt(w.replaceTo, from, len(input))
-	newInput := input[:from] + selected.Value + input[to:]
+	text := selected.Value
+	var stops []SnippetStop
+	if selected.Snippet {
+		text, stops = ParseSnippet(selected.Value)
+	}
+	newInput := input[:from] + text + input[to:]
 	buffer.SetValue(newInput)
-	buffer.SetCursorByte(from + len(selected.Value))
+	buffer.SetCursorByte(from + len(text))
 	w.Hide()
+
+	w.snippet = nil
+	if len(stops) > 0 {
+		for i := range stops {
+			stops[i].Start += from
+			stops[i].End += from
+		}
+		w.snippet = &snippetSession{stops: stops, value: newInput}
+		w.enterSnippetStop(buffer)
+	}
 }
 
 func (w *Widget) Hide() {
+	w.base = Result{}
+	w.baseInput = ""
+	w.hasBase = false
 	w.visible = false
 	w.selection = 0
 	w.replaceFrom = 0
@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/go-go-golems/bobatea/pkg/autocomplete"
+	"github.com/mattn/go-runewidth"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -299,3 +300,139 @@ func TestNoBorderPopupStyleHasNoFrame(t *testing.T) {
 	assert.Equal(t, 0, noBorder.GetHorizontalFrameSize())
 	assert.Equal(t, 0, noBorder.GetVerticalFrameSize())
 }
+
+func richTestResult() Result {
+	return Result{
+		Show: true,
+		Suggestions: []autocomplete.Suggestion{
+			{Id: "1", Value: "concat", DisplayText: "concat", Kind: autocomplete.KindFunction, Detail: "(a, b) -> string",
+				Documentation: "Joins two strings."},
+			{Id: "2", Value: "const", DisplayText: "const", Kind: autocomplete.KindKeyword},
+			{Id: "3", Value: "count", DisplayText: "count", Icon: "#", Detail: "number"},
+		},
+	}
+}
+
+func TestRenderPopupShowsIconsAndDetails(t *testing.T) {
+	w := newTestWidget(&fakeProvider{})
+	w.SetVisible(true)
+	w.SetLastResult(richTestResult())
+
+	style := lipgloss.NewStyle().Border(lipgloss.RoundedBorder())
+	layout, ok := w.ComputeOverlayLayout(80, 24, 1, 1, "> ", "co", 2, style)
+	require.True(t, ok)
+	// prefix, icon column, display text and the detail column all fit
+	assert.Equal(t, runewidth.StringWidth("› ƒ concat  (a, b) -> string"), layout.ContentWidth)
+
+	popup := w.RenderPopup(Styles{Popup: style}, layout)
+	lines := strings.Split(popup, "\n")
+	require.Len(t, lines, 5)
+	assert.Contains(t, lines[1], "› ƒ concat")
+	assert.True(t, strings.HasSuffix(strings.TrimRight(strings.TrimSuffix(lines[1], "│"), " "), "(a, b) -> string"))
+	assert.Contains(t, lines[2], "  k const")
+	assert.Contains(t, lines[3], "  # count")
+	assert.Contains(t, lines[3], "number")
+
+	// a narrow popup truncates the detail before the item
+	narrow := w.RenderPopup(Styles{Popup: style}, OverlayLayout{PopupWidth: 20, VisibleRows: 3, ContentWidth: 18})
+	assert.Contains(t, narrow, "concat")
+	assert.Contains(t, narrow, "…")
+}
+
+func TestDocumentationPaneFollowsSelection(t *testing.T) {
+	w := newTestWidget(&fakeProvider{})
+	w.SetDocumentationWidth(30)
+	w.SetVisible(true)
+	w.SetLastResult(richTestResult())
+
+	styles := Styles{Popup: lipgloss.NewStyle().Border(lipgloss.RoundedBorder())}
+	layout, ok := w.ComputeOverlayLayout(80, 24, 1, 1, "> ", "co", 2, styles.Popup)
+	require.True(t, ok)
+	popup := w.RenderPopup(styles, layout)
+
+	docLayout, ok := w.ComputeDocumentationLayout(80, 24, layout, popup, styles)
+	require.True(t, ok)
+	assert.Equal(t, layout.PopupX+lipgloss.Width(popup), docLayout.X)
+	assert.Equal(t, layout.PopupY, docLayout.Y)
+	assert.Equal(t, 30, docLayout.Width)
+	doc := w.RenderDocumentation(styles, docLayout)
+	assert.Contains(t, doc, "(a, b) -> string")
+	assert.Contains(t, doc, "Joins two strings.")
+	assert.Equal(t, 30, lipgloss.Width(doc))
+
+	// no room on the right: the pane moves to the left of the popup
+	layout.PopupX = 45
+	docLayout, ok = w.ComputeDocumentationLayout(80, 24, layout, popup, styles)
+	require.True(t, ok)
+	assert.Equal(t, 15, docLayout.X)
+
+	// items without documentation have no pane
+	w.SetSelection(1)
+	_, ok = w.ComputeDocumentationLayout(80, 24, layout, popup, styles)
+	assert.False(t, ok)
+
+	w.SetSelection(0)
+	w.SetDocumentationWidth(0)
+	_, ok = w.ComputeDocumentationLayout(80, 24, layout, popup, styles)
+	assert.False(t, ok)
+}
+
+func TestFilterSuggestionsRanksFuzzyMatches(t *testing.T) {
+	suggestions := []autocomplete.Suggestion{
+		{Id: "1", Value: "console", DisplayText: "console"},
+		{Id: "2", Value: "concat", DisplayText: "concat"},
+		{Id: "3", Value: "count", DisplayText: "count"},
+	}
+	assert.Equal(t, suggestions, FilterSuggestions(suggestions, ""))
+
+	filtered := FilterSuggestions(suggestions, "cnt")
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "count", filtered[0].Value)
+	assert.Equal(t, []autocomplete.Span{{Start: 0, End: 1}, {Start: 3, End: 5}}, filtered[0].Submatches)
+	assert.Equal(t, "concat", filtered[1].Value)
+	assert.Nil(t, suggestions[2].Submatches, "the input slice is not modified")
+}
+
+func TestClientFilterNarrowsLastResultLocally(t *testing.T) {
+	provider := &fakeProvider{result: Result{
+		Show: true,
+		Suggestions: []autocomplete.Suggestion{
+			{Id: "1", Value: "console", DisplayText: "console"},
+			{Id: "2", Value: "concat", DisplayText: "concat"},
+			{Id: "3", Value: "count", DisplayText: "count"},
+		},
+		ReplaceFrom: 2,
+		ReplaceTo:   4,
+	}}
+	w := newTestWidget(provider)
+	w.SetClientFilter(true)
+
+	cmd := w.TriggerShortcut(context.Background(), "x co", 4, "tab")
+	w.HandleResult(cmd().(ResultMsg))
+	require.True(t, w.Visible())
+	require.Len(t, provider.requests, 1)
+
+	// typing inside the replace range filters without asking the provider
+	require.Nil(t, w.OnBufferChanged("x co", 4, "x cou", 5))
+	require.True(t, w.Visible())
+	suggestions := w.LastResult().Suggestions
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "count", suggestions[0].Value)
+	assert.Equal(t, []autocomplete.Span{{Start: 0, End: 3}}, suggestions[0].Submatches)
+	assert.Equal(t, 5, w.ReplaceTo())
+
+	// no match hides the popup, deleting brings the matches back
+	require.Nil(t, w.OnBufferChanged("x cou", 5, "x coux", 6))
+	assert.False(t, w.Visible())
+	require.Nil(t, w.OnBufferChanged("x coux", 6, "x c", 3))
+	assert.True(t, w.Visible())
+	assert.Len(t, w.LastResult().Suggestions, 3)
+
+	// leaving the token goes back to the provider
+	cmd = w.OnBufferChanged("x c", 3, "x c ", 4)
+	require.NotNil(t, cmd)
+	_, ok := cmd().(DebounceMsg)
+	require.True(t, ok)
+	require.NotNil(t, w.OnBufferChanged("x c ", 4, "y c", 3))
+	require.Len(t, provider.requests, 1)
+}